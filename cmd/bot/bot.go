@@ -3,7 +3,26 @@
 package bot
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"tg-bot/internal/antispam"
+	"tg-bot/internal/config"
+	"tg-bot/internal/content"
+	"tg-bot/internal/docs"
 	"tg-bot/internal/handlers"
+	"tg-bot/internal/modlog"
+	"tg-bot/internal/quiz"
+	"tg-bot/internal/releases"
+	"tg-bot/internal/roadmap"
+	"tg-bot/internal/sandbox"
+	"tg-bot/internal/storage"
 	"tg-bot/internal/webhook"
 	"tg-bot/pkg/logger"
 
@@ -13,10 +32,27 @@ import (
 // Config interfeysi bot uchun zarur sozlamalarni belgilaydi
 // Bu interfeys orqali turli manbalardagi konfiguratsiyalarni ishlatish mumkin
 type Config interface {
+	handlers.Config
 	// GetTelegramToken Telegram bot tokenini qaytaradi
 	GetTelegramToken() string
 	// IsWebhookMode botning webhook rejimida ishlashini tekshiradi
 	IsWebhookMode() bool
+	// StorageDriver ombor turini qaytaradi (bolt yoki memory)
+	StorageDriver() string
+	// StoragePath ombor fayli yo'lini qaytaradi
+	StoragePath() string
+	// GetShutdownTimeout to'xtatishda ishlayotgan handlerlarni kutish muddatini qaytaradi
+	GetShutdownTimeout() time.Duration
+	// DispatcherSettings yangilanishlarni taqsimlash sozlamalarini qaytaradi
+	DispatcherSettings() config.DispatcherSettings
+	// ReleasesSettings Go relizlari ma'lumotlari sozlamalarini qaytaradi
+	ReleasesSettings() config.ReleasesSettings
+	// DocsSettings standart kutubxona hujjatlari sozlamalarini qaytaradi
+	DocsSettings() config.DocsSettings
+	// RunSettings /run sandbox sozlamalarini qaytaradi
+	RunSettings() config.RunSettings
+	// RoadmapSettings interaktiv yo'l xaritasi sozlamalarini qaytaradi
+	RoadmapSettings() config.RoadmapSettings
 }
 
 // WebhookConfig webhook rejimini konfiguratsiya qilish uchun interfeys
@@ -26,6 +62,10 @@ type WebhookConfig interface {
 	WebhookURL() string
 	// WebhookPort portini qaytaradi
 	WebhookPort() string
+	// WebhookPath endpoint yo'lini qaytaradi
+	WebhookPath() string
+	// WebhookSecretToken so'rovlarni tekshirish uchun maxfiy kalitni qaytaradi
+	WebhookSecretToken() string
 }
 
 // deleteWebhook mavjud webhook konfiguratsiyasini Telegram serveridan o'chiradi
@@ -50,7 +90,13 @@ func deleteWebhook(bot *tgbotapi.BotAPI, log *logger.Logger) {
 
 // RunBot Telegram botni ishga tushirish va boshqarish uchun asosiy funksiya
 // Bu funksiya botni yaratadi, sozlaydi va yangilanishlarni qabul qilishni boshlaydi
-func RunBot(cfg Config, log *logger.Logger) {
+// SIGINT yoki SIGTERM signali kelganda yoki ctx bekor qilinganda yangilanishlarni qabul qilish to'xtatiladi
+// va ishlayotgan handlerlar sozlamalarda belgilangan muddatgacha kutiladi
+func RunBot(ctx context.Context, cfg Config, log *logger.Logger) {
+	// To'xtatish signallarini ushlash
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Yangi bot namunasini yaratish
 	bot, err := tgbotapi.NewBotAPI(cfg.GetTelegramToken())
 	if err != nil {
@@ -62,8 +108,91 @@ func RunBot(cfg Config, log *logger.Logger) {
 	bot.Debug = true
 	log.Info("Bot muvaffaqiyatli ishga tushirildi:", bot.Self.UserName)
 
+	// Bot holatini saqlash uchun omborni ochish
+	store, err := storage.Open(cfg.StorageDriver(), cfg.StoragePath())
+	if err != nil {
+		log.Error("Ma'lumotlar omborini ochishda xatolik:", err)
+		return
+	}
+	defer store.Close()
+
+	// Bot matnlarini yuklash va tekshirish
+	// Matnlarda xatolik bo'lsa bot ishga tushmaydi, chunki foydalanuvchilarga noto'g'ri javob yuborilishi mumkin
+	texts, err := content.Load(cfg.ContentSettings().Dir)
+	if err != nil {
+		log.Error("Bot matnlarini yuklashda xatolik:", err)
+		return
+	}
+	if err := texts.Require(handlers.ContentKeys...); err != nil {
+		log.Error("Bot matnlari to'liq emas:", err)
+		return
+	}
+	log.Infof("Bot matnlari yuklandi: %d ta", texts.Len())
+	go texts.Watch(ctx, cfg.ContentSettings().ReloadInterval, log)
+
+	// Go relizlari bazasi avval keshdan yuklanadi, so'ng fonda manbadan yangilanib turadi
+	releaseSettings := cfg.ReleasesSettings()
+	releaseDB := releases.New(store, releaseSettings.SourceURL, releaseSettings.HistoryURL, releaseSettings.Timeout)
+	if err := releaseDB.Load(); err != nil {
+		log.Warnf("Relizlar keshini yuklashda xatolik: %v", err)
+	}
+
+	// Hujjatlar indeksi fonda quriladi, tayyor bo'lguncha /doc buni foydalanuvchiga aytadi
+	var docIndex *docs.Index
+	if cfg.DocsSettings().Enabled {
+		docIndex = docs.NewIndex()
+		go buildDocs(docIndex, cfg.DocsSettings().GOROOT, log)
+	}
+
+	// /run faqat sozlamalarda yoqilgan va sandbox bu tizimda ishlay olsa mavjud bo'ladi
+	runner := newRunner(cfg.RunSettings(), log)
+	if runner != nil {
+		go runner.Run(ctx)
+	}
+
+	// Viktorina savollari banki, xatolik bo'lsa viktorina buyruqlari o'chiriladi
+	bank := loadQuiz(cfg.QuizSettings(), log)
+
+	// Interaktiv yo'l xaritasi, yuklanmasa /roadmap faqat umumiy ko'rinishni yuboradi
+	roadmapData := loadRoadmap(cfg.RoadmapSettings(), log)
+
+	// Guruh xabarlari uchun spam filtrlari, sozlamalarda xatolik bo'lsa filtrlar o'chiriladi
+	filters := loadAntispam(cfg.AntispamSettings(), store, log)
+
 	// Bot buyruqlarini ro'yxatdan o'tkazish
-	handlers.RegisterBotCommands(bot, log)
+	commands := handlers.NewCommandHandler(cfg, store, texts, releaseDB, docIndex, runner, bank, roadmapData, filters, log)
+	commands.RegisterBotCommands(bot)
+	commands.OnMemberVerified(func(bot *tgbotapi.BotAPI, chatID int64, user tgbotapi.User) {
+		commands.WelcomeMember(bot, chatID, user, log)
+	})
+	commands.ResumeCaptchas(bot)
+	defer commands.Stop()
+
+	// Har bir yangilanishdan keyin yangi relizlar obuna bo'lgan chatlarga e'lon qilinadi
+	releaseDB.OnRefresh(func() {
+		commands.AnnounceReleases(ctx, bot)
+	})
+	go releaseDB.Run(ctx, releaseSettings.RefreshInterval, log)
+
+	// Kun savoli belgilangan vaqtda guruhlarga yuboriladi
+	go commands.RunDailyQuiz(ctx, bot)
+
+	// Omborda saqlangan uzun tugma ma'lumotlarining muddati o'tganlari o'chirib turiladi
+	go commands.RunCallbackPrune(ctx)
+
+	// Javob kutish muddati o'tgan suhbatlar bekor qilinadi
+	go commands.RunDialogTimeouts(ctx, bot)
+
+	// Handlerlar konteksti signal kelganda emas, balki kutish muddati tugaganda bekor qilinadi,
+	// shunda ishlayotgan handlerlar xabar yuborishni yakunlashga ulguradi
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandlers()
+
+	// Yangilanishlarni chatlar bo'yicha ishchilarga taqsimlovchi dispatcher
+	workers := newDispatcher(cfg.DispatcherSettings(), func(update tgbotapi.Update) {
+		handleUpdate(handlerCtx, bot, commands, store, update, log)
+	}, log)
+	go workers.reportMetrics(ctx, cfg.DispatcherSettings().MetricsInterval)
 
 	// Bot rejimiga qarab ishlash
 	if cfg.IsWebhookMode() {
@@ -74,20 +203,34 @@ func RunBot(cfg Config, log *logger.Logger) {
 			log.Error("Webhook konfiguratsiyasi noto'g'ri")
 			return
 		}
-		runWebhookMode(bot, webhookConfig, log)
+		runWebhookMode(ctx, bot, webhookConfig, workers, store, log)
 	} else {
 		log.Info("Bot polling rejimida ishlamoqda")
 		// Always delete any existing webhook before starting polling mode
 		deleteWebhook(bot, log)
-		runPollingMode(bot, log)
+		runPollingMode(ctx, bot, workers, store, log)
+	}
+
+	// Ishlayotgan handlerlarni kutish
+	log.Info("Bot to'xtatilmoqda, ishlayotgan handlerlar kutilmoqda...")
+	if workers.stop(cfg.GetShutdownTimeout()) {
+		log.Info("Barcha handlerlar yakunlandi")
+	} else {
+		// Telegram tomonida tasdiqlangan bu yangilanishlar keyingi ishga tushirishda qayta olinmaydi
+		log.Warnf("Handlerlar %s ichida yakunlanmadi, ular bekor qilinmoqda, %d ta yangilanish qayta ishlanmay qoldi",
+			cfg.GetShutdownTimeout(), workers.pending())
 	}
 }
 
+// modlogExportPath webhook serveridagi moderatsiya jurnali eksporti yo'li
+const modlogExportPath = "/modlog/export"
+
 // runWebhookMode botni webhook rejimida ishga tushiradi
 // Bu rejim ishlab chiqarish muhiti uchun tavsiya etiladi
-func runWebhookMode(bot *tgbotapi.BotAPI, cfg WebhookConfig, log *logger.Logger) {
+func runWebhookMode(ctx context.Context, bot *tgbotapi.BotAPI, cfg WebhookConfig, workers *dispatcher, store storage.Store, log *logger.Logger) {
 	// Import webhook package and use the implemented Server
 	webhookServer := webhook.NewServer(bot, cfg, log)
+	webhookServer.SetStatsProvider(func() interface{} { return workers.stats() })
 
 	// Setup the webhook server
 	if err := webhookServer.Setup(); err != nil {
@@ -95,6 +238,11 @@ func runWebhookMode(bot *tgbotapi.BotAPI, cfg WebhookConfig, log *logger.Logger)
 		return
 	}
 
+	// Moderatsiya jurnali eksporti faqat kalit sozlangan bo'lsa ochiladi
+	if token := cfg.ModlogSettings().ExportToken; token != "" {
+		webhookServer.Handle(modlogExportPath, modlog.New(store).ExportHandler(token))
+	}
+
 	// Start the webhook server
 	if err := webhookServer.Start(); err != nil {
 		log.Errorf("Webhook serverini ishga tushirishda xatolik: %v", err)
@@ -115,30 +263,227 @@ func runWebhookMode(bot *tgbotapi.BotAPI, cfg WebhookConfig, log *logger.Logger)
 	}
 
 	// Yangilanishlarni qayta ishlash
-	for update := range updates {
-		go handleUpdate(bot, update, log)
+	for {
+		select {
+		case <-ctx.Done():
+			// Yangi so'rovlarni qabul qilishni to'xtatish
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.GetShutdownTimeout())
+			if err := webhookServer.Stop(shutdownCtx); err != nil {
+				log.Errorf("Webhook serverini to'xtatishda xatolik: %v", err)
+			}
+			cancel()
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			workers.dispatch(ctx, update)
+		}
 	}
 }
 
 // runPollingMode botni polling rejimida ishga tushiradi
 // Bu rejim rivojlantirish muhiti uchun tavsiya etiladi
-func runPollingMode(bot *tgbotapi.BotAPI, log *logger.Logger) {
-	// Yangilanishlar konfiguratsiyasini sozlash
-	updateConfig := tgbotapi.NewUpdate(0)
-	updateConfig.Timeout = 60 // Kutish vaqti (sekundlarda)
+func runPollingMode(ctx context.Context, bot *tgbotapi.BotAPI, workers *dispatcher, store storage.Store, log *logger.Logger) {
+	// Oldingi ishga tushirishda qayta ishlangan yangilanishlarni qayta olmaslik uchun saqlangan offsetdan boshlaymiz
+	offsets := newPollingOffset(store, loadPollingOffset(store, log), log)
+	workers.onHandled(func(update tgbotapi.Update) {
+		offsets.handled(update.UpdateID)
+	})
+
+	for {
+		// Har bir so'rov oldingi javobdagi yangilanishlarni Telegram tomonida tasdiqlaydi.
+		// To'xtatilganda yangi so'rov yuborilmaydi, shuning uchun oxirgi javobdagi navbatga qo'yilmagan yangilanishlar
+		// keyingi ishga tushirishda qayta olinadi
+		updates, err := getUpdates(ctx, bot, offsets.next())
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Warnf("Yangilanishlarni olishda xatolik, 3 soniyadan keyin qayta urinamiz: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(3 * time.Second):
+			}
+			continue
+		}
 
-	// Yangilanishlar kanalini olish
-	updates := bot.GetUpdatesChan(updateConfig)
+		for _, update := range updates {
+			if update.UpdateID < offsets.next() {
+				continue
+			}
+			offsets.queued(update.UpdateID)
+			if workers.dispatch(ctx, update) {
+				continue
+			}
+			if ctx.Err() != nil {
+				// Navbatga qo'yilmagan yangilanishlar tasdiqlanmagan, ular keyingi safar shu offsetdan qayta olinadi
+				offsets.cancel(update.UpdateID)
+				return
+			}
+			// drop siyosatida tashlab yuborilgan yangilanish qayta ishlangan hisoblanadi
+			offsets.handled(update.UpdateID)
+		}
+	}
+}
 
-	// Yangilanishlarni qayta ishlash
-	for update := range updates {
-		go handleUpdate(bot, update, log) // Har bir yangilanish uchun alohida go-routineda ishlaymiz
+// pollingTimeout getUpdates so'rovining uzun so'rov (long polling) muddati, sekundlarda
+const pollingTimeout = 60
+
+// getUpdates offsetdan boshlab yangilanishlarni so'raydi, ctx bekor qilinsa javobni kutmaydi
+func getUpdates(ctx context.Context, bot *tgbotapi.BotAPI, offset int) ([]tgbotapi.Update, error) {
+	updateConfig := tgbotapi.NewUpdate(offset)
+	updateConfig.Timeout = pollingTimeout
+
+	type result struct {
+		updates []tgbotapi.Update
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		updates, err := bot.GetUpdates(updateConfig)
+		done <- result{updates, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.updates, r.err
+	}
+}
+
+// Polling offseti saqlanadigan bucket va kalit
+const (
+	stateBucket      = "state"
+	pollingOffsetKey = "polling_offset"
+)
+
+// pollingOffset so'raladigan va omborga saqlanadigan polling offsetlarini kuzatadi
+// Ishchilar turli chatlarni parallel qayta ishlagani uchun saqlanadigan offset hali tugamagan eng kichik yangilanishdan oshmaydi
+type pollingOffset struct {
+	store storage.Store
+	log   *logger.Logger
+
+	mu      sync.Mutex
+	request int          // Keyingi getUpdates so'rovidagi offset
+	pending map[int]bool // Navbatga qo'yilgan, lekin hali qayta ishlanmagan yangilanishlar
+	saved   int          // Omborga oxirgi yozilgan offset
+}
+
+// newPollingOffset saqlangan offsetdan boshlanadigan kuzatuvchini yaratadi
+func newPollingOffset(store storage.Store, offset int, log *logger.Logger) *pollingOffset {
+	return &pollingOffset{store: store, log: log, request: offset, pending: make(map[int]bool), saved: offset}
+}
+
+// next keyingi getUpdates so'rovi uchun offset
+func (p *pollingOffset) next() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.request
+}
+
+// queued yangilanish navbatga qo'yilayotganini belgilaydi
+func (p *pollingOffset) queued(id int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending[id] = true
+	p.request = id + 1
+}
+
+// handled yangilanish qayta ishlanganini belgilaydi va offsetni omborga yozadi
+func (p *pollingOffset) handled(id int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, id)
+	p.save()
+}
+
+// cancel navbatga qo'yilmagan yangilanishni keyingi ishga tushirishda qayta olish uchun qaytaradi
+func (p *pollingOffset) cancel(id int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, id)
+	p.request = id
+	p.save()
+}
+
+// save hali qayta ishlanmagan eng kichik yangilanish offsetini omborga yozadi
+func (p *pollingOffset) save() {
+	offset := p.request
+	for id := range p.pending {
+		if id < offset {
+			offset = id
+		}
+	}
+	if offset == p.saved {
+		return
+	}
+	if err := p.store.Put(stateBucket, pollingOffsetKey, []byte(strconv.Itoa(offset))); err != nil {
+		p.log.Warnf("Polling offsetini saqlashda xatolik: %v", err)
+		return
+	}
+	p.saved = offset
+}
+
+// loadPollingOffset ombordan oxirgi saqlangan polling offsetini o'qiydi
+func loadPollingOffset(store storage.Store, log *logger.Logger) int {
+	data, err := store.Get(stateBucket, pollingOffsetKey)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Warnf("Polling offsetini o'qishda xatolik: %v", err)
+		}
+		return 0
+	}
+
+	offset, err := strconv.Atoi(string(data))
+	if err != nil {
+		log.Warnf("Saqlangan polling offseti noto'g'ri: %v", err)
+		return 0
+	}
+	return offset
+}
+
+// rememberUpdate yangilanishdagi foydalanuvchi va chat ma'lumotlarini omborga yozadi
+// Bu ma'lumotlar keyinchalik sozlamalar, statistika va moderatsiya uchun ishlatiladi
+func rememberUpdate(store storage.Store, update tgbotapi.Update, log *logger.Logger) {
+	now := time.Now()
+
+	if user := update.SentFrom(); user != nil && !user.IsBot {
+		err := store.SaveUser(storage.User{
+			ID:           user.ID,
+			UserName:     user.UserName,
+			FirstName:    user.FirstName,
+			LastName:     user.LastName,
+			LanguageCode: user.LanguageCode,
+			LastSeen:     now,
+		})
+		if err != nil {
+			log.Warnf("Foydalanuvchini saqlashda xatolik: %v", err)
+		}
+	}
+
+	if chat := update.FromChat(); chat != nil {
+		err := store.SaveChat(storage.Chat{
+			ID:       chat.ID,
+			Type:     chat.Type,
+			Title:    chat.Title,
+			UserName: chat.UserName,
+			LastSeen: now,
+		})
+		if err != nil {
+			log.Warnf("Chatni saqlashda xatolik: %v", err)
+		}
 	}
 }
 
 // handleUpdate har bir kiruvchi yangilanishni qayta ishlaydi
 // Bu funksiya xabarlar, buyruqlar va callback so'rovlarni aniqlaydi va ularga javob beradi
-func handleUpdate(bot *tgbotapi.BotAPI, update tgbotapi.Update, log *logger.Logger) {
+func handleUpdate(ctx context.Context, bot *tgbotapi.BotAPI, commands *handlers.CommandHandler, store storage.Store, update tgbotapi.Update, log *logger.Logger) {
+	// Foydalanuvchi va chat haqidagi ma'lumotlarni yangilash
+	rememberUpdate(store, update, log)
+
 	// Yangi a'zo guruhga qo'shilganligini tekshirish
 	if update.Message != nil && update.Message.NewChatMembers != nil && len(update.Message.NewChatMembers) > 0 {
 		for _, newUser := range update.Message.NewChatMembers {
@@ -146,72 +491,164 @@ func handleUpdate(bot *tgbotapi.BotAPI, update tgbotapi.Update, log *logger.Logg
 			if newUser.ID == bot.Self.ID {
 				continue
 			}
+			commands.MemberJoined(update.Message.Chat.ID, newUser)
+
+			// Tekshiruv yoqilgan bo'lsa, avval yangi a'zo bot emasligiga ishonch hosil qilamiz
+			if commands.CaptchaEnabled() {
+				commands.StartCaptcha(bot, update.Message, newUser)
+				continue
+			}
 
 			// Send a simple mention message
-			mentionNewUser(bot, update.Message.Chat.ID, newUser, log)
+			commands.WelcomeMember(bot, update.Message.Chat.ID, newUser, log)
 		}
 		return
 	}
 
+	// Guruh xabarlari spam filtrlari, flood va slow mode cheklovlaridan o'tkaziladi,
+	// o'chirilgan xabar boshqa qayta ishlanmaydi
+	if update.Message != nil && commands.FilterMessage(ctx, bot, update.Message, log) {
+		return
+	}
+	if update.Message != nil && commands.LimitMessage(ctx, bot, update.Message, log) {
+		return
+	}
+
 	// Buyruqlarni qayta ishlash
 	if update.Message != nil && update.Message.IsCommand() {
+		// Buyruqlar Logging middleware orqali log qilinadi
 		command := update.Message.Command()
-		// Only log commands, not regular messages
-		log.Infof("Foydalanuvchi \"%s\" buyrug'ini yubordi", command)
 
 		// Buyruqni tegishli qayta ishlovchiga uzatish
-		if handler := handlers.GetCommandHandler(command); handler != nil {
-			handler(bot, update.Message, log)
+		if handler := commands.GetCommandHandler(command); handler != nil {
+			handler(ctx, bot, update.Message, log)
 		} else {
 			// Agar buyruq ma'lum bo'lmasa, foydalanuvchiga yordam xabarini yuborish
-			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Noma'lum buyruq. Mavjud buyruqlar ro'yxatini ko'rish uchun /help buyrug'ini ishlatib ko'ring")
-			bot.Send(msg)
+			commands.UnknownCommand(bot, update.Message, log)
 		}
 		return
 	}
 
-	// Oddiy xabarlarni qayta ishlash
+	// Oddiy xabarlar foydalanuvchining faol suhbatiga (masalan /setrules savoliga javob) uzatiladi,
+	// suhbatga tegishli bo'lmagan xabarlarga bot javob bermaydi
 	if update.Message != nil {
-		// Remove debug logging and message echoing for regular messages
-		// No need to resend messages that bot receives from groups
+		commands.HandleMessage(ctx, bot, update.Message, log)
 		return
 	}
 
 	// Callback so'rovlarini qayta ishlash (inline klaviaturalar uchun)
 	if update.CallbackQuery != nil {
 		log.Debugf("Callback so'rovi qabul qilindi: %s", update.CallbackQuery.Data)
-		handlers.HandleCallback(bot, update.CallbackQuery, log)
+		commands.HandleCallback(ctx, bot, update.CallbackQuery, log)
+		return
+	}
+
+	// Inline so'rovlar (@bot strings.Cut) hujjatlar indeksidan javob oladi
+	if update.InlineQuery != nil {
+		commands.HandleInlineQuery(ctx, bot, update.InlineQuery, log)
 		return
 	}
 }
 
-// mentionNewUser mentions a new user with a subtle suggestion to check the bot for community info
-// This function is called when a new user joins the group
-func mentionNewUser(bot *tgbotapi.BotAPI, chatID int64, user tgbotapi.User, log *logger.Logger) {
-	// Get user's mention format
-	var userMention string
-	if user.UserName != "" {
-		userMention = "@" + user.UserName
-	} else {
-		userMention = user.FirstName
+// newRunner sozlamalar asosida /run sandbox'ini yaratadi
+// Buyruq o'chirilgan yoki sandbox ishlamaydigan bo'lsa nil qaytariladi
+func newRunner(settings config.RunSettings, log *logger.Logger) *sandbox.Runner {
+	if !settings.Enabled {
+		return nil
+	}
+	runner, err := sandbox.New(sandbox.Config{
+		GoBinary:       settings.GoBinary,
+		WorkDir:        settings.WorkDir,
+		BuildTimeout:   settings.BuildTimeout,
+		RunTimeout:     settings.RunTimeout,
+		MemoryLimit:    int64(settings.MemoryMB) << 20,
+		MaxOutput:      settings.MaxOutput,
+		AllowedImports: settings.AllowedImports,
+		Workers:        settings.Workers,
+		QueueSize:      settings.QueueSize,
+		UserQuota:      settings.UserQuota,
+		QuotaWindow:    settings.QuotaWindow,
+	}, log)
+	if err != nil {
+		log.Warnf("/run o'chirildi, sandbox ishga tushmadi: %v", err)
+		return nil
 	}
+	log.Info("/run sandbox'i tayyor")
+	return runner
+}
 
-	// Create a subtle mention message
-	welcomeMsg := tgbotapi.NewMessage(chatID,
-		"Assalomu alaykum "+userMention+"! Bizni hamjamiyat haqida ko'proq bilish uchun botga murojaat qiling.")
+// loadQuiz sozlamalar asosida viktorina savollari bankini yuklaydi
+// Viktorina o'chirilgan yoki bank yuklanmagan bo'lsa nil qaytariladi
+func loadQuiz(settings config.QuizSettings, log *logger.Logger) *quiz.Bank {
+	if !settings.Enabled {
+		return nil
+	}
+	bank, err := quiz.Load(settings.Bank)
+	if err != nil {
+		log.Warnf("Viktorina o'chirildi, savollar yuklanmadi: %v", err)
+		return nil
+	}
+	log.Infof("Viktorina savollari yuklandi: %d ta", bank.Len())
+	return bank
+}
 
-	// Create an inline keyboard with a Start button
-	startButton := tgbotapi.NewInlineKeyboardButtonURL("Botga tashrif buyirish", "https://t.me/"+bot.Self.UserName+"?start=welcome")
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(startButton),
-	)
+// loadAntispam sozlamalar asosida spam filtrlari zanjirini yaratadi
+// Filtrlar o'chirilgan yoki sozlamalar noto'g'ri bo'lsa nil qaytariladi
+func loadAntispam(settings config.AntispamSettings, store storage.Store, log *logger.Logger) *antispam.Pipeline {
+	if !settings.Enabled {
+		return nil
+	}
+	rules := make([]antispam.Rule, 0, len(settings.Rules))
+	for _, r := range settings.Rules {
+		rules = append(rules, antispam.Rule{Pattern: r.Pattern, Action: r.Action, Reason: r.Reason})
+	}
+	pipeline, err := antispam.New(antispam.Config{
+		AllowDomains:       settings.Links.Allow,
+		DenyDomains:        settings.Links.Deny,
+		LinkAction:         settings.Links.Action,
+		NewcomerMessages:   settings.Newcomers.Messages,
+		NewcomerAction:     settings.Newcomers.Action,
+		ForwardAction:      settings.Forwards.Action,
+		ForwardChannels:    settings.Forwards.Channels,
+		DuplicateChats:     settings.Duplicates.Chats,
+		DuplicateWindow:    settings.Duplicates.Window,
+		DuplicateMinLength: settings.Duplicates.MinLength,
+		DuplicateAction:    settings.Duplicates.Action,
+		Rules:              rules,
+	}, store)
+	if err != nil {
+		log.Warnf("Spam filtrlari o'chirildi, sozlamalarda xatolik: %v", err)
+		return nil
+	}
+	return pipeline
+}
 
-	// Attach the keyboard to the message
-	welcomeMsg.ReplyMarkup = keyboard
+// loadRoadmap sozlamalar asosida interaktiv yo'l xaritasini yuklaydi
+// Fayl ko'rsatilmagan yoki yuklanmagan bo'lsa nil qaytariladi
+func loadRoadmap(settings config.RoadmapSettings, log *logger.Logger) *roadmap.Roadmap {
+	if settings.File == "" {
+		return nil
+	}
+	data, err := roadmap.Load(settings.File)
+	if err != nil {
+		log.Warnf("Interaktiv yo'l xaritasi o'chirildi: %v", err)
+		return nil
+	}
+	log.Infof("Yo'l xaritasi yuklandi: %d ta mavzu", data.Total())
+	return data
+}
 
-	// Send the message
-	_, err := bot.Send(welcomeMsg)
+// buildDocs standart kutubxona hujjatlari indeksini quradi va natijani logga yozadi
+func buildDocs(index *docs.Index, configured string, log *logger.Logger) {
+	goroot, err := docs.GOROOT(configured)
 	if err != nil {
-		log.Error("Error sending mention message to new user:", err)
+		log.Warnf("Hujjatlar indeksi qurilmadi: %v", err)
+		return
+	}
+	took, err := index.Build(goroot)
+	if err != nil {
+		log.Warnf("Hujjatlar indeksi qurilmadi: %v", err)
+		return
 	}
+	log.Infof("Hujjatlar indeksi qurildi: %d ta belgi, %s (%s)", index.Len(), took.Round(time.Millisecond), goroot)
 }
@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"testing"
+
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+)
+
+func TestPollingOffset(t *testing.T) {
+	store := storage.NewMemoryStore()
+	log := logger.New("error")
+	p := newPollingOffset(store, loadPollingOffset(store, log), log)
+
+	for _, id := range []int{1, 2, 3} {
+		p.queued(id)
+	}
+	if got := p.next(); got != 4 {
+		t.Fatalf("next = %d, want 4", got)
+	}
+
+	// Boshqa chatlardagi keyingi yangilanishlar tugasa ham offset tugamagan 1 dan oshmaydi
+	p.handled(2)
+	p.handled(3)
+	if got := loadPollingOffset(store, log); got != 1 {
+		t.Fatalf("saved offset = %d, want 1", got)
+	}
+	p.handled(1)
+	if got := loadPollingOffset(store, log); got != 4 {
+		t.Fatalf("saved offset = %d, want 4", got)
+	}
+
+	// Navbatga qo'yilmagan yangilanish keyingi ishga tushirishda qayta olinadi
+	p.queued(4)
+	p.queued(5)
+	p.cancel(5)
+	if got := p.next(); got != 5 {
+		t.Fatalf("next after cancel = %d, want 5", got)
+	}
+	p.handled(4)
+	if got := loadPollingOffset(store, log); got != 5 {
+		t.Fatalf("saved offset = %d, want 5", got)
+	}
+}
@@ -0,0 +1,232 @@
+package bot
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tg-bot/internal/config"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Navbat to'lganda qo'llaniladigan siyosatlar
+const (
+	policyBlock = "block" // Navbatda joy bo'shashini kutish (qabul qilish sekinlashadi)
+	policyDrop  = "drop"  // Yangilanishni tashlab yuborish
+)
+
+// dispatcher yangilanishlarni cheklangan sondagi ishchilar orasida taqsimlaydi
+// Yangilanishlar chat ID si bo'yicha ishchiga biriktiriladi: bitta chat qat'iy tartibda,
+// turli chatlar esa parallel ravishda qayta ishlanadi
+type dispatcher struct {
+	queues  []chan tgbotapi.Update
+	policy  string
+	handle  func(update tgbotapi.Update)
+	handled func(update tgbotapi.Update) // Yangilanish qayta ishlangandan keyin chaqiriladi (ixtiyoriy)
+	log     *logger.Logger
+	workers sync.WaitGroup
+	metrics dispatcherMetrics
+}
+
+// dispatcherMetrics navbat va handler kechikishi statistikasi
+type dispatcherMetrics struct {
+	queued       atomic.Int64 // Navbatga qo'yilgan yangilanishlar soni
+	processed    atomic.Int64 // Qayta ishlangan yangilanishlar soni
+	dropped      atomic.Int64 // Navbat to'lgani uchun tashlab yuborilganlar soni
+	latencyTotal atomic.Int64 // Handlerlar umumiy ishlash vaqti (nanosekund)
+	latencyMax   atomic.Int64 // Eng uzoq ishlagan handler vaqti (nanosekund)
+}
+
+// DispatcherStats dispatcher holatining bir lahzalik ko'rinishi
+type DispatcherStats struct {
+	Workers        int           `json:"workers"`         // Ishchilar soni
+	QueueLength    int           `json:"queue_length"`    // Barcha navbatlardagi yangilanishlar soni
+	QueueCapacity  int           `json:"queue_capacity"`  // Barcha navbatlarning umumiy sig'imi
+	Processed      int64         `json:"processed"`       // Qayta ishlanganlar soni
+	Dropped        int64         `json:"dropped"`         // Tashlab yuborilganlar soni
+	AverageLatency time.Duration `json:"average_latency"` // Handlerning o'rtacha ishlash vaqti
+	MaxLatency     time.Duration `json:"max_latency"`     // Handlerning eng uzoq ishlash vaqti
+}
+
+// newDispatcher sozlamalar asosida dispatcher yaratadi va ishchilarni ishga tushiradi
+func newDispatcher(settings config.DispatcherSettings, handle func(update tgbotapi.Update), log *logger.Logger) *dispatcher {
+	workers := settings.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	queueSize := settings.QueueSize
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	policy := strings.ToLower(settings.Policy)
+	if policy != policyDrop {
+		policy = policyBlock
+	}
+
+	d := &dispatcher{
+		queues: make([]chan tgbotapi.Update, workers),
+		policy: policy,
+		handle: handle,
+		log:    log,
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan tgbotapi.Update, queueSize)
+		d.workers.Add(1)
+		go d.work(d.queues[i])
+	}
+
+	log.Infof("Dispatcher ishga tushdi: %d ishchi, navbat uzunligi %d, siyosat %s", workers, queueSize, policy)
+	return d
+}
+
+// work bitta ishchining navbatidagi yangilanishlarni ketma-ket qayta ishlaydi
+func (d *dispatcher) work(queue <-chan tgbotapi.Update) {
+	defer d.workers.Done()
+
+	for update := range queue {
+		start := time.Now()
+		d.safeHandle(update)
+		d.observe(time.Since(start))
+		if d.handled != nil {
+			d.handled(update)
+		}
+	}
+}
+
+// onHandled har bir yangilanish qayta ishlangandan keyin chaqiriladigan funksiyani belgilaydi
+// Birinchi dispatch chaqiruvidan oldin belgilanishi kerak
+func (d *dispatcher) onHandled(fn func(update tgbotapi.Update)) {
+	d.handled = fn
+}
+
+// safeHandle yangilanishni qayta ishlaydi va panic bo'lsa ishchini to'xtatmasdan log qiladi
+// Buyruqlar Recover middleware bilan himoyalangan, bu esa callback va boshqa yangilanishlar uchun oxirgi himoya qatlami
+func (d *dispatcher) safeHandle(update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.log.Errorf("%d yangilanishni qayta ishlashda panic: %v\n%s", update.UpdateID, r, debug.Stack())
+		}
+	}()
+	d.handle(update)
+}
+
+// observe handler ishlash vaqtini statistikaga qo'shadi
+func (d *dispatcher) observe(latency time.Duration) {
+	d.metrics.processed.Add(1)
+	d.metrics.latencyTotal.Add(int64(latency))
+	for {
+		current := d.metrics.latencyMax.Load()
+		if int64(latency) <= current || d.metrics.latencyMax.CompareAndSwap(current, int64(latency)) {
+			return
+		}
+	}
+}
+
+// chatKey yangilanish qaysi chatga tegishli ekanini aniqlaydi
+// Chat bo'lmagan yangilanishlar (masalan, inline so'rovlar) foydalanuvchi bo'yicha guruhlanadi
+func chatKey(update tgbotapi.Update) int64 {
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	if user := update.SentFrom(); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// dispatch yangilanishni tegishli ishchi navbatiga qo'yadi
+// block siyosatida navbatda joy bo'shashini ctx bekor qilinguncha kutadi, drop siyosatida esa darhol tashlab yuboradi
+// Yangilanish navbatga qo'yilsa true qaytaradi
+func (d *dispatcher) dispatch(ctx context.Context, update tgbotapi.Update) bool {
+	queue := d.queues[uint64(chatKey(update))%uint64(len(d.queues))]
+
+	if d.policy == policyDrop {
+		select {
+		case queue <- update:
+			d.metrics.queued.Add(1)
+			return true
+		default:
+			d.metrics.dropped.Add(1)
+			d.log.Warnf("Navbat to'lgan, %d yangilanish tashlab yuborildi", update.UpdateID)
+			return false
+		}
+	}
+
+	select {
+	case queue <- update:
+		d.metrics.queued.Add(1)
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// pending navbatga qo'yilgan, lekin hali qayta ishlanmagan yangilanishlar soni
+func (d *dispatcher) pending() int64 {
+	return d.metrics.queued.Load() - d.metrics.processed.Load()
+}
+
+// stop yangi yangilanishlarni qabul qilishni to'xtatadi va navbatdagilar qayta ishlanishini ko'pi bilan timeout davomida kutadi
+// Barcha ishchilar o'z vaqtida tugasa true qaytaradi
+func (d *dispatcher) stop(timeout time.Duration) bool {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// stats dispatcherning joriy statistikasini qaytaradi
+func (d *dispatcher) stats() DispatcherStats {
+	stats := DispatcherStats{
+		Workers:    len(d.queues),
+		Processed:  d.metrics.processed.Load(),
+		Dropped:    d.metrics.dropped.Load(),
+		MaxLatency: time.Duration(d.metrics.latencyMax.Load()),
+	}
+	for _, queue := range d.queues {
+		stats.QueueLength += len(queue)
+		stats.QueueCapacity += cap(queue)
+	}
+	if stats.Processed > 0 {
+		stats.AverageLatency = time.Duration(d.metrics.latencyTotal.Load() / stats.Processed)
+	}
+	return stats
+}
+
+// reportMetrics statistikani belgilangan oraliqda ctx bekor qilinguncha logga yozadi
+func (d *dispatcher) reportMetrics(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := d.stats()
+			d.log.Infof("Dispatcher: navbatda %d/%d, qayta ishlangan %d, tashlab yuborilgan %d, o'rtacha %s, eng uzoq %s",
+				s.QueueLength, s.QueueCapacity, s.Processed, s.Dropped, s.AverageLatency, s.MaxLatency)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package antispam guruhdagi oddiy xabarlarni spamga tekshiradigan filtrlar zanjiri
+// Har bir filtr xabar bo'yicha qaror (Verdict) chiqaradi, zanjir esa eng qattiq qarorni tanlaydi.
+// Qarorni bajarish (xabarni o'chirish, ogohlantirish, chetlatish) paketdan tashqarida amalga oshiriladi
+package antispam
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Action filtr qarori, qiymat qanchalik katta bo'lsa chora shunchalik qattiq
+type Action int
+
+// Filtr qarorlari
+const (
+	Allow  Action = iota // Xabar o'tkazib yuboriladi
+	Delete               // Xabar o'chiriladi
+	Warn                 // Xabar o'chiriladi va muallifga ogohlantirish beriladi
+	Ban                  // Xabar o'chiriladi va muallif guruhdan chetlatiladi
+)
+
+// actionNames qarorlarning sozlamalardagi nomlari
+var actionNames = map[Action]string{
+	Allow:  "allow",
+	Delete: "delete",
+	Warn:   "warn",
+	Ban:    "ban",
+}
+
+// String qarorning sozlamalardagi nomi
+func (a Action) String() string {
+	if name, ok := actionNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("action(%d)", int(a))
+}
+
+// ParseAction sozlamadagi nomni qarorga aylantiradi
+// Bo'sh qiymat def sifatida qabul qilinadi
+func ParseAction(name string, def Action) (Action, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return def, nil
+	}
+	for action, n := range actionNames {
+		if n == name {
+			return action, nil
+		}
+	}
+	return Allow, fmt.Errorf("noma'lum chora %q (allow, delete, warn yoki ban bo'lishi kerak)", name)
+}
+
+// Message filtrlar tekshiradigan xabar ma'lumotlari
+type Message struct {
+	ChatID    int64
+	UserID    int64
+	Text      string   // Xabar matni yoki media izohi
+	Links     []string // Xabardagi havolalar (matndagi va yashirin havolalar)
+	Forwarded bool     // Xabar kanaldan uzatilgan
+	Channel   string   // Xabar uzatilgan kanalning username'i (kichik harflarda, bo'lmasa bo'sh)
+}
+
+// FromTelegram Telegram xabaridan filtrlar uchun ma'lumotlarni ajratib oladi
+func FromTelegram(m *tgbotapi.Message) Message {
+	msg := Message{ChatID: m.Chat.ID, Text: m.Text}
+	if m.From != nil {
+		msg.UserID = m.From.ID
+	}
+	entities := m.Entities
+	if msg.Text == "" {
+		msg.Text = m.Caption
+		entities = m.CaptionEntities
+	}
+	msg.Links = extractLinks(msg.Text, entities)
+	if m.ForwardFromChat != nil && m.ForwardFromChat.IsChannel() {
+		msg.Forwarded = true
+		msg.Channel = strings.ToLower(m.ForwardFromChat.UserName)
+	}
+	return msg
+}
+
+// extractLinks xabar belgilaridan havolalarni yig'adi
+// Telegram belgilar o'rnini UTF-16 birliklarida beradi
+func extractLinks(text string, entities []tgbotapi.MessageEntity) []string {
+	var links []string
+	var encoded []uint16
+	for _, e := range entities {
+		switch e.Type {
+		case "text_link":
+			links = append(links, e.URL)
+		case "url":
+			if encoded == nil {
+				encoded = utf16.Encode([]rune(text))
+			}
+			if e.Offset >= 0 && e.Length > 0 && e.Offset+e.Length <= len(encoded) {
+				links = append(links, string(utf16.Decode(encoded[e.Offset:e.Offset+e.Length])))
+			}
+		}
+	}
+	return links
+}
+
+// linkHost havoladagi domen nomi (kichik harflarda, www. siz)
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// Verdict filtr qarori va uning sababi
+type Verdict struct {
+	Action Action
+	Filter string // Qaror chiqargan filtr nomi
+	Reason string // Qo'shimcha ma'lumot, masalan taqiqlangan domen yoki qoida izohi
+}
+
+// Filter xabarni tekshiradigan bitta filtr
+type Filter interface {
+	// Name filtr nomi, u qaror va audit yozuvlarida ko'rsatiladi
+	Name() string
+	// Check xabar bo'yicha qaror chiqaradi
+	Check(msg Message) Verdict
+}
+
+// JoinObserver guruhga yangi a'zo qo'shilganini bilishi kerak bo'lgan filtr
+type JoinObserver interface {
+	Joined(chatID, userID int64) error
+}
+
+// Pipeline filtrlar zanjiri
+type Pipeline struct {
+	filters []Filter
+}
+
+// NewPipeline berilgan filtrlardan zanjir yaratadi
+func NewPipeline(filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters}
+}
+
+// Add zanjirga filtr qo'shadi
+func (p *Pipeline) Add(f Filter) {
+	p.filters = append(p.filters, f)
+}
+
+// Check xabarni barcha filtrlardan o'tkazadi va eng qattiq qarorni qaytaradi
+// Qarorlar teng bo'lsa zanjirda oldinroq turgan filtr qarori olinadi
+func (p *Pipeline) Check(msg Message) Verdict {
+	result := Verdict{Action: Allow}
+	for _, f := range p.filters {
+		if v := f.Check(msg); v.Action > result.Action {
+			if v.Filter == "" {
+				v.Filter = f.Name()
+			}
+			result = v
+		}
+	}
+	return result
+}
+
+// Joined yangi a'zo haqida JoinObserver filtrlariga xabar beradi
+func (p *Pipeline) Joined(chatID, userID int64) error {
+	for _, f := range p.filters {
+		if o, ok := f.(JoinObserver); ok {
+			if err := o.Joined(chatID, userID); err != nil {
+				return fmt.Errorf("%s: %w", f.Name(), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package antispam
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"tg-bot/internal/storage"
+)
+
+// Ichki filtrlar nomlari
+const (
+	FilterLinks     = "links"     // Taqiqlangan domenlarga havolalar
+	FilterNewcomer  = "newcomer"  // Yangi a'zoning dastlabki xabarlaridagi havolalar va repostlar
+	FilterForward   = "forward"   // Kanallardan uzatilgan xabarlar
+	FilterDuplicate = "duplicate" // Bir nechta guruhga yuborilgan bir xil xabar
+	FilterRule      = "rule"      // Sozlamalardagi muntazam ifoda qoidalari
+)
+
+// Config ichki filtrlar sozlamalari
+// Chora nomlari ParseAction qabul qiladigan qiymatlar, bo'sh bo'lsa filtrning standart chorasi olinadi
+type Config struct {
+	AllowDomains []string // Hech qachon spam hisoblanmaydigan domenlar (subdomenlari bilan)
+	DenyDomains  []string // Har doim spam hisoblanadigan domenlar (subdomenlari bilan)
+	LinkAction   string   // Taqiqlangan domenga havola uchun chora
+
+	NewcomerMessages int    // Yangi a'zoning nechta birinchi xabari tekshiriladi (0 - filtr o'chirilgan)
+	NewcomerAction   string // Yangi a'zo ruxsat etilmagan havola yoki repost yuborganda chora
+
+	ForwardAction   string   // Kanaldan uzatilgan xabar uchun chora (allow - filtr o'chirilgan)
+	ForwardChannels []string // Xabarlarini uzatish mumkin bo'lgan kanallar username'lari
+
+	DuplicateChats     int           // Bir xil matn nechta guruhda uchrasa spam hisoblanadi (0 - filtr o'chirilgan)
+	DuplicateWindow    time.Duration // Takrorlar qancha vaqt ichida hisoblanadi
+	DuplicateMinLength int           // Bundan qisqa matnlar tekshirilmaydi
+	DuplicateAction    string        // Takrorlangan xabar uchun chora
+
+	Rules []Rule // Muntazam ifoda qoidalari
+}
+
+// Rule matnga qo'llaniladigan muntazam ifoda qoidasi
+type Rule struct {
+	Pattern string // Go regexp sintaksisidagi ifoda
+	Action  string // Ifoda mos kelganda chora
+	Reason  string // Audit va ogohlantirishda ko'rsatiladigan izoh (bo'sh bo'lsa ifodaning o'zi)
+}
+
+// New sozlamalar asosida ichki filtrlardan zanjir yaratadi
+func New(cfg Config, store storage.Store) (*Pipeline, error) {
+	allow, deny := newDomainList(cfg.AllowDomains), newDomainList(cfg.DenyDomains)
+	p := NewPipeline()
+
+	if len(deny) > 0 {
+		action, err := ParseAction(cfg.LinkAction, Delete)
+		if err != nil {
+			return nil, fmt.Errorf("links: %w", err)
+		}
+		p.Add(&LinkFilter{allow: allow, deny: deny, action: action})
+	}
+
+	if cfg.NewcomerMessages > 0 {
+		action, err := ParseAction(cfg.NewcomerAction, Delete)
+		if err != nil {
+			return nil, fmt.Errorf("newcomers: %w", err)
+		}
+		p.Add(&NewcomerFilter{store: store, messages: cfg.NewcomerMessages, allow: allow, action: action})
+	}
+
+	if action, err := ParseAction(cfg.ForwardAction, Allow); err != nil {
+		return nil, fmt.Errorf("forwards: %w", err)
+	} else if action != Allow {
+		channels := make(map[string]bool, len(cfg.ForwardChannels))
+		for _, c := range cfg.ForwardChannels {
+			channels[strings.ToLower(strings.TrimPrefix(strings.TrimSpace(c), "@"))] = true
+		}
+		p.Add(&ForwardFilter{action: action, channels: channels})
+	}
+
+	if cfg.DuplicateChats > 1 {
+		action, err := ParseAction(cfg.DuplicateAction, Delete)
+		if err != nil {
+			return nil, fmt.Errorf("duplicates: %w", err)
+		}
+		window := cfg.DuplicateWindow
+		if window <= 0 {
+			window = 10 * time.Minute
+		}
+		p.Add(NewDuplicateFilter(cfg.DuplicateChats, window, cfg.DuplicateMinLength, action))
+	}
+
+	if len(cfg.Rules) > 0 {
+		rules, err := NewRuleFilter(cfg.Rules)
+		if err != nil {
+			return nil, err
+		}
+		p.Add(rules)
+	}
+	return p, nil
+}
+
+// domainList domenlar ro'yxati, domen o'zi va uning subdomenlari mos keladi
+type domainList []string
+
+// newDomainList ro'yxatni kichik harflarga keltiradi va bo'sh qiymatlarni tashlab yuboradi
+func newDomainList(domains []string) domainList {
+	var list domainList
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "www.")
+		if d != "" {
+			list = append(list, d)
+		}
+	}
+	return list
+}
+
+// Match domen ro'yxatdagi domen yoki uning subdomeni ekanligini tekshiradi
+func (l domainList) Match(host string) bool {
+	for _, d := range l {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkFilter taqiqlangan domenlarga havolalarni aniqlaydi
+type LinkFilter struct {
+	allow, deny domainList
+	action      Action
+}
+
+// Name filtr nomi
+func (f *LinkFilter) Name() string { return FilterLinks }
+
+// Check xabarda taqiqlangan domenga havola borligini tekshiradi
+// Ruxsat etilgan ro'yxatdagi domen taqiqlangan ro'yxatdan ustun turadi
+func (f *LinkFilter) Check(msg Message) Verdict {
+	for _, link := range msg.Links {
+		host := linkHost(link)
+		if host != "" && !f.allow.Match(host) && f.deny.Match(host) {
+			return Verdict{Action: f.action, Filter: FilterLinks, Reason: host}
+		}
+	}
+	return Verdict{}
+}
+
+// newcomersBucket yangi a'zolarning tekshirilishi qolgan xabarlari soni ("<chat ID>:<foydalanuvchi ID>" kaliti bo'yicha)
+const newcomersBucket = "antispam_newcomers"
+
+// NewcomerFilter guruhga yangi qo'shilgan a'zoning dastlabki xabarlarida havola va repostlarga ruxsat bermaydi
+// Filtr faqat bot qo'shilishini ko'rgan a'zolarni tekshiradi, oldindan guruhda bo'lganlar yangi hisoblanmaydi
+type NewcomerFilter struct {
+	store    storage.Store
+	messages int
+	allow    domainList
+	action   Action
+
+	mu sync.Mutex
+}
+
+// Name filtr nomi
+func (f *NewcomerFilter) Name() string { return FilterNewcomer }
+
+// newcomerKey yangi a'zo yozuvi kaliti
+func newcomerKey(chatID, userID int64) string {
+	return fmt.Sprintf("%d:%d", chatID, userID)
+}
+
+// Joined yangi a'zoning dastlabki xabarlarini tekshirishni boshlaydi
+func (f *NewcomerFilter) Joined(chatID, userID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.store.Put(newcomersBucket, newcomerKey(chatID, userID), []byte(strconv.Itoa(f.messages)))
+}
+
+// Check yangi a'zoning xabarini tekshiradi va tekshirilishi qolgan xabarlar sonini kamaytiradi
+func (f *NewcomerFilter) Check(msg Message) Verdict {
+	if !f.count(msg.ChatID, msg.UserID) {
+		return Verdict{}
+	}
+	if msg.Forwarded {
+		return Verdict{Action: f.action, Filter: FilterNewcomer}
+	}
+	for _, link := range msg.Links {
+		if host := linkHost(link); !f.allow.Match(host) {
+			return Verdict{Action: f.action, Filter: FilterNewcomer, Reason: host}
+		}
+	}
+	return Verdict{}
+}
+
+// count foydalanuvchi yangi a'zo ekanligini aniqlaydi va hisoblagichni kamaytiradi
+func (f *NewcomerFilter) count(chatID, userID int64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := newcomerKey(chatID, userID)
+	data, err := f.store.Get(newcomersBucket, key)
+	if err != nil {
+		// Ombor xatosida ham xabar o'tkazib yuboriladi, yangi a'zoni tekshirmaslik guruhni to'xtatib qo'yishdan yaxshiroq
+		return false
+	}
+	left, err := strconv.Atoi(string(data))
+	if err != nil || left <= 1 {
+		_ = f.store.Delete(newcomersBucket, key)
+		return err == nil
+	}
+	_ = f.store.Put(newcomersBucket, key, []byte(strconv.Itoa(left-1)))
+	return true
+}
+
+// ForwardFilter ruxsat etilmagan kanallardan uzatilgan xabarlarni aniqlaydi
+type ForwardFilter struct {
+	action   Action
+	channels map[string]bool
+}
+
+// Name filtr nomi
+func (f *ForwardFilter) Name() string { return FilterForward }
+
+// Check xabar ruxsat etilmagan kanaldan uzatilganini tekshiradi
+func (f *ForwardFilter) Check(msg Message) Verdict {
+	if msg.Forwarded && (msg.Channel == "" || !f.channels[msg.Channel]) {
+		return Verdict{Action: f.action, Filter: FilterForward, Reason: msg.Channel}
+	}
+	return Verdict{}
+}
+
+// DuplicateFilter bir xil matnning qisqa vaqt ichida bir nechta guruhga yuborilishini aniqlaydi
+// Matnlar xotirada faqat xesh ko'rinishida saqlanadi
+type DuplicateFilter struct {
+	chats     int
+	window    time.Duration
+	minLength int
+	action    Action
+
+	mu        sync.Mutex
+	sightings map[string][]sighting
+	swept     time.Time
+}
+
+// sighting matn qaysi guruhda qachon uchragani
+type sighting struct {
+	chatID int64
+	at     time.Time
+}
+
+// NewDuplicateFilter takrorlanuvchi xabarlar filtrini yaratadi
+func NewDuplicateFilter(chats int, window time.Duration, minLength int, action Action) *DuplicateFilter {
+	return &DuplicateFilter{
+		chats:     chats,
+		window:    window,
+		minLength: minLength,
+		action:    action,
+		sightings: make(map[string][]sighting),
+	}
+}
+
+// Name filtr nomi
+func (f *DuplicateFilter) Name() string { return FilterDuplicate }
+
+// normalize matnni solishtirish uchun bir xil ko'rinishga keltiradi
+// Spamchilar ko'pincha faqat bo'shliqlar va harflar registrini o'zgartiradi
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// Check matn oyna ichida nechta turli guruhda uchraganini hisoblaydi
+func (f *DuplicateFilter) Check(msg Message) Verdict {
+	text := normalize(msg.Text)
+	if text == "" || utf8.RuneCountInString(text) < f.minLength {
+		return Verdict{}
+	}
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:16])
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	f.sweep(now)
+
+	seen := append(fresh(f.sightings[hash], now.Add(-f.window)), sighting{chatID: msg.ChatID, at: now})
+	f.sightings[hash] = seen
+
+	chats := make(map[int64]bool)
+	for _, s := range seen {
+		chats[s.chatID] = true
+	}
+	if len(chats) >= f.chats {
+		return Verdict{Action: f.action, Filter: FilterDuplicate, Reason: strconv.Itoa(len(chats))}
+	}
+	return Verdict{}
+}
+
+// sweep vaqti-vaqti bilan oynadan chiqqan barcha yozuvlarni o'chiradi
+func (f *DuplicateFilter) sweep(now time.Time) {
+	if now.Sub(f.swept) < f.window {
+		return
+	}
+	f.swept = now
+	for hash, seen := range f.sightings {
+		if seen = fresh(seen, now.Add(-f.window)); len(seen) == 0 {
+			delete(f.sightings, hash)
+		} else {
+			f.sightings[hash] = seen
+		}
+	}
+}
+
+// fresh since dan keyingi yozuvlarni qaytaradi
+func fresh(seen []sighting, since time.Time) []sighting {
+	i := 0
+	for i < len(seen) && seen[i].at.Before(since) {
+		i++
+	}
+	return seen[i:]
+}
+
+// RuleFilter sozlamalardagi muntazam ifoda qoidalarini qo'llaydi
+type RuleFilter struct {
+	rules []compiledRule
+}
+
+// compiledRule kompilyatsiya qilingan qoida
+type compiledRule struct {
+	re     *regexp.Regexp
+	action Action
+	reason string
+}
+
+// NewRuleFilter qoidalarni kompilyatsiya qiladi
+func NewRuleFilter(rules []Rule) (*RuleFilter, error) {
+	f := &RuleFilter{}
+	for i, r := range rules {
+		if r.Pattern == "" {
+			return nil, errors.New("rules: bo'sh ifoda")
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		action, err := ParseAction(r.Action, Delete)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+		reason := r.Reason
+		if reason == "" {
+			reason = r.Pattern
+		}
+		f.rules = append(f.rules, compiledRule{re: re, action: action, reason: reason})
+	}
+	return f, nil
+}
+
+// Name filtr nomi
+func (f *RuleFilter) Name() string { return FilterRule }
+
+// Check matnga mos keladigan qoidalar ichidan eng qattiq chorani tanlaydi
+func (f *RuleFilter) Check(msg Message) Verdict {
+	result := Verdict{}
+	if msg.Text == "" {
+		return result
+	}
+	for _, r := range f.rules {
+		if r.action > result.Action && r.re.MatchString(msg.Text) {
+			result = Verdict{Action: r.action, Filter: FilterRule, Reason: r.reason}
+		}
+	}
+	return result
+}
@@ -0,0 +1,139 @@
+package antispam
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadRules configs/config.yaml dagi standart qoidalarni o'qiydi
+func loadRules(t *testing.T) []Rule {
+	t.Helper()
+	data, err := os.ReadFile("../../configs/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		Antispam struct {
+			Rules []Rule `yaml:"rules"`
+		} `yaml:"antispam"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Antispam.Rules) == 0 {
+		t.Fatal("configs/config.yaml: qoidalar topilmadi")
+	}
+	return cfg.Antispam.Rules
+}
+
+func TestDefaultRules(t *testing.T) {
+	rules := loadRules(t)
+	for _, r := range rules {
+		// Qoidalar oddiy suhbatga ham tushishi mumkin, standart sozlamalar hech kimni chetlatmasligi kerak
+		if action, _ := ParseAction(r.Action, Delete); action == Ban {
+			t.Errorf("%q qoidasi standart holatda chetlatadi", r.Pattern)
+		}
+	}
+	f, err := NewRuleFilter(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"crypto/rand paketidan foydalaning", false},
+		{"crypto/tls ni investigate qildim", false},
+		{"Parol uchun crypto/sha256 foydali", false},
+		{"math/rand o'rniga crypto/rand, keyin daromad haqida gaplashamiz", false},
+		{"Kriptografiya bo'yicha kitob kerak, investitsiya qilishga tayyorman", false},
+		{"Go'da goroutine qanday ishlaydi?", false},
+		{"Bitcoin bilan kuniga profit qiling", true},
+		{"USDT invest qiling, 100% kafolat", true},
+		{"Kripto orqali daromadingizni oshiring", true},
+		{"crypto earn", true},
+		{"这是一个非常长的中文垃圾广告信息内容", true},
+	}
+	for _, tt := range tests {
+		got := f.Check(Message{Text: tt.text})
+		if (got.Action != Allow) != tt.want {
+			t.Errorf("Check(%q) = %+v, want match %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLinkFilter(t *testing.T) {
+	f := &LinkFilter{allow: newDomainList([]string{"go.dev"}), deny: newDomainList([]string{"dev", "www.spam.com"}), action: Delete}
+	tests := []struct {
+		link string
+		want Action
+	}{
+		{"https://go.dev/doc", Allow},
+		{"https://pkg.go.dev/fmt", Allow},
+		{"https://evil.dev", Delete},
+		{"spam.com/x", Delete},
+		{"https://sub.spam.com", Delete},
+		{"https://notspam.com", Allow},
+	}
+	for _, tt := range tests {
+		if got := f.Check(Message{Links: []string{tt.link}}); got.Action != tt.want {
+			t.Errorf("Check(%q) = %v, want %v", tt.link, got.Action, tt.want)
+		}
+	}
+}
+
+func TestForwardFilter(t *testing.T) {
+	f := &ForwardFilter{action: Delete, channels: map[string]bool{"golang_uz": true}}
+	tests := []struct {
+		msg  Message
+		want Action
+	}{
+		{Message{}, Allow},
+		{Message{Forwarded: true, Channel: "golang_uz"}, Allow},
+		{Message{Forwarded: true, Channel: "spam_channel"}, Delete},
+		{Message{Forwarded: true}, Delete},
+	}
+	for _, tt := range tests {
+		if got := f.Check(tt.msg); got.Action != tt.want {
+			t.Errorf("Check(%+v) = %v, want %v", tt.msg, got.Action, tt.want)
+		}
+	}
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	f := NewDuplicateFilter(3, time.Minute, 10, Delete)
+	text := "Arzon kurslar, batafsil lichkada"
+	for i, chatID := range []int64{-1, -1, -2} {
+		if got := f.Check(Message{ChatID: chatID, Text: text}); got.Action != Allow {
+			t.Fatalf("message %d: got %v, want allow", i, got.Action)
+		}
+	}
+	// Bo'shliq va registr farqi bir xil matn hisoblanadi
+	if got := f.Check(Message{ChatID: -3, Text: "  arzon KURSLAR,   batafsil lichkada"}); got.Action != Delete {
+		t.Fatalf("third chat: got %v, want delete", got.Action)
+	}
+	if got := f.Check(Message{ChatID: -4, Text: "salom"}); got.Action != Allow {
+		t.Fatalf("short text: got %v, want allow", got.Action)
+	}
+}
+
+// fixedFilter har doim bir xil qaror qaytaradigan filtr
+type fixedFilter Verdict
+
+func (f fixedFilter) Name() string          { return f.Filter }
+func (f fixedFilter) Check(Message) Verdict { return Verdict(f) }
+
+func TestPipelineStrictest(t *testing.T) {
+	p := NewPipeline(
+		fixedFilter{Action: Delete, Filter: "a"},
+		fixedFilter{Action: Warn, Filter: "b"},
+		fixedFilter{Action: Allow, Filter: "c"},
+	)
+	if got := p.Check(Message{Text: "x"}); got.Action != Warn || got.Filter != "b" {
+		t.Fatalf("Check = %+v, want warn from b", got)
+	}
+}
@@ -3,11 +3,14 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -18,10 +21,186 @@ type Config struct {
 	TelegramToken string `yaml:"telegram_token"` // Telegram bot tokeni - Botfather tomonidan berilgan maxsus identifikator
 	LogLevel      string `yaml:"log_level"`      // Log darajasi - qancha batafsil ma'lumot saqlanishini belgilaydi (debug, info, warn, error)
 	Mode          string `yaml:"mode"`           // Bot ishlash rejimi - webhook yoki polling
-	Webhook       struct {
-		URL  string `yaml:"url"`  // Webhook URL manzili - faqat webhook rejimida ishlatiladi
-		Port string `yaml:"port"` // Webhook porti - faqat webhook rejimida ishlatiladi
+	// ShutdownTimeout to'xtatish signali kelganda ishlayotgan handlerlarni kutish muddati
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	Webhook         struct {
+		URL         string `yaml:"url"`          // Webhook URL manzili - faqat webhook rejimida ishlatiladi
+		Port        string `yaml:"port"`         // Webhook porti - faqat webhook rejimida ishlatiladi
+		Path        string `yaml:"path"`         // Webhook endpoint yo'li - bo'sh bo'lsa URL dagi yo'l yoki bot tokeni ishlatiladi
+		SecretToken string `yaml:"secret_token"` // Telegram so'rovlarini tekshirish uchun maxfiy kalit - bo'sh bo'lsa har ishga tushishda yangisi yaratiladi
 	} `yaml:"webhook"`
+	Storage struct {
+		Driver string `yaml:"driver"` // Ombor turi - bolt (fayl) yoki memory (xotira)
+		Path   string `yaml:"path"`   // BoltDB fayli yo'li - faqat bolt drayverida ishlatiladi
+	} `yaml:"storage"`
+	Moderation struct {
+		Warn  WarnPolicy           `yaml:"warn"`  // Barcha guruhlar uchun standart ogohlantirish siyosati
+		Chats map[int64]WarnPolicy `yaml:"chats"` // Guruh ID si bo'yicha alohida siyosatlar
+	} `yaml:"moderation"`
+	Captcha    CaptchaSettings    `yaml:"captcha"`    // Yangi a'zolarni tekshirish sozlamalari
+	Dispatcher DispatcherSettings `yaml:"dispatcher"` // Yangilanishlarni taqsimlash sozlamalari
+	Content    ContentSettings    `yaml:"content"`    // Bot matnlari sozlamalari
+	Releases   ReleasesSettings   `yaml:"releases"`   // Go relizlari ma'lumotlari sozlamalari
+	Docs       DocsSettings       `yaml:"docs"`       // Standart kutubxona hujjatlari sozlamalari
+	Snippets   SnippetSettings    `yaml:"snippets"`   // /fmt va /vet chegaralari
+	Run        RunSettings        `yaml:"run"`        // /run sandbox sozlamalari
+	Quiz       QuizSettings       `yaml:"quiz"`       // Viktorina sozlamalari
+	Roadmap    RoadmapSettings    `yaml:"roadmap"`    // Interaktiv yo'l xaritasi sozlamalari
+	Start      StartSettings      `yaml:"start"`      // /start havolalari sozlamalari
+	Antispam   AntispamSettings   `yaml:"antispam"`   // Guruhlardagi oddiy xabarlarni spamga tekshirish
+	Flood      FloodSettings      `yaml:"flood"`      // Guruhlarda juda tez yozishni cheklash
+	Reports    ReportSettings     `yaml:"reports"`    // /report shikoyatlari sozlamalari
+	Modlog     ModlogSettings     `yaml:"modlog"`     // Moderatsiya jurnali sozlamalari
+}
+
+// ModlogSettings moderatsiya jurnali sozlamalari
+// Har bir chora jurnal kanaliga ham yoziladi, guruh adminlari o'z kanalini /modlog channel orqali belgilashi mumkin
+type ModlogSettings struct {
+	Channel     int64  `yaml:"channel"`      // Guruh uchun kanal belgilanmaganda choralar yoziladigan kanal ID si (0 - yozilmaydi)
+	ExportToken string `yaml:"export_token"` // Webhook serveridagi /modlog/export uchun kalit (bo'sh - eksport o'chirilgan)
+}
+
+// ReportSettings a'zolar /report orqali yuboradigan shikoyatlar sozlamalari
+// Shikoyat adminlar guruhiga yoki har bir adminga shaxsiy xabar sifatida yuboriladi
+type ReportSettings struct {
+	Chat         int64         `yaml:"chat"`          // Shikoyatlar yuboriladigan adminlar guruhi ID si (0 - har bir adminga shaxsiy xabar)
+	Limit        int           `yaml:"limit"`         // Bir a'zo Window ichida yuborishi mumkin bo'lgan shikoyatlar soni
+	Window       time.Duration `yaml:"window"`        // Shikoyatlar sanaladigan vaqt oralig'i
+	MuteDuration time.Duration `yaml:"mute_duration"` // Shikoyat bo'yicha ovozsiz qilish muddati
+}
+
+// FloodSettings bir foydalanuvchining guruhda juda tez-tez xabar yozishini cheklash sozlamalari
+// Chegaradan oshgan xabarlar o'chiriladi, flood davom etsa foydalanuvchi vaqtincha ovozsiz qilinadi va adminlarga xabar beriladi
+type FloodSettings struct {
+	Enabled      bool          `yaml:"enabled"`       // Flood nazorati yoqilganmi (/slowmode bundan qat'i nazar ishlaydi)
+	Messages     int           `yaml:"messages"`      // Window ichida ruxsat etilgan xabarlar soni
+	Window       time.Duration `yaml:"window"`        // Xabarlar sanaladigan vaqt oralig'i
+	MuteAfter    int           `yaml:"mute_after"`    // Window ichida shundan ko'p xabar yozilsa foydalanuvchi ovozsiz qilinadi (0 - ovozsiz qilinmaydi)
+	MuteDuration time.Duration `yaml:"mute_duration"` // Ovozsiz qilish muddati
+}
+
+// AntispamSettings guruhlardagi oddiy xabarlarni tekshiradigan filtrlar sozlamalari
+// Choralar: allow (o'tkazib yuborish), delete (o'chirish), warn (o'chirish va ogohlantirish), ban (o'chirish va chetlatish)
+type AntispamSettings struct {
+	Enabled   bool  `yaml:"enabled"`    // Filtrlar yoqilganmi
+	AuditChat int64 `yaml:"audit_chat"` // Ko'rilgan choralar yoziladigan adminlar kanali yoki guruhi (0 - yozilmaydi)
+	Links     struct {
+		Allow  []string `yaml:"allow"`  // Doim ruxsat etilgan domenlar (subdomenlari bilan)
+		Deny   []string `yaml:"deny"`   // Taqiqlangan domenlar (subdomenlari bilan)
+		Action string   `yaml:"action"` // Taqiqlangan domenga havola uchun chora
+	} `yaml:"links"`
+	Newcomers struct {
+		Messages int    `yaml:"messages"` // Yangi a'zoning nechta birinchi xabarida havola va repostlarga ruxsat yo'q (0 - o'chirilgan)
+		Action   string `yaml:"action"`   // Qoida buzilganda chora
+	} `yaml:"newcomers"`
+	Forwards struct {
+		Action   string   `yaml:"action"`   // Kanaldan uzatilgan xabar uchun chora
+		Channels []string `yaml:"channels"` // Xabarlarini uzatish mumkin bo'lgan kanallar
+	} `yaml:"forwards"`
+	Duplicates struct {
+		Chats     int           `yaml:"chats"`      // Bir xil matn nechta guruhda uchrasa spam hisoblanadi (0 - o'chirilgan)
+		Window    time.Duration `yaml:"window"`     // Takrorlar hisoblanadigan vaqt oralig'i
+		MinLength int           `yaml:"min_length"` // Bundan qisqa matnlar tekshirilmaydi
+		Action    string        `yaml:"action"`     // Takrorlangan xabar uchun chora
+	} `yaml:"duplicates"`
+	Rules []AntispamRule `yaml:"rules"` // Muntazam ifoda qoidalari
+}
+
+// AntispamRule xabar matniga qo'llaniladigan muntazam ifoda qoidasi
+type AntispamRule struct {
+	Pattern string `yaml:"pattern"` // Go regexp sintaksisidagi ifoda
+	Action  string `yaml:"action"`  // Ifoda mos kelganda chora
+	Reason  string `yaml:"reason"`  // Adminlarga va ogohlantirishda ko'rsatiladigan izoh
+}
+
+// StartSettings https://t.me/<bot>?start=... havolalari sozlamalari
+type StartSettings struct {
+	Secret string `yaml:"secret"` // Havoladagi guruh ID si kabi qiymatlarni imzolash kaliti (bo'sh - bot tokenidan hosil qilinadi)
+}
+
+// RoadmapSettings shaxsiy chatdagi interaktiv yo'l xaritasi sozlamalari
+type RoadmapSettings struct {
+	File string `yaml:"file"` // Bo'limlar, mavzular va manbalar fayli (bo'sh - faqat umumiy ko'rinish)
+}
+
+// QuizSettings /quiz viktorinasi sozlamalari
+// Savollar banki content katalogidan alohida saqlanadi, chunki u matnlar emas, tuzilgan ma'lumotlar
+type QuizSettings struct {
+	Enabled  bool   `yaml:"enabled"`  // /quiz, /top va /quizdaily buyruqlari yoqilganmi
+	Bank     string `yaml:"bank"`     // Savollar banki fayli
+	Timezone string `yaml:"timezone"` // Kun savoli vaqti qaysi vaqt mintaqasida hisoblanadi
+}
+
+// RunSettings /run buyrug'i uchun sandbox sozlamalari
+// Foydalanuvchi kodini bot kompyuterida bajarish xavfli, shuning uchun buyruq standart holatda o'chirilgan
+type RunSettings struct {
+	Enabled        bool          `yaml:"enabled"`         // /run buyrug'i yoqilganmi
+	GoBinary       string        `yaml:"go_binary"`       // go buyrug'i (PATH dagi nomi yoki to'liq yo'li)
+	WorkDir        string        `yaml:"work_dir"`        // Vaqtinchalik kataloglar va kompilyatsiya keshi (bo'sh - tizimning vaqtinchalik katalogi)
+	BuildTimeout   time.Duration `yaml:"build_timeout"`   // Kompilyatsiya uchun eng ko'p vaqt
+	RunTimeout     time.Duration `yaml:"run_timeout"`     // Dastur ishlashi uchun eng ko'p vaqt
+	MemoryMB       int           `yaml:"memory_mb"`       // Dastur xotirasi chegarasi (megabaytlarda)
+	MaxOutput      int           `yaml:"max_output"`      // Qaytariladigan chiqishning eng katta hajmi (baytlarda)
+	AllowedImports []string      `yaml:"allowed_imports"` // Ruxsat etilgan importlar (bo'sh - standart ro'yxat)
+	Workers        int           `yaml:"workers"`         // Bir vaqtda bajariladigan dasturlar soni
+	QueueSize      int           `yaml:"queue_size"`      // Navbatda kutishi mumkin bo'lgan dasturlar soni
+	UserQuota      int           `yaml:"user_quota"`      // Bitta foydalanuvchi quota_window ichida ishga tushira oladigan dasturlar soni
+	QuotaWindow    time.Duration `yaml:"quota_window"`    // Kvota hisoblanadigan oraliq
+}
+
+// SnippetSettings chatdagi kod parchalarini formatlash va tekshirish chegaralari
+type SnippetSettings struct {
+	MaxBytes int           `yaml:"max_bytes"` // Kodning eng katta hajmi (baytlarda)
+	Timeout  time.Duration `yaml:"timeout"`   // Bitta formatlash yoki tekshiruv uchun eng ko'p vaqt
+}
+
+// DocsSettings /doc buyrug'i uchun hujjatlar indeksi sozlamalari
+// Indeks bot ishlayotgan kompyuterdagi Go manba kodidan quriladi, tarmoq talab qilinmaydi
+type DocsSettings struct {
+	Enabled bool   `yaml:"enabled"` // /doc buyrug'i va inline qidiruv yoqilganmi
+	GOROOT  string `yaml:"goroot"`  // Go o'rnatilgan katalog (bo'sh - GOROOT yoki `go env GOROOT`)
+}
+
+// ReleasesSettings Go relizlari haqidagi ma'lumotlar manbasi sozlamalari
+// Manbalar http(s) manzil yoki mahalliy fayl yo'li bo'lishi mumkin, bu sinov uchun qulay
+type ReleasesSettings struct {
+	SourceURL       string        `yaml:"source_url"`       // go.dev/dl JSON formatidagi relizlar ro'yxati
+	HistoryURL      string        `yaml:"history_url"`      // Reliz tarixi sahifasi, sanalar va xavfsizlik tuzatishlari uchun (bo'sh - o'chirilgan)
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // Ma'lumotlarni yangilash oralig'i (0 - faqat ishga tushganda)
+	Timeout         time.Duration `yaml:"timeout"`          // Manbadan o'qish uchun eng ko'p vaqt
+}
+
+// ContentSettings bot yuboradigan matnlar joylashgan katalog sozlamalari
+// Matnlar Markdown yoki YAML fayllarda saqlanadi va o'zgartirilganda avtomatik qayta yuklanadi
+type ContentSettings struct {
+	Dir            string        `yaml:"dir"`             // Matn fayllari katalogi
+	ReloadInterval time.Duration `yaml:"reload_interval"` // Fayllar o'zgarganini tekshirish oralig'i (0 - faqat /reload orqali)
+	Owners         []int64       `yaml:"owners"`          // /reload buyrug'ini bajara oladigan bot egalari ID lari (bo'sh - hech kim)
+}
+
+// DispatcherSettings yangilanishlarni qayta ishlovchi ishchilar hovuzi sozlamalari
+// Bir chatdagi yangilanishlar doim bitta ishchiga tushadi, shuning uchun ular kelgan tartibda qayta ishlanadi
+type DispatcherSettings struct {
+	Workers         int           `yaml:"workers"`          // Parallel ishlovchi ishchilar soni
+	QueueSize       int           `yaml:"queue_size"`       // Har bir ishchi navbatining maksimal uzunligi
+	Policy          string        `yaml:"policy"`           // Navbat to'lganda: block (kutish) yoki drop (tashlab yuborish)
+	MetricsInterval time.Duration `yaml:"metrics_interval"` // Navbat va kechikish statistikasini logga yozish oralig'i (0 - o'chirilgan)
+}
+
+// CaptchaSettings yangi a'zolarni bot emasligini tekshirish sozlamalari
+// Yoqilganda yangi a'zo cheklanadi va savolga to'g'ri javob bermaguncha yoza olmaydi
+type CaptchaSettings struct {
+	Enabled bool          `yaml:"enabled"` // Tekshiruv yoqilganmi
+	Mode    string        `yaml:"mode"`    // Savol turi - question (Go bo'yicha savol) yoki button (tugma topish)
+	Timeout time.Duration `yaml:"timeout"` // Javob berish uchun vaqt, tugagach foydalanuvchi chiqarib yuboriladi
+}
+
+// WarnPolicy ogohlantirishlar soniga qarab qo'llaniladigan choralarni belgilaydi
+// Nol qiymatli chegara tegishli chora o'chirilganini bildiradi
+type WarnPolicy struct {
+	MuteAfter    int           `yaml:"mute_after"`    // Nechta ogohlantirishdan keyin foydalanuvchi ovozsiz qilinadi
+	BanAfter     int           `yaml:"ban_after"`     // Nechta ogohlantirishdan keyin foydalanuvchi guruhdan chetlatiladi
+	MuteDuration time.Duration `yaml:"mute_duration"` // Ovozsiz qilish muddati (masalan, 24h)
 }
 
 // GetTelegramToken Telegram bot tokenini qaytaruvchi metod
@@ -35,6 +214,11 @@ func (c *Config) IsWebhookMode() bool {
 	return strings.ToLower(c.Mode) == "webhook"
 }
 
+// GetShutdownTimeout to'xtatishda ishlayotgan handlerlarni kutish muddatini qaytaradi
+func (c *Config) GetShutdownTimeout() time.Duration {
+	return c.ShutdownTimeout
+}
+
 // WebhookURL webhook URL manzilini qaytaradi
 func (c *Config) WebhookURL() string {
 	return c.Webhook.URL
@@ -45,15 +229,206 @@ func (c *Config) WebhookPort() string {
 	return c.Webhook.Port
 }
 
+// WebhookPath webhook endpoint yo'lini qaytaradi
+func (c *Config) WebhookPath() string {
+	return c.Webhook.Path
+}
+
+// WebhookSecretToken webhook so'rovlarini tekshirish uchun maxfiy kalitni qaytaradi
+func (c *Config) WebhookSecretToken() string {
+	return c.Webhook.SecretToken
+}
+
+// StorageDriver ombor turini qaytaradi
+func (c *Config) StorageDriver() string {
+	return c.Storage.Driver
+}
+
+// StoragePath ombor fayli yo'lini qaytaradi
+func (c *Config) StoragePath() string {
+	return c.Storage.Path
+}
+
+// WarnPolicy ko'rsatilgan guruh uchun ogohlantirish siyosatini qaytaradi
+// Guruh uchun alohida qiymat berilmagan maydonlar standart siyosatdan olinadi
+func (c *Config) WarnPolicy(chatID int64) WarnPolicy {
+	policy := c.Moderation.Warn
+	override, ok := c.Moderation.Chats[chatID]
+	if !ok {
+		return policy
+	}
+
+	if override.MuteAfter != 0 {
+		policy.MuteAfter = override.MuteAfter
+	}
+	if override.BanAfter != 0 {
+		policy.BanAfter = override.BanAfter
+	}
+	if override.MuteDuration != 0 {
+		policy.MuteDuration = override.MuteDuration
+	}
+	return policy
+}
+
+// CaptchaSettings yangi a'zolarni tekshirish sozlamalarini qaytaradi
+func (c *Config) CaptchaSettings() CaptchaSettings {
+	return c.Captcha
+}
+
+// DispatcherSettings yangilanishlarni taqsimlash sozlamalarini qaytaradi
+func (c *Config) DispatcherSettings() DispatcherSettings {
+	return c.Dispatcher
+}
+
+// ContentSettings bot matnlari sozlamalarini qaytaradi
+func (c *Config) ContentSettings() ContentSettings {
+	return c.Content
+}
+
+// ReleasesSettings Go relizlari ma'lumotlari sozlamalarini qaytaradi
+func (c *Config) ReleasesSettings() ReleasesSettings {
+	return c.Releases
+}
+
+// DocsSettings standart kutubxona hujjatlari sozlamalarini qaytaradi
+func (c *Config) DocsSettings() DocsSettings {
+	return c.Docs
+}
+
+// SnippetSettings kod parchalari bilan ishlash chegaralarini qaytaradi
+func (c *Config) SnippetSettings() SnippetSettings {
+	return c.Snippets
+}
+
+// RunSettings /run sandbox sozlamalarini qaytaradi
+func (c *Config) RunSettings() RunSettings {
+	return c.Run
+}
+
+// QuizSettings viktorina sozlamalarini qaytaradi
+func (c *Config) QuizSettings() QuizSettings {
+	return c.Quiz
+}
+
+// RoadmapSettings interaktiv yo'l xaritasi sozlamalarini qaytaradi
+func (c *Config) RoadmapSettings() RoadmapSettings {
+	return c.Roadmap
+}
+
+// AntispamSettings spam filtrlari sozlamalarini qaytaradi
+func (c *Config) AntispamSettings() AntispamSettings {
+	return c.Antispam
+}
+
+// FloodSettings flood nazorati sozlamalarini qaytaradi
+func (c *Config) FloodSettings() FloodSettings {
+	return c.Flood
+}
+
+// ReportSettings shikoyatlar sozlamalarini qaytaradi
+func (c *Config) ReportSettings() ReportSettings {
+	return c.Reports
+}
+
+// ModlogSettings moderatsiya jurnali sozlamalarini qaytaradi
+func (c *Config) ModlogSettings() ModlogSettings {
+	return c.Modlog
+}
+
+// StartSecret /start havolalari parametrlarini imzolash kalitini qaytaradi
+// Kalit sozlanmagan bo'lsa bot tokenidan hosil qilinadi, token o'zgarsa eski imzolangan havolalar ishlamay qoladi
+func (c *Config) StartSecret() string {
+	if c.Start.Secret != "" {
+		return c.Start.Secret
+	}
+	sum := sha256.Sum256([]byte("start-payload:" + c.TelegramToken))
+	return hex.EncodeToString(sum[:])
+}
+
 // LoadConfig konfiguratsiya sozlamalarini config.yaml faylidan yuklaydi
 // Bu funksiya dastur ishga tushganda eng birinchi chaqirilishi kerak
 func LoadConfig() *Config {
 	// Standart qiymatlar bilan konfiguratsiya obyektini yaratish
 	cfg := &Config{
-		LogLevel: "info",
-		Mode:     "polling",
+		LogLevel:        "info",
+		Mode:            "polling",
+		ShutdownTimeout: 30 * time.Second,
 	}
 	cfg.Webhook.Port = "8443" // Webhook uchun standart port
+	cfg.Storage.Driver = "bolt"
+	cfg.Storage.Path = filepath.Join("data", "bot.db")
+	cfg.Moderation.Warn = WarnPolicy{
+		MuteAfter:    3,
+		BanAfter:     5,
+		MuteDuration: 24 * time.Hour,
+	}
+	cfg.Captcha = CaptchaSettings{
+		Mode:    "question",
+		Timeout: 2 * time.Minute,
+	}
+	cfg.Dispatcher = DispatcherSettings{
+		Workers:         8,
+		QueueSize:       100,
+		Policy:          "block",
+		MetricsInterval: time.Minute,
+	}
+	cfg.Content = ContentSettings{
+		Dir:            "content",
+		ReloadInterval: 10 * time.Second,
+	}
+	cfg.Releases = ReleasesSettings{
+		SourceURL:       "https://go.dev/dl/?mode=json&include=all",
+		HistoryURL:      "https://go.dev/doc/devel/release",
+		RefreshInterval: time.Hour,
+		Timeout:         30 * time.Second,
+	}
+	cfg.Docs = DocsSettings{
+		Enabled: true,
+	}
+	cfg.Snippets = SnippetSettings{
+		MaxBytes: 3500,
+		Timeout:  10 * time.Second,
+	}
+	cfg.Run = RunSettings{
+		GoBinary:     "go",
+		BuildTimeout: 30 * time.Second,
+		RunTimeout:   5 * time.Second,
+		MemoryMB:     256,
+		MaxOutput:    3000,
+		Workers:      1,
+		QueueSize:    10,
+		UserQuota:    5,
+		QuotaWindow:  10 * time.Minute,
+	}
+	cfg.Quiz = QuizSettings{
+		Enabled:  true,
+		Bank:     filepath.Join("quiz", "questions.yaml"),
+		Timezone: "Asia/Tashkent",
+	}
+	cfg.Roadmap = RoadmapSettings{
+		File: filepath.Join("roadmap", "roadmap.yaml"),
+	}
+	cfg.Antispam.Enabled = true
+	cfg.Antispam.Links.Action = "delete"
+	cfg.Antispam.Newcomers.Messages = 5
+	cfg.Antispam.Newcomers.Action = "delete"
+	cfg.Antispam.Forwards.Action = "delete"
+	cfg.Antispam.Duplicates.Chats = 3
+	cfg.Antispam.Duplicates.Window = 10 * time.Minute
+	cfg.Antispam.Duplicates.MinLength = 30
+	cfg.Antispam.Duplicates.Action = "delete"
+	cfg.Flood = FloodSettings{
+		Enabled:      true,
+		Messages:     6,
+		Window:       10 * time.Second,
+		MuteAfter:    10,
+		MuteDuration: 10 * time.Minute,
+	}
+	cfg.Reports = ReportSettings{
+		Limit:        3,
+		Window:       10 * time.Minute,
+		MuteDuration: 24 * time.Hour,
+	}
 
 	// Birinchi navbatda "config.yaml" ni tekshiramiz
 	configPaths := []string{
@@ -116,11 +491,149 @@ func createDefaultConfigIfNeeded(cfg *Config) *Config {
 telegram_token: "" # Botfather tomonidan berilgan token
 log_level: "info"  # debug, info, warn, error
 mode: "polling"    # webhook yoki polling
+shutdown_timeout: "30s" # To'xtatishda ishlayotgan handlerlarni kutish muddati
 
 # Webhook sozlamalari (faqat webhook rejimida ishlatiladi)
 webhook:
-  url: ""          # https://example.com/your_token
+  url: ""          # https://example.com yoki https://example.com/telegram
   port: "8443"     # 8443, 443, 80, 88 yoki 8080
+  path: ""         # Endpoint yo'li, bo'sh bo'lsa URL dagi yo'l yoki bot tokeni ishlatiladi
+  secret_token: "" # X-Telegram-Bot-Api-Secret-Token qiymati, bo'sh bo'lsa avtomatik yaratiladi
+
+# Ma'lumotlarni saqlash sozlamalari
+storage:
+  driver: "bolt"       # bolt (fayl) yoki memory (xotira, faqat sinov uchun)
+  path: "data/bot.db"  # BoltDB fayli yo'li
+
+# Moderatsiya sozlamalari
+moderation:
+  warn:
+    mute_after: 3        # Nechta ogohlantirishdan keyin ovozsiz qilish (0 - o'chirilgan)
+    ban_after: 5         # Nechta ogohlantirishdan keyin guruhdan chetlatish (0 - o'chirilgan)
+    mute_duration: "24h" # Ovozsiz qilish muddati
+  chats: {}              # Guruh ID si bo'yicha alohida chegaralar
+
+# Yangi a'zolarni tekshirish (captcha)
+captcha:
+  enabled: false     # Yoqilganda yangi a'zo savolga javob bermaguncha yoza olmaydi
+  mode: "question"   # question (Go bo'yicha savol) yoki button (tugma topish)
+  timeout: "2m"      # Javob berish uchun vaqt
+
+# Yangilanishlarni taqsimlash
+dispatcher:
+  workers: 8               # Parallel ishchilar soni
+  queue_size: 100          # Har bir ishchi navbatining uzunligi
+  policy: "block"          # Navbat to'lganda: block (kutish) yoki drop (tashlab yuborish)
+  metrics_interval: "1m"   # Statistikani logga yozish oralig'i (0 - o'chirilgan)
+
+# Bot matnlari (Markdown va YAML fayllar)
+content:
+  dir: "content"           # Matn fayllari katalogi
+  reload_interval: "10s"   # O'zgarishlarni tekshirish oralig'i (0 - faqat /reload orqali)
+  owners: []               # /reload buyrug'ini bajara oladigan bot egalari ID lari, masalan [123456789]
+
+# Go relizlari (/latest va /version)
+releases:
+  source_url: "https://go.dev/dl/?mode=json&include=all" # Relizlar ro'yxati (URL yoki fayl yo'li)
+  history_url: "https://go.dev/doc/devel/release"        # Reliz tarixi, sanalar va xavfsizlik tuzatishlari uchun (bo'sh - o'chirilgan)
+  refresh_interval: "1h"   # Yangilash va yangi relizlarni e'lon qilish oralig'i (0 - faqat ishga tushganda)
+  timeout: "30s"           # Manbadan o'qish uchun eng ko'p vaqt
+
+# Standart kutubxona hujjatlari (/doc va inline qidiruv)
+docs:
+  enabled: true            # Indeksni ishga tushganda qurish
+  goroot: ""               # Go o'rnatilgan katalog (bo'sh - GOROOT yoki "go env GOROOT")
+
+# Kod parchalari (/fmt va /vet)
+snippets:
+  max_bytes: 3500          # Kodning eng katta hajmi (javob Telegram xabariga sig'ishi uchun)
+  timeout: "10s"           # Bitta formatlash yoki tekshiruv uchun eng ko'p vaqt
+
+# Go dasturlarini bajarish (/run), faqat Linux'da, user namespace'lar yoqilgan bo'lishi kerak
+run:
+  enabled: false           # Standart holatda o'chirilgan
+  go_binary: "go"          # go buyrug'i (PATH dagi nomi yoki to'liq yo'li)
+  work_dir: ""             # Vaqtinchalik kataloglar va kompilyatsiya keshi (bo'sh - tizimning vaqtinchalik katalogi)
+  build_timeout: "30s"     # Kompilyatsiya uchun eng ko'p vaqt
+  run_timeout: "5s"        # Dastur ishlashi uchun eng ko'p vaqt
+  memory_mb: 256           # Dastur xotirasi chegarasi
+  max_output: 3000         # Qaytariladigan chiqishning eng katta hajmi (baytlarda)
+  allowed_imports: []      # Ruxsat etilgan importlar (bo'sh - fmt, strings, sort va boshqa xavfsiz paketlar)
+  workers: 1               # Bir vaqtda bajariladigan dasturlar soni
+  queue_size: 10           # Navbatda kutishi mumkin bo'lgan dasturlar soni
+  user_quota: 5            # Bitta foydalanuvchi quota_window ichida ishga tushira oladigan dasturlar soni
+  quota_window: "10m"      # Kvota hisoblanadigan oraliq
+
+# Go viktorinasi (/quiz, /top va kun savoli)
+quiz:
+  enabled: true                  # Viktorina buyruqlari yoqilganmi
+  bank: "quiz/questions.yaml"    # Savollar banki (yo'l xaritasi bo'limlari bo'yicha)
+  timezone: "Asia/Tashkent"      # Kun savoli vaqti shu mintaqa bo'yicha hisoblanadi
+
+# Shaxsiy chatdagi interaktiv yo'l xaritasi (/roadmap)
+roadmap:
+  file: "roadmap/roadmap.yaml"   # Bo'limlar, mavzular va manbalar (bo'sh - faqat umumiy ko'rinish)
+
+# /start havolalari (https://t.me/<bot>?start=...)
+start:
+  secret: ""                     # Havola parametrlarini imzolash kaliti (bo'sh - bot tokenidan hosil qilinadi)
+
+# Guruhlardagi oddiy xabarlarni spamga tekshirish
+# Choralar: allow (o'tkazib yuborish), delete (o'chirish), warn (o'chirish va ogohlantirish), ban (o'chirish va chetlatish)
+# Adminlar xabarlari tekshirilmaydi
+antispam:
+  enabled: true
+  audit_chat: 0                  # Spam va flood choralari yoziladigan adminlar kanali ID si (0 - yozilmaydi)
+  links:
+    allow: ["go.dev", "golang.org", "github.com", "gitlab.com", "stackoverflow.com", "gobyexample.com"]
+    deny: ["bit.ly", "tinyurl.com", "cutt.ly"]
+    action: "delete"             # Taqiqlangan domenga havola uchun chora
+  newcomers:
+    messages: 5                  # Yangi a'zoning dastlabki xabarlarida havola (allow dan tashqari) va repostlar taqiqlanadi
+    action: "delete"
+  forwards:
+    action: "delete"             # Kanaldan uzatilgan xabarlar uchun chora
+    channels: []                 # Xabarlarini uzatish mumkin bo'lgan kanallar username'lari
+  duplicates:
+    chats: 3                     # Bir xil matn shuncha guruhda uchrasa spam hisoblanadi (0 - o'chirilgan)
+    window: 10m
+    min_length: 30               # Bundan qisqa matnlar tekshirilmaydi
+    action: "delete"             # Bir savolni bir nechta guruhga yozish ham shunga tushadi, ban ni ehtiyotkorlik bilan yoqing
+  rules:
+    - pattern: '\p{Han}{10,}'
+      action: "delete"
+      reason: "Xitoycha spam"
+    - pattern: '(?:\p{Arabic}+[\s\p{P}]*){8,}'
+      action: "delete"
+      reason: "Arabcha spam"
+    # crypto/rand kabi paket yo'llari qoidaga tushmaydi
+    - pattern: '(?i)\b(crypto|kripto|bitcoin|usdt|binance)\b(?:[^/]|$).*\b(profit|invest|investitsiya|earn|daromad\w*)\b'
+      action: "delete"
+      reason: "Kripto firibgarlik"
+
+# Guruhlarda juda tez yozishni cheklash
+# Chegaradan oshgan xabarlar o'chiriladi, flood davom etsa foydalanuvchi ovozsiz qilinadi va adminlarga xabar beriladi
+# Adminlar cheklanmaydi, /slowmode bu sozlamalardan qat'i nazar ishlaydi
+flood:
+  enabled: true
+  messages: 6                    # window ichida ruxsat etilgan xabarlar soni
+  window: 10s
+  mute_after: 10                 # window ichida shundan ko'p xabar yozilsa ovozsiz qilinadi (0 - ovozsiz qilinmaydi)
+  mute_duration: 10m
+
+# /report shikoyatlari
+# Shikoyat qilingan xabar chat, muallif va shikoyatchi haqidagi ma'lumot hamda choralar tugmalari bilan adminlarga yuboriladi
+reports:
+  chat: 0                        # Shikoyatlar yuboriladigan adminlar guruhi ID si (0 - har bir adminga shaxsiy xabar)
+  limit: 3                       # Bir a'zo window ichida yuborishi mumkin bo'lgan shikoyatlar soni
+  window: 10m
+  mute_duration: 24h             # "Ovozsiz qilish" tugmasi bilan beriladigan cheklov muddati
+
+# Moderatsiya jurnali
+# Adminlar va botning o'zi ko'rgan barcha choralar omborga yoziladi va jurnal kanaliga yuboriladi
+modlog:
+  channel: 0                     # Guruh /modlog channel bilan o'z kanalini belgilamagan bo'lsa ishlatiladigan kanal ID si (0 - yuborilmaydi)
+  export_token: ""               # Webhook serveridagi /modlog/export kaliti (bo'sh - eksport o'chirilgan)
 `
 
 	// Standart config faylini yaratish (configs papkasida)
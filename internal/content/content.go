@@ -0,0 +1,386 @@
+// Package content bot yuboradigan matnlarni fayllardan yuklash va shablon asosida tayyorlash uchun mo'ljallangan
+// Matnlar Markdown yoki YAML fayllarda saqlanadi, shuning uchun ularni tahrirlash uchun kodni o'zgartirish shart emas
+//
+// Katalogning o'zidagi fayllar asosiy til (o'zbek, lotin) matnlari hisoblanadi,
+// boshqa tillar uchun tarjimalar til nomi bilan atalgan ichki kataloglarda saqlanadi (masalan, content/ru).
+// O'zbekcha matnlarning kirill varianti lotin matnidan avtomatik yasaladi, shuning uchun ularni alohida yozish shart emas.
+package content
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"tg-bot/internal/i18n"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Telegram qo'llab-quvvatlaydigan formatlash rejimlari
+const (
+	ParseModePlain      = ""           // Formatlashsiz oddiy matn
+	ParseModeHTML       = "HTML"       // HTML teglari bilan formatlash
+	ParseModeMarkdownV2 = "MarkdownV2" // MarkdownV2 belgilari bilan formatlash
+)
+
+// ErrNotFound so'ralgan kalit bo'yicha matn topilmaganda qaytariladi
+var ErrNotFound = errors.New("content: matn topilmadi")
+
+// Message yuborishga tayyor matn va uning formatlash rejimi
+type Message struct {
+	Text      string // Shablondan tayyorlangan matn
+	ParseMode string // Telegram formatlash rejimi (bo'sh bo'lsa oddiy matn)
+}
+
+// Vars shablonlarga beriladigan o'zgaruvchilar
+// Qiymatlar xom holda beriladi va matnning formatlash rejimiga qarab avtomatik ekranlanadi
+type Vars struct {
+	BotUsername string            // Botning @ belgisisiz username'i
+	ChatTitle   string            // Guruh nomi (shaxsiy chatda bo'sh)
+	UserID      int64             // Foydalanuvchi ID si, eslatma havolasi uchun
+	UserName    string            // Foydalanuvchining @ belgisisiz username'i
+	FirstName   string            // Foydalanuvchi ismi
+	Args        map[string]string // Muayyan xabarga xos qo'shimcha qiymatlar
+}
+
+// templateData shablon ichida ko'rinadigan, allaqachon ekranlangan qiymatlar
+type templateData struct {
+	BotUsername string
+	ChatTitle   string
+	UserName    string
+	UserMention string
+	Args        map[string]string
+}
+
+// entry bitta matn shabloni
+type entry struct {
+	tmpl      *template.Template
+	text      string // Shablonning asl matni, transliteratsiya uchun
+	parseMode string
+	source    string // Shablon yuklangan fayl, xatolik xabarlari uchun
+}
+
+// catalog til bo'yicha guruhlangan matnlar
+type catalog map[i18n.Locale]map[string]entry
+
+// Store katalogdan yuklangan matnlar to'plami
+// Matnlar qayta yuklanganda yangi to'plam to'liq tekshiriladi va faqat xatosiz bo'lsa almashtiriladi
+type Store struct {
+	dir string
+
+	mu       sync.RWMutex
+	entries  catalog
+	required []string
+	stamp    string // Fayllarning oxirgi yuklangan holati, o'zgarishlarni aniqlash uchun
+}
+
+// Load dir katalogidagi va uning til kataloglaridagi barcha .md, .yaml va .yml fayllarni yuklaydi va tekshiradi
+func Load(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Require ko'rsatilgan kalitlar asosiy tilda mavjud bo'lishini talab qiladi
+// Boshqa tillarda yo'q matnlar asosiy tildan olinadi
+// Talab qilingan matni yo'q to'plam keyinchalik qayta yuklashda ham rad etiladi
+func (s *Store) Require(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkRequired(s.entries, keys); err != nil {
+		return err
+	}
+	s.required = append(s.required, keys...)
+	return nil
+}
+
+// Reload matnlarni katalogdan qaytadan yuklaydi
+// Xatolik yuz bersa avvalgi matnlar o'zgarishsiz qoladi
+func (s *Store) Reload() error {
+	stamp, err := snapshot(s.dir)
+	if err != nil {
+		return err
+	}
+	entries, err := loadCatalog(s.dir)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := checkRequired(entries, s.required); err != nil {
+		return err
+	}
+	s.entries = entries
+	s.stamp = stamp
+	return nil
+}
+
+// Len asosiy tildagi matnlar sonini qaytaradi
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries[i18n.Default])
+}
+
+// Render kalit bo'yicha shablonni berilgan til va o'zgaruvchilar bilan to'ldiradi
+// Tanlangan tilda tarjima bo'lmasa, matn tilning zaxira tillari ketma-ketligidan olinadi
+func (s *Store) Render(locale i18n.Locale, key string, vars Vars) (Message, error) {
+	var (
+		e  entry
+		ok bool
+	)
+	s.mu.RLock()
+	for _, l := range locale.Fallbacks() {
+		if e, ok = s.entries[l][key]; ok {
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	text, err := e.execute(vars)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Text: text, ParseMode: e.parseMode}, nil
+}
+
+// execute shablonni o'zgaruvchilarni formatlash rejimiga mos ekranlab to'ldiradi
+func (e entry) execute(vars Vars) (string, error) {
+	args := make(map[string]string, len(vars.Args))
+	for k, v := range vars.Args {
+		args[k] = Escape(e.parseMode, v)
+	}
+	data := templateData{
+		BotUsername: Escape(e.parseMode, vars.BotUsername),
+		ChatTitle:   Escape(e.parseMode, vars.ChatTitle),
+		UserName:    Escape(e.parseMode, vars.UserName),
+		UserMention: Mention(e.parseMode, vars.UserID, vars.UserName, vars.FirstName),
+		Args:        args,
+	}
+
+	var buf bytes.Buffer
+	if err := e.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%s: %w", e.source, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// loadCatalog asosiy katalog va til kataloglaridagi matnlarni yuklaydi
+// O'zbek tilining bir yozuvida yo'q matnlar ikkinchi yozuvdagi matndan transliteratsiya qilib yasaladi
+func loadCatalog(dir string) (catalog, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("matnlar katalogini o'qib bo'lmadi: %w", err)
+	}
+
+	root, err := loadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := catalog{i18n.Default: root}
+
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		locale, ok := i18n.Parse(file.Name())
+		if !ok || string(locale) != strings.ToLower(file.Name()) {
+			return nil, fmt.Errorf("%s: noma'lum til katalogi, mavjud tillar: %v", filepath.Join(dir, file.Name()), i18n.Supported)
+		}
+		if locale == i18n.Default {
+			return nil, fmt.Errorf("%s: asosiy til matnlari katalogning o'zida saqlanadi", filepath.Join(dir, file.Name()))
+		}
+		if entries[locale], err = loadDir(filepath.Join(dir, file.Name())); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := transliterate(entries, i18n.UzLatn, i18n.UzCyrl, i18n.ToCyrillic); err != nil {
+		return nil, err
+	}
+	if err := transliterate(entries, i18n.UzCyrl, i18n.UzLatn, i18n.ToLatin); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// transliterate from tilidagi matnlardan to tilida yo'qlarini convert orqali yasaydi
+func transliterate(entries catalog, from, to i18n.Locale, convert func(string) string) error {
+	if entries[to] == nil {
+		entries[to] = make(map[string]entry)
+	}
+	for key, e := range entries[from] {
+		if _, exists := entries[to][key]; exists {
+			continue
+		}
+		derived, err := newEntry(key, convert(e.text), e.parseMode, e.source+" ("+string(to)+")")
+		if err != nil {
+			return err
+		}
+		entries[to][key] = derived
+	}
+	return nil
+}
+
+// loadDir katalogdagi barcha matn fayllarini o'qiydi, shablonlarni tahlil qiladi va sinov qiymatlari bilan bajarib ko'radi
+func loadDir(dir string) (map[string]entry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("matnlar katalogini o'qib bo'lmadi: %w", err)
+	}
+
+	entries := make(map[string]entry)
+	add := func(key, text, mode, source string) error {
+		if prev, exists := entries[key]; exists {
+			return fmt.Errorf("%s: %q kaliti %s faylida ham mavjud", source, key, prev.source)
+		}
+		e, err := newEntry(key, text, mode, source)
+		if err != nil {
+			return err
+		}
+		entries[key] = e
+		return nil
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		ext := strings.ToLower(filepath.Ext(file.Name()))
+
+		switch ext {
+		case ".md":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			mode, body, err := splitFrontMatter(string(data))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if err := add(strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())), body, mode, path); err != nil {
+				return nil, err
+			}
+		case ".yaml", ".yml":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			var items map[string]yamlEntry
+			if err := yaml.Unmarshal(data, &items); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			for key, item := range items {
+				if err := add(key, item.Text, item.ParseMode, path); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// newEntry shablonni tahlil qiladi va uni sinov qiymatlari bilan bajarib, xatolarni oldindan aniqlaydi
+func newEntry(key, text, mode, source string) (entry, error) {
+	parseMode, err := normalizeParseMode(mode)
+	if err != nil {
+		return entry{}, fmt.Errorf("%s: %q: %w", source, key, err)
+	}
+
+	tmpl, err := template.New(key).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return entry{}, fmt.Errorf("%s: %w", source, err)
+	}
+
+	e := entry{tmpl: tmpl, text: text, parseMode: parseMode, source: source}
+	sample := Vars{BotUsername: "bot", ChatTitle: "Guruh", UserID: 1, UserName: "gopher", FirstName: "Gopher"}
+	if _, err := e.execute(sample); err != nil {
+		return entry{}, err
+	}
+	return e, nil
+}
+
+// yamlEntry YAML fayldagi bitta matn: oddiy satr yoki text va parse_mode maydonli obyekt
+type yamlEntry struct {
+	Text      string `yaml:"text"`
+	ParseMode string `yaml:"parse_mode"`
+}
+
+// UnmarshalYAML qisqa (faqat matn) va to'liq yozuv shakllarini qabul qiladi
+func (y *yamlEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		y.Text = node.Value
+		return nil
+	}
+	type plain yamlEntry
+	return node.Decode((*plain)(y))
+}
+
+// splitFrontMatter Markdown fayl boshidagi "---" bilan ajratilgan YAML sarlavhani matndan ajratadi
+func splitFrontMatter(data string) (parseMode string, body string, err error) {
+	data = strings.TrimPrefix(data, "\ufeff")
+	if !strings.HasPrefix(data, "---\n") {
+		return "", data, nil
+	}
+
+	rest := data[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return "", "", errors.New("sarlavha yopilmagan")
+	}
+
+	var meta struct {
+		ParseMode string `yaml:"parse_mode"`
+	}
+	if err := yaml.Unmarshal([]byte(rest[:end]), &meta); err != nil {
+		return "", "", err
+	}
+
+	body = strings.TrimPrefix(rest[end+len("\n---"):], "\n")
+	return meta.ParseMode, body, nil
+}
+
+// normalizeParseMode fayldagi formatlash rejimi nomini Telegram qabul qiladigan shaklga keltiradi
+func normalizeParseMode(mode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "plain", "text":
+		return ParseModePlain, nil
+	case "html":
+		return ParseModeHTML, nil
+	case "markdownv2", "markdown":
+		return ParseModeMarkdownV2, nil
+	default:
+		return "", fmt.Errorf("noma'lum formatlash rejimi %q", mode)
+	}
+}
+
+// checkRequired barcha talab qilingan kalitlar mavjudligini tekshiradi
+func checkRequired(entries catalog, keys []string) error {
+	var missing []string
+	for _, key := range keys {
+		if _, ok := entries[i18n.Default][key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("quyidagi matnlar topilmadi: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
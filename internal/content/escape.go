@@ -0,0 +1,54 @@
+package content
+
+import (
+	"strconv"
+	"strings"
+)
+
+// htmlEscaper Telegram HTML rejimida maxsus ma'noga ega belgilarni almashtiradi
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// markdownV2Escaper Telegram MarkdownV2 rejimida ekranlanishi shart bo'lgan barcha belgilarni ekranlaydi
+var markdownV2Escaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// Escape matnni formatlash rejimiga mos ravishda ekranlaydi
+// Oddiy matn rejimida matn o'zgarishsiz qaytariladi
+func Escape(parseMode, text string) string {
+	switch parseMode {
+	case ParseModeHTML:
+		return htmlEscaper.Replace(text)
+	case ParseModeMarkdownV2:
+		return markdownV2Escaper.Replace(text)
+	default:
+		return text
+	}
+}
+
+// Mention foydalanuvchini eslatish uchun formatlash rejimiga mos matn yasaydi
+// HTML va MarkdownV2 rejimlarida foydalanuvchi profiliga havola, oddiy matnda esa @username yoki ism qaytariladi
+func Mention(parseMode string, userID int64, userName, firstName string) string {
+	name := firstName
+	if name == "" {
+		name = userName
+	}
+	if name == "" {
+		name = "Foydalanuvchi"
+	}
+
+	if userID == 0 || parseMode == ParseModePlain {
+		if parseMode == ParseModePlain && userName != "" {
+			return "@" + userName
+		}
+		return Escape(parseMode, name)
+	}
+
+	link := "tg://user?id=" + strconv.FormatInt(userID, 10)
+	if parseMode == ParseModeHTML {
+		return `<a href="` + link + `">` + Escape(parseMode, name) + "</a>"
+	}
+	return "[" + Escape(parseMode, name) + "](" + link + ")"
+}
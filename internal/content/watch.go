@@ -0,0 +1,83 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"tg-bot/pkg/logger"
+)
+
+// Watch katalogdagi fayllarni interval oralig'ida tekshiradi va o'zgarish bo'lsa matnlarni qayta yuklaydi
+// Qayta yuklashda xatolik bo'lsa u log qilinadi va avvalgi matnlar ishlatilishda davom etadi
+// ctx bekor qilinguncha ishlaydi, interval nol bo'lsa darhol qaytadi
+func (s *Store) Watch(ctx context.Context, interval time.Duration, log *logger.Logger) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stamp, err := snapshot(s.dir)
+			if err != nil {
+				log.Warnf("Matnlar katalogini tekshirishda xatolik: %v", err)
+				continue
+			}
+
+			s.mu.RLock()
+			changed := stamp != s.stamp
+			s.mu.RUnlock()
+			if !changed {
+				continue
+			}
+
+			if err := s.Reload(); err != nil {
+				log.Errorf("Matnlarni qayta yuklashda xatolik, avvalgi matnlar ishlatiladi: %v", err)
+				// Xato fayl tuzatilmaguncha har safar qayta urinmaslik uchun holatni eslab qolamiz
+				s.mu.Lock()
+				s.stamp = stamp
+				s.mu.Unlock()
+				continue
+			}
+			log.Infof("Matnlar qayta yuklandi: %d ta", s.Len())
+		}
+	}
+}
+
+// snapshot katalog va til kataloglaridagi fayllar nomi, hajmi va o'zgartirilgan vaqtidan iborat qisqa ko'rinish yasaydi
+func snapshot(dir string) (string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("matnlar katalogini o'qib bo'lmadi: %w", err)
+	}
+
+	parts := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			// Til kataloglaridagi fayllar ham kuzatiladi
+			sub, err := snapshot(filepath.Join(dir, file.Name()))
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, file.Name()+"/["+sub+"]")
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", file.Name(), info.Size(), info.ModTime().UnixNano()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|"), nil
+}
@@ -0,0 +1,134 @@
+// Package conversation bir nechta xabardan iborat suhbatlar (dialoglar) holatini saqlaydi
+// Har bir suhbat (chat, foydalanuvchi) juftligiga bog'langan va omborda saqlanadi,
+// shuning uchun bot qayta ishga tushganda ham foydalanuvchi suhbatni davom ettira oladi
+package conversation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-bot/internal/storage"
+)
+
+// bucket suhbatlar holati saqlanadigan bucket ("<chat ID>:<foydalanuvchi ID>" kaliti bo'yicha State)
+const bucket = "conversations"
+
+// State suhbatning joriy holati
+type State struct {
+	Flow    string            `json:"flow"`    // Suhbat turi, masalan setrules yoki report
+	Step    int               `json:"step"`    // Joriy qadam tartib raqami
+	Data    map[string]string `json:"data"`    // Oldingi qadamlarda yig'ilgan javoblar
+	Expires time.Time         `json:"expires"` // Shu vaqtgacha javob bo'lmasa suhbat bekor qilinadi
+}
+
+// Expired suhbat muddati o'tganini tekshiradi
+func (s State) Expired(now time.Time) bool {
+	return now.After(s.Expires)
+}
+
+// Key suhbat egasi
+type Key struct {
+	ChatID int64
+	UserID int64
+}
+
+// String Key ning ombordagi ko'rinishi
+func (k Key) String() string {
+	return fmt.Sprintf("%d:%d", k.ChatID, k.UserID)
+}
+
+// parseKey ombordagi kalitni Key ga aylantiradi
+func parseKey(s string) (Key, bool) {
+	chat, user, ok := strings.Cut(s, ":")
+	if !ok {
+		return Key{}, false
+	}
+	chatID, err1 := strconv.ParseInt(chat, 10, 64)
+	userID, err2 := strconv.ParseInt(user, 10, 64)
+	if err1 != nil || err2 != nil {
+		return Key{}, false
+	}
+	return Key{ChatID: chatID, UserID: userID}, true
+}
+
+// Store suhbatlar holatini omborda yuritadi
+type Store struct {
+	store storage.Store
+	mu    sync.Mutex // Muddati o'tgan suhbatlarni o'chirish va yangi javoblarni ketma-ket bajarish uchun
+}
+
+// New suhbatlar omborini yaratadi
+func New(store storage.Store) *Store {
+	return &Store{store: store}
+}
+
+// Get faol suhbatni qaytaradi, suhbat bo'lmasa ok false bo'ladi
+// Muddati o'tgan suhbat ham qaytariladi, uni tekshirish chaqiruvchining vazifasi
+func (s *Store) Get(key Key) (state State, ok bool, err error) {
+	err = storage.GetJSON(s.store, bucket, key.String(), &state)
+	if errors.Is(err, storage.ErrNotFound) {
+		return State{}, false, nil
+	}
+	if err != nil {
+		return State{}, false, err
+	}
+	if state.Data == nil {
+		state.Data = make(map[string]string)
+	}
+	return state, true, nil
+}
+
+// Put suhbat holatini saqlaydi (avvalgi suhbat bo'lsa u almashtiriladi)
+func (s *Store) Put(key Key, state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return storage.PutJSON(s.store, bucket, key.String(), state)
+}
+
+// Delete suhbatni yakunlaydi
+func (s *Store) Delete(key Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.Delete(bucket, key.String())
+}
+
+// Expire muddati o'tgan suhbatlarni o'chiradi va ularni egalari bilan birga qaytaradi
+func (s *Store) Expire(now time.Time) (map[Key]State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := make(map[Key]State)
+	var broken []string
+	err := s.store.Scan(bucket, "", func(k string, value []byte) error {
+		var state State
+		key, ok := parseKey(k)
+		if !ok || json.Unmarshal(value, &state) != nil {
+			broken = append(broken, k)
+			return nil
+		}
+		if state.Expired(now) {
+			expired[key] = state
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range broken {
+		if err := s.store.Delete(bucket, k); err != nil {
+			return nil, err
+		}
+	}
+	for key := range expired {
+		if err := s.store.Delete(bucket, key.String()); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
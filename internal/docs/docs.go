@@ -0,0 +1,340 @@
+// Package docs standart kutubxona hujjatlari bo'yicha qidiruv indeksi
+// Indeks bot ishlayotgan kompyuterdagi GOROOT manba kodidan go/parser va go/doc yordamida quriladi,
+// shuning uchun tarmoqqa ulanishsiz ishlaydi va o'rnatilgan Go versiyasiga mos keladi
+package docs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Imzo va hujjat matni uchun chegaralar
+const (
+	maxSignatureLines = 20  // Uzun struct va const bloklari shu qatordan keyin qisqartiriladi
+	maxDocLength      = 700 // Hujjat matnining eng katta uzunligi
+)
+
+// ErrNotReady indeks hali qurilmaganini bildiradi
+var ErrNotReady = errors.New("docs: indeks hali tayyor emas")
+
+// Kind belgi turi
+type Kind string
+
+// Belgi turlari
+const (
+	KindPackage Kind = "package"
+	KindFunc    Kind = "func"
+	KindType    Kind = "type"
+	KindMethod  Kind = "method"
+	KindConst   Kind = "const"
+	KindVar     Kind = "var"
+)
+
+// Symbol indeksdagi bitta hujjatlashtirilgan belgi
+type Symbol struct {
+	ImportPath string // Paketning import yo'li, masalan net/http
+	Package    string // Paket nomi, masalan http
+	Name       string // Belgi nomi: Cut, WaitGroup yoki WaitGroup.Add; paketning o'zi uchun bo'sh
+	Kind       Kind   // Belgi turi
+	Signature  string // E'lon qilinishi (funksiya imzosi, tur ta'rifi)
+	Doc        string // Qisqartirilgan hujjat izohi
+}
+
+// FullName belgining to'liq nomi, masalan strings.Cut
+func (s Symbol) FullName() string {
+	if s.Name == "" {
+		return s.ImportPath
+	}
+	return s.Package + "." + s.Name
+}
+
+// URL belgining pkg.go.dev dagi sahifasi
+func (s Symbol) URL() string {
+	if s.Name == "" {
+		return "https://pkg.go.dev/" + s.ImportPath
+	}
+	return "https://pkg.go.dev/" + s.ImportPath + "#" + s.Name
+}
+
+// Index standart kutubxona belgilari indeksi
+// Build tugaguncha qidiruv ErrNotReady qaytaradi, keyin esa bir vaqtda bir nechta goroutine'dan ishlatilishi mumkin
+type Index struct {
+	mu      sync.RWMutex
+	symbols []Symbol
+	byKey   map[string][]int // Kichik harfli "paket.Belgi" va "import/yo'l.Belgi" kalitlari
+	ready   bool
+}
+
+// NewIndex bo'sh indeks yaratadi
+func NewIndex() *Index {
+	return &Index{}
+}
+
+// GOROOT standart kutubxona manba kodi joylashgan katalogni aniqlaydi
+// Tartib: sozlamalardagi qiymat, GOROOT muhit o'zgaruvchisi, `go env GOROOT` natijasi
+func GOROOT(configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	if env := os.Getenv("GOROOT"); env != "" {
+		return env, nil
+	}
+	out, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		return "", fmt.Errorf("GOROOT aniqlanmadi: %w", err)
+	}
+	root := strings.TrimSpace(string(out))
+	if root == "" {
+		return "", errors.New("GOROOT aniqlanmadi")
+	}
+	return root, nil
+}
+
+// Build goroot/src ichidagi barcha ommaviy paketlarni tahlil qiladi va indeksni quradi
+// internal, vendor, testdata va cmd kataloglari o'tkazib yuboriladi
+func (x *Index) Build(goroot string) (time.Duration, error) {
+	start := time.Now()
+	src := filepath.Join(goroot, "src")
+	if _, err := os.Stat(src); err != nil {
+		return 0, fmt.Errorf("standart kutubxona manbasi topilmadi: %w", err)
+	}
+
+	var symbols []Symbol
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != src && (name == "internal" || name == "vendor" || name == "testdata" || name == "cmd" ||
+			strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+
+		importPath, err := filepath.Rel(src, path)
+		if err != nil || importPath == "." {
+			return nil
+		}
+		pkgSymbols, err := parsePackage(path, filepath.ToSlash(importPath))
+		if err != nil {
+			// Joriy platformaga mos fayli yo'q kataloglar (masalan, faqat testlar) shunchaki o'tkazib yuboriladi
+			return nil
+		}
+		symbols = append(symbols, pkgSymbols...)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(symbols) == 0 {
+		return 0, fmt.Errorf("%s ichida birorta ham paket topilmadi", src)
+	}
+
+	byKey := make(map[string][]int, len(symbols)*2)
+	for i, s := range symbols {
+		for _, key := range keys(s) {
+			byKey[key] = append(byKey[key], i)
+		}
+	}
+	// Bir xil nomli paketlarda qisqaroq import yo'li (masalan, crypto/rand emas, math/rand) birinchi turadi
+	for _, ids := range byKey {
+		sort.SliceStable(ids, func(a, b int) bool {
+			return len(symbols[ids[a]].ImportPath) < len(symbols[ids[b]].ImportPath)
+		})
+	}
+
+	x.mu.Lock()
+	x.symbols = symbols
+	x.byKey = byKey
+	x.ready = true
+	x.mu.Unlock()
+	return time.Since(start), nil
+}
+
+// Len indeksdagi belgilar soni
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.symbols)
+}
+
+// keys belgini qidirish kalitlari
+func keys(s Symbol) []string {
+	if s.Name == "" {
+		keys := []string{strings.ToLower(s.ImportPath)}
+		if s.Package != s.ImportPath {
+			keys = append(keys, strings.ToLower(s.Package))
+		}
+		return keys
+	}
+	keys := []string{strings.ToLower(s.Package + "." + s.Name)}
+	if s.Package != s.ImportPath {
+		keys = append(keys, strings.ToLower(s.ImportPath+"."+s.Name))
+	}
+	return keys
+}
+
+// parsePackage bitta katalogdagi paketni tahlil qilib uning ommaviy belgilarini qaytaradi
+// Faqat joriy GOOS/GOARCH uchun yig'iladigan fayllar olinadi, shunda bir belgining platformaga xos nusxalari takrorlanmaydi
+func parsePackage(dir, importPath string) ([]Symbol, error) {
+	bp, err := build.Default.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	if bp.Name == "main" || len(bp.GoFiles) == 0 {
+		return nil, errors.New("ommaviy paket emas")
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := []Symbol{{
+		ImportPath: importPath,
+		Package:    pkg.Name,
+		Kind:       KindPackage,
+		Signature:  "import \"" + importPath + "\"",
+		Doc:        trimDoc(pkg.Doc),
+	}}
+	add := func(name string, kind Kind, node ast.Node, comment string) {
+		symbols = append(symbols, Symbol{
+			ImportPath: importPath,
+			Package:    pkg.Name,
+			Name:       name,
+			Kind:       kind,
+			Signature:  signature(fset, node),
+			Doc:        trimDoc(comment),
+		})
+	}
+	addValues := func(values []*doc.Value, kind Kind) {
+		for _, v := range values {
+			for _, name := range v.Names {
+				if ast.IsExported(name) {
+					add(name, kind, valueDecl(fset, v.Decl, name), v.Doc)
+				}
+			}
+		}
+	}
+
+	addValues(pkg.Consts, KindConst)
+	addValues(pkg.Vars, KindVar)
+	for _, f := range pkg.Funcs {
+		add(f.Name, KindFunc, f.Decl, f.Doc)
+	}
+	for _, t := range pkg.Types {
+		add(t.Name, KindType, t.Decl, t.Doc)
+		addValues(t.Consts, KindConst)
+		addValues(t.Vars, KindVar)
+		// Konstruktorlar (masalan, NewReader) paket funksiyalari sifatida qidiriladi
+		for _, f := range t.Funcs {
+			add(f.Name, KindFunc, f.Decl, f.Doc)
+		}
+		for _, m := range t.Methods {
+			add(t.Name+"."+m.Name, KindMethod, m.Decl, m.Doc)
+		}
+	}
+	return symbols, nil
+}
+
+// valueDecl katta const yoki var blokidan faqat kerakli nom e'lon qilingan qismini ajratadi
+// Kichik bloklar to'liq ko'rsatiladi, chunki iota qiymatlari qo'shni qatorlarsiz tushunarsiz bo'ladi
+func valueDecl(fset *token.FileSet, decl *ast.GenDecl, name string) ast.Node {
+	if len(decl.Specs) <= 1 || fset.Position(decl.End()).Line-fset.Position(decl.Pos()).Line < maxSignatureLines {
+		return decl
+	}
+	for _, spec := range decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for _, ident := range vs.Names {
+			if ident.Name == name {
+				single := *vs
+				single.Doc = nil
+				single.Comment = nil
+				return &ast.GenDecl{Tok: decl.Tok, Specs: []ast.Spec{&single}}
+			}
+		}
+	}
+	return decl
+}
+
+// signature e'lonni izohlarsiz va funksiya tanasisiz matnga o'giradi
+func signature(fset *token.FileSet, node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		decl := *n
+		decl.Doc = nil
+		decl.Body = nil
+		node = &decl
+	case *ast.GenDecl:
+		decl := *n
+		decl.Doc = nil
+		node = &decl
+	}
+
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 4}
+	if err := cfg.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) > maxSignatureLines {
+		lines = append(lines[:maxSignatureLines], "    // ...")
+		// Qisqartirilgan blokni yopib qo'yamiz
+		if last := strings.TrimSpace(lines[0]); strings.HasSuffix(last, "{") {
+			lines = append(lines, "}")
+		} else if strings.HasSuffix(last, "(") {
+			lines = append(lines, ")")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimDoc hujjat izohini bir necha birinchi xatboshigacha qisqartiradi
+func trimDoc(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= maxDocLength {
+		return text
+	}
+
+	// Iloji bo'lsa butun xatboshilarni saqlaymiz
+	cut := strings.LastIndex(text[:maxDocLength], "\n\n")
+	if cut <= 0 {
+		cut = strings.LastIndexFunc(text[:maxDocLength], unicode.IsSpace)
+	}
+	if cut <= 0 {
+		cut = maxDocLength
+	}
+	return strings.TrimSpace(text[:cut]) + " …"
+}
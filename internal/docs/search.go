@@ -0,0 +1,177 @@
+package docs
+
+import (
+	"sort"
+	"strings"
+)
+
+// normalize so'rovni qidiruv kalitiga o'giradi
+// "strings.Cut()", "(*sync.WaitGroup).Add" va "sync.WaitGroup#Add" kabi yozuvlar ham tushuniladi
+func normalize(query string) string {
+	q := strings.TrimSpace(query)
+	q = strings.NewReplacer("(", "", ")", "", "*", "", "#", ".", " ", "").Replace(q)
+	return strings.ToLower(strings.Trim(q, "."))
+}
+
+// Lookup so'rovga aniq mos keladigan belgilarni qaytaradi
+// Bir nechta paketda bir xil nomli belgi bo'lsa (rand.Int), hammasi qisqaroq import yo'lidan boshlab qaytariladi
+func (x *Index) Lookup(query string) ([]Symbol, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if !x.ready {
+		return nil, ErrNotReady
+	}
+
+	ids := x.byKey[normalize(query)]
+	found := make([]Symbol, 0, len(ids))
+	for _, id := range ids {
+		found = append(found, x.symbols[id])
+	}
+	return found, nil
+}
+
+// Closest xato yozilgan so'rovga eng yaqin belgini topadi
+// Tahrirlash masofasi so'rov uzunligiga nisbatan katta bo'lsa hech narsa qaytarilmaydi
+func (x *Index) Closest(query string) (Symbol, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if !x.ready {
+		return Symbol{}, false
+	}
+
+	q := normalize(query)
+	if q == "" {
+		return Symbol{}, false
+	}
+	limit := max(2, len(q)/4)
+
+	best, bestKey, bestDist := -1, "", limit+1
+	for key, ids := range x.byKey {
+		// Uzunligi juda farq qiladigan kalitlar uchun masofani hisoblab o'tirmaymiz
+		if abs(len(key)-len(q)) > limit {
+			continue
+		}
+		// Chegara bestDist+1: teng masofadagi kalitlar ham aniq hisoblanadi
+		d := distance(q, key, bestDist+1)
+		if d > limit {
+			continue
+		}
+		// Teng masofada qisqaroq va alifbo bo'yicha oldingi kalit tanlanadi, shunda natija barqaror bo'ladi
+		if d < bestDist || (d == bestDist && (len(key) < len(bestKey) || (len(key) == len(bestKey) && key < bestKey))) {
+			best, bestKey, bestDist = ids[0], key, d
+		}
+	}
+	if best < 0 {
+		return Symbol{}, false
+	}
+	return x.symbols[best], true
+}
+
+// Search inline rejim uchun so'rovga mos belgilarni ahamiyati bo'yicha qaytaradi
+// Avval aniq moslik, keyin prefiks, keyin nom ichida uchraydigan belgilar, oxirida eng yaqin xato yozuv
+func (x *Index) Search(query string, limit int) ([]Symbol, error) {
+	x.mu.RLock()
+	if !x.ready {
+		x.mu.RUnlock()
+		return nil, ErrNotReady
+	}
+
+	q := normalize(query)
+	if q == "" {
+		x.mu.RUnlock()
+		return nil, nil
+	}
+
+	type match struct {
+		id    int
+		rank  int
+		key   string
+		order int
+	}
+	var matches []match
+	seen := make(map[int]bool)
+	for key, ids := range x.byKey {
+		rank := -1
+		switch {
+		case key == q:
+			rank = 0
+		case strings.HasPrefix(key, q):
+			rank = 1
+		case strings.Contains(key, q):
+			rank = 2
+		}
+		if rank < 0 {
+			continue
+		}
+		for order, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				matches = append(matches, match{id: id, rank: rank, key: key, order: order})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if len(a.key) != len(b.key) {
+			return len(a.key) < len(b.key)
+		}
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		return a.order < b.order
+	})
+
+	var found []Symbol
+	for _, m := range matches {
+		if len(found) == limit {
+			break
+		}
+		found = append(found, x.symbols[m.id])
+	}
+	x.mu.RUnlock()
+
+	if len(found) == 0 {
+		if s, ok := x.Closest(query); ok {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+// distance ikki satr orasidagi Levenshtein masofasini hisoblaydi
+// Masofa bound dan oshishi aniq bo'lganda hisoblash to'xtatiladi va bound qaytariladi
+func distance(a, b string, bound int) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin >= bound {
+			return bound
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,140 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testSources kichik soxta GOROOT uchun paketlar
+var testSources = map[string]string{
+	"strings/strings.go": `// Package strings satrlar bilan ishlaydi
+package strings
+
+// Cut s ni sep atrofida ikkiga bo'ladi
+func Cut(s, sep string) (before, after string, found bool) { return }
+
+// Count sep necha marta uchrashini sanaydi
+func Count(s, sep string) int { return 0 }
+
+// Builder satrni bo'laklab yig'adi
+type Builder struct{}
+
+// WriteString satr qo'shadi
+func (b *Builder) WriteString(s string) (int, error) { return 0, nil }
+`,
+	"sync/sync.go": `// Package sync sinxronlash vositalari
+package sync
+
+// WaitGroup goroutine'larni kutadi
+type WaitGroup struct{}
+
+// Add hisoblagichni oshiradi
+func (wg *WaitGroup) Add(delta int) {}
+`,
+	"math/rand/rand.go": `// Package rand tasodifiy sonlar
+package rand
+
+// Int tasodifiy son
+func Int() int { return 0 }
+`,
+	"crypto/rand/rand.go": `// Package rand kriptografik tasodifiy sonlar
+package rand
+
+// Int tasodifiy katta son
+func Int() int { return 0 }
+`,
+}
+
+// newTestIndex testSources dan indeks quradi
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+	root := t.TempDir()
+	for name, src := range testSources {
+		path := filepath.Join(root, "src", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	x := NewIndex()
+	if _, err := x.Build(root); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return x
+}
+
+func TestClosest(t *testing.T) {
+	x := newTestIndex(t)
+	tests := []struct {
+		query string
+		want  string // Bo'sh bo'lsa hech narsa topilmasligi kerak
+	}{
+		{"strings.Cutt", "strings.Cut"},
+		{"strngs.Cut", "strings.Cut"},
+		{"Strings.cUt()", "strings.Cut"},
+		{"strings.Counr", "strings.Count"},
+		{"sync.WaitGrop", "sync.WaitGroup"},
+		{"sync.WaitGroup.Ad", "sync.WaitGroup.Add"},
+		{"(*strings.Builder).WriteStrng", "strings.Builder.WriteString"},
+		{"rand.Intt", "rand.Int"},
+		{"net/http.Get", ""},
+		{"xyz", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		s, ok := x.Closest(tt.query)
+		got := ""
+		if ok {
+			got = s.FullName()
+		}
+		if got != tt.want {
+			t.Errorf("Closest(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	// Bir xil nomli paketlarda qisqaroq import yo'li tanlanadi
+	if s, _ := x.Closest("rand.Intt"); s.ImportPath != "math/rand" {
+		t.Errorf("Closest(rand.Intt) import path = %q, want math/rand", s.ImportPath)
+	}
+	if _, ok := NewIndex().Closest("strings.Cutt"); ok {
+		t.Error("Closest on an unbuilt index found a symbol")
+	}
+}
+
+func TestSearchFallsBackToClosest(t *testing.T) {
+	x := newTestIndex(t)
+	found, err := x.Search("strings.Cutt", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].FullName() != "strings.Cut" {
+		t.Fatalf("Search(strings.Cutt) = %v, want strings.Cut", found)
+	}
+	if _, err := NewIndex().Search("strings", 5); err != ErrNotReady {
+		t.Fatalf("Search on an unbuilt index: err = %v, want ErrNotReady", err)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		bound int
+		want  int
+	}{
+		{"", "", 5, 0},
+		{"cut", "cut", 5, 0},
+		{"cutt", "cut", 5, 1},
+		{"kitten", "sitting", 5, 3},
+		{"abc", "", 5, 3},
+		// Chegaradan oshganda hisoblash to'xtaydi
+		{"strings", "bytes.buffer", 3, 3},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b, tt.bound); got != tt.want {
+			t.Errorf("distance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.bound, got, tt.want)
+		}
+	}
+}
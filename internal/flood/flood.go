@@ -0,0 +1,131 @@
+// Package flood guruhlarda bir foydalanuvchining juda tez-tez xabar yozishini cheklaydi
+// Har bir (chat, foydalanuvchi) juftligi uchun oxirgi xabarlar vaqti sirpanuvchi oynada sanaladi,
+// chat uchun slow mode belgilangan bo'lsa xabarlar orasidagi eng kam oraliq ham tekshiriladi
+package flood
+
+import (
+	"sync"
+	"time"
+)
+
+// MaxInterval slow mode oralig'ining eng katta qiymati
+const MaxInterval = time.Hour
+
+// Action cheklov qarori, qiymat qanchalik katta bo'lsa chora shunchalik qattiq
+type Action int
+
+// Cheklov qarorlari
+const (
+	Allow  Action = iota // Xabar o'tkazib yuboriladi
+	Slow                 // Slow mode oralig'i hali o'tmagan, xabar o'chiriladi
+	Delete               // Oynadagi xabarlar soni chegaradan oshgan, xabar o'chiriladi
+	Mute                 // Foydalanuvchi flood qilmoqda, u vaqtincha ovozsiz qilinadi
+)
+
+// Limits flood chegaralari
+type Limits struct {
+	Messages  int           // Window ichida ruxsat etilgan xabarlar soni (0 - cheklanmaydi)
+	Window    time.Duration // Xabarlar sanaladigan sirpanuvchi oyna
+	MuteAfter int           // Window ichida shundan ko'p xabar yozgan foydalanuvchi ovozsiz qilinadi (0 - ovozsiz qilinmaydi)
+}
+
+// Decision bitta xabar bo'yicha qaror
+type Decision struct {
+	Action Action
+	Wait   time.Duration // Slow qarorida keyingi xabargacha qolgan vaqt
+	Repeat bool          // Foydalanuvchi oxirgi ruxsat etilgan xabaridan beri bu qarorni allaqachon olgan
+}
+
+// key foydalanuvchining chatdagi hisoblagichi kaliti
+type key struct {
+	chatID, userID int64
+}
+
+// history foydalanuvchining chatdagi yaqin xabarlari
+type history struct {
+	hits    []time.Time // Window ichidagi barcha xabarlar vaqti
+	last    time.Time   // Oxirgi ruxsat etilgan xabar vaqti
+	limited Action      // Oxirgi ruxsat etilgan xabardan beri berilgan eng qattiq qaror
+}
+
+// Limiter barcha chatlar uchun xabarlar hisoblagichi
+type Limiter struct {
+	limits Limits
+
+	mu     sync.Mutex
+	users  map[key]*history
+	sweep  time.Time // Eskirgan hisoblagichlar oxirgi marta tozalangan vaqt
+	maxAge time.Duration
+}
+
+// NewLimiter berilgan chegaralar bilan hisoblagich yaratadi
+func NewLimiter(limits Limits) *Limiter {
+	if limits.Window <= 0 {
+		limits.Window = 10 * time.Second
+	}
+	return &Limiter{
+		limits: limits,
+		users:  make(map[key]*history),
+		maxAge: max(limits.Window, MaxInterval),
+	}
+}
+
+// Hit foydalanuvchining yangi xabarini hisobga oladi va u bo'yicha qaror chiqaradi
+// interval chatdagi slow mode oralig'i (0 - slow mode o'chirilgan)
+func (l *Limiter) Hit(chatID, userID int64, interval time.Duration, now time.Time) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(now)
+
+	k := key{chatID: chatID, userID: userID}
+	h := l.users[k]
+	if h == nil {
+		h = &history{}
+		l.users[k] = h
+	}
+
+	recent := h.hits[:0]
+	for _, t := range h.hits {
+		if now.Sub(t) < l.limits.Window {
+			recent = append(recent, t)
+		}
+	}
+	h.hits = append(recent, now)
+
+	var d Decision
+	switch n := len(h.hits); {
+	case l.limits.MuteAfter > 0 && n > l.limits.MuteAfter:
+		// Ovozsiz qilingandan keyin hisob qaytadan boshlanadi, shunda bitta flood uchun bir marta chora ko'riladi
+		h.hits = nil
+		d.Action = Mute
+	case l.limits.Messages > 0 && n > l.limits.Messages:
+		d.Action = Delete
+	case interval > 0 && !h.last.IsZero() && now.Sub(h.last) < interval:
+		d = Decision{Action: Slow, Wait: interval - now.Sub(h.last)}
+	default:
+		h.last = now
+		h.limited = Allow
+		return Decision{}
+	}
+
+	d.Repeat = h.limited >= d.Action
+	h.limited = max(h.limited, d.Action)
+	return d
+}
+
+// prune uzoq vaqt yozmagan foydalanuvchilar hisoblagichlarini o'chiradi
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.sweep) < l.maxAge {
+		return
+	}
+	l.sweep = now
+	for k, h := range l.users {
+		latest := h.last
+		if n := len(h.hits); n > 0 && h.hits[n-1].After(latest) {
+			latest = h.hits[n-1]
+		}
+		if now.Sub(latest) >= l.maxAge {
+			delete(l.users, k)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package flood
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterHit(t *testing.T) {
+	type hit struct {
+		at       time.Duration // Boshlang'ich vaqtdan siljish
+		user     int64
+		interval time.Duration
+		want     Action
+		repeat   bool
+	}
+	tests := []struct {
+		name   string
+		limits Limits
+		hits   []hit
+	}{
+		{
+			name:   "no limits",
+			limits: Limits{},
+			hits:   []hit{{at: 0, want: Allow}, {at: 0, want: Allow}, {at: 0, want: Allow}},
+		},
+		{
+			name:   "messages per window",
+			limits: Limits{Messages: 2, Window: 10 * time.Second},
+			hits: []hit{
+				{at: 0, want: Allow},
+				{at: time.Second, want: Allow},
+				{at: 2 * time.Second, want: Delete},
+				{at: 3 * time.Second, want: Delete, repeat: true},
+				// Oynadan chiqqan xabarlar hisobga olinmaydi
+				{at: 20 * time.Second, want: Allow},
+			},
+		},
+		{
+			name:   "users are counted separately",
+			limits: Limits{Messages: 1, Window: 10 * time.Second},
+			hits: []hit{
+				{at: 0, user: 1, want: Allow},
+				{at: 0, user: 2, want: Allow},
+				{at: time.Second, user: 1, want: Delete},
+			},
+		},
+		{
+			name:   "mute resets the window",
+			limits: Limits{Messages: 1, Window: 10 * time.Second, MuteAfter: 2},
+			hits: []hit{
+				{at: 0, want: Allow},
+				{at: time.Second, want: Delete},
+				{at: 2 * time.Second, want: Mute},
+				{at: 3 * time.Second, want: Allow},
+			},
+		},
+		{
+			name:   "slow mode",
+			limits: Limits{},
+			hits: []hit{
+				{at: 0, interval: time.Minute, want: Allow},
+				{at: 10 * time.Second, interval: time.Minute, want: Slow},
+				{at: 20 * time.Second, interval: time.Minute, want: Slow, repeat: true},
+				{at: time.Minute, interval: time.Minute, want: Allow},
+				{at: 61 * time.Second, interval: 0, want: Allow},
+			},
+		},
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.limits)
+			for i, h := range tt.hits {
+				d := l.Hit(-100, h.user, h.interval, start.Add(h.at))
+				if d.Action != h.want || d.Repeat != h.repeat {
+					t.Fatalf("hit %d: got %+v, want action %d repeat %v", i, d, h.want, h.repeat)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterSlowWait(t *testing.T) {
+	l := NewLimiter(Limits{})
+	start := time.Now()
+	l.Hit(1, 1, time.Minute, start)
+	if d := l.Hit(1, 1, time.Minute, start.Add(15*time.Second)); d.Wait != 45*time.Second {
+		t.Fatalf("Wait = %v, want 45s", d.Wait)
+	}
+}
+
+func TestLimiterPrune(t *testing.T) {
+	l := NewLimiter(Limits{Messages: 5, Window: time.Second})
+	start := time.Now()
+	l.Hit(1, 1, 0, start)
+	l.Hit(1, 2, 0, start)
+	l.Hit(1, 3, 0, start.Add(l.maxAge+time.Second))
+	if n := len(l.users); n != 1 {
+		t.Fatalf("users after prune = %d, want 1", n)
+	}
+}
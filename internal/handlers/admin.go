@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf16"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/modlog"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Telegram vaqtinchalik cheklov muddatlari chegarasi, bundan qisqa yoki uzun muddat muddatsiz hisoblanadi
+const (
+	minRestrictDuration = 30 * time.Second
+	maxRestrictDuration = 366 * 24 * time.Hour
+)
+
+// Bitta /purge buyrug'i bilan o'chirish mumkin bo'lgan xabarlar soni va deleteMessages so'rovi chegarasi
+const (
+	maxPurge      = 200
+	purgeBatchLen = 100
+)
+
+// adminRight moderatsiya buyrug'i uchun zarur admin huquqi
+type adminRight int
+
+// Admin huquqlari
+const (
+	rightRestrict adminRight = iota // A'zolarni chetlatish va cheklash
+	rightDelete                     // Xabarlarni o'chirish
+	rightPin                        // Xabarlarni qadash
+)
+
+// key huquq nomi matni kaliti
+func (r adminRight) key() string {
+	switch r {
+	case rightDelete:
+		return "mod_right_delete"
+	case rightPin:
+		return "mod_right_pin"
+	default:
+		return "mod_right_restrict"
+	}
+}
+
+// granted a'zoda huquq borligini tekshiradi, guruh yaratuvchisi barcha huquqlarga ega
+func (r adminRight) granted(member tgbotapi.ChatMember) bool {
+	if member.IsCreator() {
+		return true
+	}
+	if !member.IsAdministrator() {
+		return false
+	}
+	switch r {
+	case rightDelete:
+		return member.CanDeleteMessages
+	case rightPin:
+		return member.CanPinMessages
+	default:
+		return member.CanRestrictMembers
+	}
+}
+
+// chatMember a'zoning guruhdagi holatini getChatMember orqali oladi
+func chatMember(bot *tgbotapi.BotAPI, chatID, userID int64) (tgbotapi.ChatMember, error) {
+	return bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+}
+
+// requireRight buyruq yuboruvchi va botning o'zida kerakli admin huquqi borligini tekshiradi
+// Huquq yetishmasa foydalanuvchiga kimda va qaysi huquq yo'qligini yozadi va false qaytaradi
+func requireRight(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, right adminRight, log *logger.Logger) bool {
+	t := translatorFrom(ctx)
+	if message.From == nil {
+		return false
+	}
+	vars := content.Vars{Args: map[string]string{"right": t.text(right.key(), nil)}}
+
+	caller, err := chatMember(bot, message.Chat.ID, message.From.ID)
+	if err != nil {
+		log.Errorf("Admin huquqlarini tekshirishda xatolik: %v", err)
+		t.reply(bot, message, "admin_check_failed", content.Vars{}, log)
+		return false
+	}
+	if !caller.IsCreator() && !caller.IsAdministrator() {
+		t.reply(bot, message, "only_admins", content.Vars{}, log)
+		return false
+	}
+	if !right.granted(caller) {
+		t.reply(bot, message, "mod_caller_no_right", vars, log)
+		return false
+	}
+
+	self, err := chatMember(bot, message.Chat.ID, bot.Self.ID)
+	if err != nil {
+		log.Errorf("Bot huquqlarini tekshirishda xatolik: %v", err)
+		t.reply(bot, message, "admin_check_failed", content.Vars{}, log)
+		return false
+	}
+	if !self.IsAdministrator() {
+		t.reply(bot, message, "mod_bot_not_admin", vars, log)
+		return false
+	}
+	if !right.granted(self) {
+		t.reply(bot, message, "mod_bot_no_right", vars, log)
+		return false
+	}
+	return true
+}
+
+// cutWord matndan birinchi so'zni ajratadi
+func cutWord(s string) (word, rest string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, unicode.IsSpace)
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// parseModDuration "90s", "30m", "2h", "1h30m", "3d" yoki "1w" ko'rinishidagi muddatni o'qiydi
+func parseModDuration(word string) (time.Duration, bool) {
+	if d, err := time.ParseDuration(word); err == nil {
+		return d, d > 0
+	}
+	if len(word) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(word[:len(word)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	switch word[len(word)-1] {
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, true
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
+
+// resolveTarget buyruq qaratilgan foydalanuvchini aniqlaydi
+// Foydalanuvchi javob berilgan xabar muallifi, ismi bosilgan havola, @username yoki raqamli ID orqali ko'rsatiladi.
+// Qolgan argumentlar rest da qaytadi, foydalanuvchini aniqlab bo'lmasa problem foydalanuvchiga ko'rsatiladigan matn kaliti bo'ladi
+func (h *CommandHandler) resolveTarget(message *tgbotapi.Message) (target *tgbotapi.User, rest string, problem string) {
+	args := strings.TrimSpace(message.CommandArguments())
+	if reply := message.ReplyToMessage; reply != nil && reply.From != nil {
+		return reply.From, args, ""
+	}
+
+	// Username'i yo'q foydalanuvchilar ismi orqali belgilanadi, Telegram belgilar o'rnini UTF-16 birliklarida beradi
+	for _, e := range message.Entities {
+		if e.Type != "text_mention" || e.User == nil {
+			continue
+		}
+		text := utf16.Encode([]rune(message.Text))
+		if end := e.Offset + e.Length; end <= len(text) {
+			rest = strings.TrimSpace(string(utf16.Decode(text[end:])))
+		}
+		return e.User, rest, ""
+	}
+
+	word, rest := cutWord(args)
+	if word == "" {
+		return nil, "", "mod_no_target"
+	}
+	if strings.HasPrefix(word, "@") {
+		user, err := h.store.FindUser(word)
+		if err != nil {
+			return nil, "", "mod_user_unknown"
+		}
+		return &tgbotapi.User{ID: user.ID, FirstName: user.FirstName, LastName: user.LastName, UserName: user.UserName}, rest, ""
+	}
+	id, err := strconv.ParseInt(word, 10, 64)
+	if err != nil || id <= 0 {
+		return nil, "", "mod_no_target"
+	}
+	target = &tgbotapi.User{ID: id}
+	if user, err := h.store.GetUser(id); err == nil {
+		target.FirstName, target.LastName, target.UserName = user.FirstName, user.LastName, user.UserName
+	}
+	return target, rest, ""
+}
+
+// userLabel foydalanuvchining jurnal uchun ismi
+func userLabel(user *tgbotapi.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	switch {
+	case name != "" && user.UserName != "":
+		return name + " (@" + user.UserName + ")"
+	case user.UserName != "":
+		return "@" + user.UserName
+	case name != "":
+		return name
+	}
+	return strconv.FormatInt(user.ID, 10)
+}
+
+// moderationVars javob xabari uchun o'zgaruvchilar
+func moderationVars(t *translator, bot *tgbotapi.BotAPI, message *tgbotapi.Message, target *tgbotapi.User, duration time.Duration, reason string) content.Vars {
+	vars := contentVars(bot, message.Chat, target)
+	vars.Args = map[string]string{"reason": reason}
+	if duration > 0 {
+		vars.Args["duration"] = t.duration(duration)
+	}
+	return vars
+}
+
+// punish /ban, /mute va /kick buyruqlari uchun umumiy qism
+// timed bo'lsa foydalanuvchidan keyingi birinchi so'z muddat sifatida o'qiladi (masalan /mute 2h spam)
+func (h *CommandHandler) punish(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, action string, timed bool, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	target, rest, problem := h.resolveTarget(message)
+	if problem != "" {
+		t.reply(bot, message, problem, content.Vars{Args: map[string]string{"command": message.Command()}}, log)
+		return
+	}
+	switch {
+	case target.ID == bot.Self.ID:
+		t.reply(bot, message, "mod_target_bot", content.Vars{}, log)
+		return
+	case target.ID == message.From.ID:
+		t.reply(bot, message, "mod_target_yourself", content.Vars{}, log)
+		return
+	}
+	admin, err := isChatAdmin(bot, message.Chat.ID, target.ID)
+	if err != nil {
+		log.Errorf("Admin huquqlarini tekshirishda xatolik: %v", err)
+		t.reply(bot, message, "admin_check_failed", content.Vars{}, log)
+		return
+	}
+	if admin {
+		t.reply(bot, message, "mod_target_admin", contentVars(bot, message.Chat, target), log)
+		return
+	}
+
+	var duration time.Duration
+	if timed {
+		if word, tail := cutWord(rest); word != "" {
+			if d, ok := parseModDuration(word); ok {
+				if d < minRestrictDuration || d > maxRestrictDuration {
+					t.reply(bot, message, "mod_bad_duration", content.Vars{Args: map[string]string{
+						"min": t.duration(minRestrictDuration),
+						"max": t.duration(maxRestrictDuration),
+					}}, log)
+					return
+				}
+				duration, rest = d, tail
+			}
+		}
+	}
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+
+	switch action {
+	case modlog.Ban:
+		err = banMemberUntil(bot, message.Chat.ID, target.ID, until)
+	case modlog.Mute:
+		err = muteMember(bot, message.Chat.ID, target.ID, until)
+	case modlog.Kick:
+		err = kickMember(bot, message.Chat.ID, target.ID)
+	}
+	if err != nil {
+		log.Errorf("/%s bajarilmadi: %v", message.Command(), err)
+		t.reply(bot, message, "mod_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+		return
+	}
+
+	log.Infof("%d admin %d guruhda %d foydalanuvchiga %s chorasini qo'lladi", message.From.ID, message.Chat.ID, target.ID, action)
+	h.recordModeration(bot, message.Chat, message.From, target, modlog.Entry{Action: action, Reason: rest, Duration: duration}, log)
+	t.reply(bot, message, "mod_"+action+"_done", moderationVars(t, bot, message, target, duration, rest), log)
+}
+
+// handleBan foydalanuvchini guruhdan muddatsiz yoki vaqtincha chetlatadi
+func (h *CommandHandler) handleBan(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	h.punish(ctx, bot, message, modlog.Ban, true, log)
+}
+
+// handleMute foydalanuvchiga guruhda yozishni muddatsiz yoki vaqtincha taqiqlaydi
+func (h *CommandHandler) handleMute(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	h.punish(ctx, bot, message, modlog.Mute, true, log)
+}
+
+// handleKick foydalanuvchini guruhdan chiqaradi, u havola orqali qayta qo'shilishi mumkin
+func (h *CommandHandler) handleKick(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	h.punish(ctx, bot, message, modlog.Kick, false, log)
+}
+
+// pardon /unban va /unmute buyruqlari uchun umumiy qism
+func (h *CommandHandler) pardon(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, action string, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	target, rest, problem := h.resolveTarget(message)
+	if problem != "" {
+		t.reply(bot, message, problem, content.Vars{Args: map[string]string{"command": message.Command()}}, log)
+		return
+	}
+
+	var err error
+	switch action {
+	case modlog.Unban:
+		_, err = bot.Request(tgbotapi.UnbanChatMemberConfig{
+			ChatMemberConfig: tgbotapi.ChatMemberConfig{ChatID: message.Chat.ID, UserID: target.ID},
+			OnlyIfBanned:     true,
+		})
+	case modlog.Unmute:
+		err = unmuteMember(bot, message.Chat.ID, target.ID)
+	}
+	if err != nil {
+		log.Errorf("/%s bajarilmadi: %v", message.Command(), err)
+		t.reply(bot, message, "mod_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+		return
+	}
+
+	if action == modlog.Unban {
+		// Qaytarilgan foydalanuvchi eski ogohlantirishlar bilan darhol qayta chetlatilmasin
+		h.clearWarnings(message.Chat.ID, target.ID, log)
+	}
+
+	log.Infof("%d admin %d guruhda %d foydalanuvchiga %s chorasini qo'lladi", message.From.ID, message.Chat.ID, target.ID, action)
+	h.recordModeration(bot, message.Chat, message.From, target, modlog.Entry{Action: action, Reason: rest}, log)
+	t.reply(bot, message, "mod_"+action+"_done", moderationVars(t, bot, message, target, 0, rest), log)
+}
+
+// handleUnban chetlatilgan foydalanuvchiga guruhga qaytishga ruxsat beradi
+func (h *CommandHandler) handleUnban(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	h.pardon(ctx, bot, message, modlog.Unban, log)
+}
+
+// handleUnmute foydalanuvchidan yozish cheklovini olib tashlaydi
+func (h *CommandHandler) handleUnmute(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	h.pardon(ctx, bot, message, modlog.Unmute, log)
+}
+
+// handlePurge javob berilgan xabardan buyruqqacha bo'lgan yoki buyruqdan oldingi N ta xabarni o'chiradi
+func (h *CommandHandler) handlePurge(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	vars := content.Vars{Args: map[string]string{"max": strconv.Itoa(maxPurge)}}
+
+	var from int
+	if reply := message.ReplyToMessage; reply != nil {
+		from = reply.MessageID
+	} else if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > maxPurge {
+			t.reply(bot, message, "mod_purge_usage", vars, log)
+			return
+		}
+		from = message.MessageID - n
+	} else {
+		t.reply(bot, message, "mod_purge_usage", vars, log)
+		return
+	}
+	if message.MessageID-from > maxPurge {
+		t.reply(bot, message, "mod_purge_too_many", vars, log)
+		return
+	}
+
+	ids := make([]int, 0, message.MessageID-from+1)
+	for id := max(from, 1); id <= message.MessageID; id++ {
+		ids = append(ids, id)
+	}
+	if err := deleteMessages(bot, message.Chat.ID, ids); err != nil {
+		log.Errorf("Xabarlarni o'chirishda xatolik: %v", err)
+		t.reply(bot, message, "mod_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+		return
+	}
+
+	// Buyruqning o'zi hisobga kirmaydi
+	count := len(ids) - 1
+	log.Infof("%d admin %d guruhda %d ta xabarni o'chirdi", message.From.ID, message.Chat.ID, count)
+	h.recordModeration(bot, message.Chat, message.From, nil, modlog.Entry{Action: modlog.Purge, MessageID: from, Count: count}, log)
+	sendNotice(bot, message.Chat.ID, t.message("mod_purge_done", content.Vars{Args: map[string]string{"count": strconv.Itoa(count)}}), log)
+}
+
+// deleteMessages xabarlarni deleteMessages so'rovi orqali guruhlab o'chiradi
+// Topilmagan yoki o'chirib bo'lmaydigan eski xabarlar Telegram tomonidan tashlab yuboriladi
+func deleteMessages(bot *tgbotapi.BotAPI, chatID int64, ids []int) error {
+	for start := 0; start < len(ids); start += purgeBatchLen {
+		batch := ids[start:min(start+purgeBatchLen, len(ids))]
+		params := tgbotapi.Params{}
+		params.AddNonZero64("chat_id", chatID)
+		if err := params.AddInterface("message_ids", batch); err != nil {
+			return err
+		}
+		if _, err := bot.MakeRequest("deleteMessages", params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handlePin javob berilgan xabarni guruhda qadaydi, "silent" argumenti bilan a'zolarga bildirishnoma yuborilmaydi
+func (h *CommandHandler) handlePin(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	reply := message.ReplyToMessage
+	if reply == nil {
+		t.reply(bot, message, "mod_pin_usage", content.Vars{}, log)
+		return
+	}
+	silent := strings.EqualFold(strings.TrimSpace(message.CommandArguments()), "silent")
+
+	_, err := bot.Request(tgbotapi.PinChatMessageConfig{
+		ChatID:              message.Chat.ID,
+		MessageID:           reply.MessageID,
+		DisableNotification: silent,
+	})
+	if err != nil {
+		log.Errorf("Xabarni qadashda xatolik: %v", err)
+		t.reply(bot, message, "mod_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+		return
+	}
+	h.recordModeration(bot, message.Chat, message.From, reply.From, modlog.Entry{Action: modlog.Pin, MessageID: reply.MessageID}, log)
+	deleteMessage(bot, message.Chat.ID, message.MessageID, log)
+}
+
+// handleUnpin javob berilgan xabarni yoki oxirgi qadalgan xabarni qadalganlar ro'yxatidan olib tashlaydi
+func (h *CommandHandler) handleUnpin(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	config := tgbotapi.UnpinChatMessageConfig{ChatID: message.Chat.ID}
+	var author *tgbotapi.User
+	if reply := message.ReplyToMessage; reply != nil {
+		config.MessageID = reply.MessageID
+		author = reply.From
+	}
+
+	if _, err := bot.Request(config); err != nil {
+		log.Errorf("Qadalgan xabarni olib tashlashda xatolik: %v", err)
+		t.reply(bot, message, "mod_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+		return
+	}
+	h.recordModeration(bot, message.Chat, message.From, author, modlog.Entry{Action: modlog.Unpin, MessageID: config.MessageID}, log)
+	t.reply(bot, message, "mod_unpin_done", content.Vars{}, log)
+}
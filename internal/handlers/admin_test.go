@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseModDuration(t *testing.T) {
+	tests := []struct {
+		word string
+		want time.Duration
+		ok   bool
+	}{
+		{"30m", 30 * time.Minute, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"2d", 48 * time.Hour, true},
+		{"1w", 7 * 24 * time.Hour, true},
+		{"0m", 0, false},
+		{"-1h", 0, false},
+		{"0d", 0, false},
+		{"d", 0, false},
+		{"5y", 0, false},
+		{"spam", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseModDuration(tt.word)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("parseModDuration(%q) = %v, %v; want %v, %v", tt.word, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/releases"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Relizlar e'lonlari sozlamalari kalitlari
+const (
+	releasesSetting      = "releases"       // Obuna turi: stable, all yoki off
+	releasesSinceSetting = "releases_since" // Obuna yoqilgan vaqt, undan oldin chiqqan relizlar e'lon qilinmaydi
+)
+
+// Obuna turlari
+const (
+	subscriptionOff    = "off"    // E'lonlar yuborilmaydi
+	subscriptionStable = "stable" // Faqat barqaror relizlar (xavfsizlik relizlari bilan)
+	subscriptionAll    = "all"    // Sinov versiyalari (beta, rc) ham e'lon qilinadi
+)
+
+// announcementsBucket har bir chatga yuborilgan e'lonlar yoziladigan bucket
+// Kalit "<chat ID>:<reliz nomi>" ko'rinishida, shuning uchun qayta ishga tushganda e'lon takrorlanmaydi
+const announcementsBucket = "announcements"
+
+// historyGrace barqaror reliz reliz tarixida paydo bo'lishini kutish muddati
+// Tarixda sanasi va xavfsizlik tuzatishlari ko'rsatilgandan keyin e'lon to'liqroq bo'ladi
+const historyGrace = 12 * time.Hour
+
+// parseSubscription buyruq argumentini obuna turiga o'giradi
+func parseSubscription(arg string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(arg)) {
+	case "", "stable":
+		return subscriptionStable, true
+	case "all", "rc", "beta", "prerelease":
+		return subscriptionAll, true
+	default:
+		return "", false
+	}
+}
+
+// subscription chatning joriy obuna turini qaytaradi
+func (h *CommandHandler) subscription(chatID int64) string {
+	mode, err := h.store.GetSetting(chatID, releasesSetting)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			h.logger.Debugf("%d uchun obuna sozlamasini o'qishda xatolik: %v", chatID, err)
+		}
+		return subscriptionOff
+	}
+	return mode
+}
+
+// subscriptionStatus obuna turini foydalanuvchi tilida qaytaradi
+func subscriptionStatus(t *translator, mode string) string {
+	return t.text("subscribe_status_"+mode, nil)
+}
+
+// handleSubscribe chatni Go relizlari haqidagi e'lonlarga obuna qiladi
+// Guruhlarda obunani faqat adminlar o'zgartira oladi, shaxsiy chatda foydalanuvchi o'zi hal qiladi
+func (h *CommandHandler) handleSubscribe(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	chatID := message.Chat.ID
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 || !strings.EqualFold(args[0], "releases") {
+		t.reply(bot, message, "subscribe_usage", content.Vars{Args: map[string]string{"status": subscriptionStatus(t, h.subscription(chatID))}}, log)
+		return
+	}
+
+	mode, ok := parseSubscription(strings.Join(args[1:], " "))
+	if !ok {
+		t.reply(bot, message, "subscribe_usage", content.Vars{Args: map[string]string{"status": subscriptionStatus(t, h.subscription(chatID))}}, log)
+		return
+	}
+
+	if !message.Chat.IsPrivate() && !requireGroupAdmin(ctx, bot, message, log) {
+		return
+	}
+
+	// Obuna qayta yoqilganda avvalgi vaqt saqlanadi, aks holda oraliqda chiqqan relizlar yo'qolib qoladi
+	if h.subscription(chatID) == subscriptionOff {
+		if err := h.store.SetSetting(chatID, releasesSinceSetting, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			log.Errorf("Obuna vaqtini saqlashda xatolik: %v", err)
+			t.reply(bot, message, "subscribe_failed", content.Vars{}, log)
+			return
+		}
+	}
+	if err := h.store.SetSetting(chatID, releasesSetting, mode); err != nil {
+		log.Errorf("Obuna sozlamasini saqlashda xatolik: %v", err)
+		t.reply(bot, message, "subscribe_failed", content.Vars{}, log)
+		return
+	}
+
+	log.Infof("%d chati relizlar e'lonlariga obuna bo'ldi: %s", chatID, mode)
+	t.reply(bot, message, "subscribe_done", content.Vars{Args: map[string]string{"status": subscriptionStatus(t, mode)}}, log)
+}
+
+// handleUnsubscribe chatni relizlar e'lonlaridan chiqaradi
+func (h *CommandHandler) handleUnsubscribe(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	chatID := message.Chat.ID
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 || !strings.EqualFold(args[0], "releases") {
+		t.reply(bot, message, "subscribe_usage", content.Vars{Args: map[string]string{"status": subscriptionStatus(t, h.subscription(chatID))}}, log)
+		return
+	}
+
+	if !message.Chat.IsPrivate() && !requireGroupAdmin(ctx, bot, message, log) {
+		return
+	}
+
+	if err := h.store.SetSetting(chatID, releasesSetting, subscriptionOff); err != nil {
+		log.Errorf("Obuna sozlamasini saqlashda xatolik: %v", err)
+		t.reply(bot, message, "subscribe_failed", content.Vars{}, log)
+		return
+	}
+
+	log.Infof("%d chati relizlar e'lonlaridan chiqdi", chatID)
+	t.reply(bot, message, "unsubscribe_done", content.Vars{}, log)
+}
+
+// subscriber e'lon yuborilishi kerak bo'lgan chat
+type subscriber struct {
+	chatID int64
+	mode   string
+	since  time.Time
+}
+
+// subscribers relizlar e'lonlariga obuna bo'lgan chatlarni qaytaradi
+func (h *CommandHandler) subscribers() ([]subscriber, error) {
+	chats, err := h.store.ListChats()
+	if err != nil {
+		return nil, err
+	}
+
+	var list []subscriber
+	for _, chat := range chats {
+		mode := h.subscription(chat.ID)
+		if mode == subscriptionOff {
+			continue
+		}
+		sub := subscriber{chatID: chat.ID, mode: mode}
+		if value, err := h.store.GetSetting(chat.ID, releasesSinceSetting); err == nil {
+			sub.since, _ = time.Parse(time.RFC3339, value)
+		}
+		list = append(list, sub)
+	}
+	return list, nil
+}
+
+// announcementKey chatga yuborilgan e'lon yozuvi kaliti
+func announcementKey(chatID int64, release releases.Release) string {
+	return fmt.Sprintf("%d:%s", chatID, release.Name)
+}
+
+// AnnounceReleases obuna bo'lgan chatlarga hali e'lon qilinmagan yangi relizlar haqida xabar yuboradi
+// Har bir yuborilgan e'lon omborda chat bo'yicha qayd etiladi, shuning uchun bir reliz bir chatga faqat bir marta e'lon qilinadi
+// Relizlar ma'lumotlari yangilangandan keyin chaqiriladi
+func (h *CommandHandler) AnnounceReleases(ctx context.Context, bot *tgbotapi.BotAPI) {
+	if h.releases == nil {
+		return
+	}
+
+	pending := h.pendingReleases()
+	if len(pending) == 0 {
+		return
+	}
+
+	subs, err := h.subscribers()
+	if err != nil {
+		h.logger.Errorf("Obunachilar ro'yxatini olishda xatolik: %v", err)
+		return
+	}
+
+	for _, sub := range subs {
+		for _, release := range pending {
+			if ctx.Err() != nil {
+				return
+			}
+			if !release.Stable && sub.mode != subscriptionAll {
+				continue
+			}
+			// Obuna yoqilishidan oldin chiqqan relizlar e'lon qilinmaydi
+			if release.SeenAt.Before(sub.since) {
+				continue
+			}
+			if !h.announce(bot, sub.chatID, release) {
+				// Chatga yuborib bo'lmasa qolgan relizlar keyingi safar yuboriladi
+				break
+			}
+		}
+	}
+}
+
+// pendingReleases e'lon qilinishi mumkin bo'lgan yangi relizlarni eskisidan boshlab qaytaradi
+// Barqaror relizlar reliz tarixida paydo bo'lguncha yoki historyGrace o'tguncha kutiladi
+func (h *CommandHandler) pendingReleases() []releases.Release {
+	all := h.releases.Releases()
+
+	var pending []releases.Release
+	for i := len(all) - 1; i >= 0; i-- {
+		r := all[i]
+		if r.SeenAt.IsZero() {
+			continue
+		}
+		if r.Stable && r.Date.IsZero() && h.releases.HasHistory() && time.Since(r.SeenAt) < historyGrace {
+			continue
+		}
+		pending = append(pending, r)
+	}
+	return pending
+}
+
+// announce bitta relizni chatga e'lon qiladi va natijani qayd etadi
+// Yozuv xabar yuborilishidan oldin saqlanadi: bot yuborish paytida to'xtasa e'lon takrorlanmaydi
+// Vaqtinchalik xatolikda yozuv o'chiriladi va e'lon keyingi safar qayta yuboriladi,
+// bot chatdan chiqarilgan bo'lsa obuna o'chiriladi
+func (h *CommandHandler) announce(bot *tgbotapi.BotAPI, chatID int64, release releases.Release) bool {
+	key := announcementKey(chatID, release)
+	if _, err := h.store.Get(announcementsBucket, key); err == nil {
+		return true
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		h.logger.Errorf("E'lonlar yozuvini o'qishda xatolik: %v", err)
+		return false
+	}
+
+	if err := h.store.Put(announcementsBucket, key, []byte(time.Now().UTC().Format(time.RFC3339))); err != nil {
+		h.logger.Errorf("E'lon yozuvini saqlashda xatolik: %v", err)
+		return false
+	}
+
+	t := h.translatorFor(h.locale(&tgbotapi.Chat{ID: chatID}, nil))
+	text := h.announcementMessage(t, release)
+	msg := tgbotapi.NewMessage(chatID, text.Text)
+	msg.ParseMode = text.ParseMode
+	msg.DisableWebPagePreview = true
+	if _, err := bot.Send(msg); err != nil {
+		var apiErr *tgbotapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == 403 {
+			h.logger.Warnf("%d chatiga e'lon yuborib bo'lmadi, obuna o'chirildi: %v", chatID, err)
+			if err := h.store.SetSetting(chatID, releasesSetting, subscriptionOff); err != nil {
+				h.logger.Errorf("Obuna sozlamasini saqlashda xatolik: %v", err)
+			}
+			return false
+		}
+
+		h.logger.Warnf("%d chatiga %s e'lonini yuborishda xatolik: %v", chatID, release.Name, err)
+		if err := h.store.Delete(announcementsBucket, key); err != nil {
+			h.logger.Errorf("E'lon yozuvini o'chirishda xatolik: %v", err)
+		}
+		return false
+	}
+
+	h.logger.Infof("%d chatiga %s relizi e'lon qilindi", chatID, release.Name)
+	return true
+}
+
+// announcementMessage reliz e'loni matnini tayyorlaydi
+// Xavfsizlik relizlari va sinov versiyalari uchun alohida shablonlar ishlatiladi
+func (h *CommandHandler) announcementMessage(t *translator, release releases.Release) content.Message {
+	key := "announce_release"
+	switch {
+	case release.Version.Prerelease():
+		key = "announce_prerelease"
+	case release.Security:
+		key = "announce_security"
+	}
+
+	date := ""
+	if !release.Date.IsZero() {
+		date = release.Date.Format(time.DateOnly)
+	}
+
+	return t.message(key, content.Vars{Args: map[string]string{
+		"version":  release.Version.String(),
+		"date":     date,
+		"fixes":    release.SecurityFixes,
+		"notes":    release.NotesURL(),
+		"download": "https://go.dev/dl/#" + release.Name,
+	}})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"unicode/utf8"
+
+	"tg-bot/internal/antispam"
+	"tg-bot/internal/modlog"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// auditExcerptLength audit xabarida ko'rsatiladigan spam matnining eng ko'p uzunligi (belgilarda)
+const auditExcerptLength = 300
+
+// MemberJoined yangi a'zoni spam filtrlariga bildiradi, shunda uning dastlabki xabarlari qattiqroq tekshiriladi
+func (h *CommandHandler) MemberJoined(chatID int64, user tgbotapi.User) {
+	if h.antispam == nil || user.IsBot {
+		return
+	}
+	if err := h.antispam.Joined(chatID, user.ID); err != nil {
+		h.logger.Warnf("Yangi a'zoni spam filtrlariga yozishda xatolik: %v", err)
+	}
+}
+
+// FilterMessage guruhdagi xabarni spam filtrlaridan o'tkazadi va qaror bo'yicha chora ko'radi
+// Xabar o'chirilgan bo'lsa true qaytariladi va uni boshqa qayta ishlash kerak emas
+func (h *CommandHandler) FilterMessage(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) bool {
+	if h.antispam == nil || message.From == nil || message.From.IsBot {
+		return false
+	}
+	// Shaxsiy chatlar, kanal nomidan yozgan adminlar va bog'langan kanal postlari tekshirilmaydi
+	if message.Chat.IsPrivate() || message.Chat.IsChannel() || message.SenderChat != nil || message.IsAutomaticForward {
+		return false
+	}
+
+	verdict := h.antispam.Check(antispam.FromTelegram(message))
+	if verdict.Action == antispam.Allow {
+		return false
+	}
+	// Adminlar xabarlari filtrlanmaydi, ularni tekshirish faqat shubhali xabarlar uchun so'raladi
+	// Tekshirib bo'lmasa chora ko'rilmaydi: adminni xato bilan chetlatgandan spamni o'tkazib yuborgan yaxshi
+	admin, err := isChatAdmin(bot, message.Chat.ID, message.From.ID)
+	if err != nil {
+		log.Warnf("Spam filtri: admin huquqlarini tekshirishda xatolik, xabar o'tkazib yuborildi: %v", err)
+		return false
+	}
+	if admin {
+		return false
+	}
+
+	log.Infof("Spam filtri: %d guruhdagi %d foydalanuvchi xabari, qaror %s (%s: %s)",
+		message.Chat.ID, message.From.ID, verdict.Action, verdict.Filter, verdict.Reason)
+	deleteMessage(bot, message.Chat.ID, message.MessageID, log)
+
+	t := h.translator(message.Chat, message.From)
+	switch verdict.Action {
+	case antispam.Warn:
+		parts, err := h.warnMember(bot, t, message.Chat, message.From, nil, modlog.SourceAntispam, h.verdictReason(t, verdict), log)
+		if err != nil {
+			log.Errorf("Spam filtri: ogohlantirishni saqlashda xatolik: %v", err)
+			break
+		}
+		sendMessage(bot, message.Chat.ID, 0, joinMessages(parts...), log)
+	case antispam.Ban:
+		if err := banMember(bot, message.Chat.ID, message.From.ID); err != nil {
+			log.Errorf("Spam filtri: foydalanuvchini chetlatishda xatolik: %v", err)
+			break
+		}
+		h.recordModeration(bot, message.Chat, nil, message.From, modlog.Entry{Action: modlog.Ban, Source: modlog.SourceAntispam, Reason: h.verdictReason(t, verdict)}, log)
+	default:
+		h.recordModeration(bot, message.Chat, nil, message.From, modlog.Entry{Action: modlog.Delete, Source: modlog.SourceAntispam, Reason: h.verdictReason(t, verdict), MessageID: message.MessageID}, log)
+	}
+
+	h.auditSpam(bot, message, verdict, log)
+	return true
+}
+
+// verdictReason qaror sababini foydalanuvchi va adminlarga tushunarli matnga aylantiradi
+func (h *CommandHandler) verdictReason(t *translator, verdict antispam.Verdict) string {
+	args := map[string]string{"detail": verdict.Reason}
+	switch verdict.Filter {
+	case antispam.FilterLinks, antispam.FilterNewcomer, antispam.FilterForward, antispam.FilterDuplicate, antispam.FilterRule:
+		return t.text("antispam_filter_"+verdict.Filter, args)
+	}
+	// Tashqaridan qo'shilgan filtrlar uchun matn yo'q
+	if verdict.Reason == "" {
+		return verdict.Filter
+	}
+	return verdict.Filter + ": " + verdict.Reason
+}
+
+// auditSpam ko'rilgan chorani adminlar kanaliga yozadi
+func (h *CommandHandler) auditSpam(bot *tgbotapi.BotAPI, message *tgbotapi.Message, verdict antispam.Verdict, log *logger.Logger) {
+	auditChat := h.config.AntispamSettings().AuditChat
+	if auditChat == 0 {
+		return
+	}
+
+	t := h.translator(&tgbotapi.Chat{ID: auditChat}, nil)
+	text := message.Text
+	if text == "" {
+		text = message.Caption
+	}
+	if utf8.RuneCountInString(text) > auditExcerptLength {
+		text = string([]rune(text)[:auditExcerptLength]) + "…"
+	}
+
+	vars := contentVars(bot, message.Chat, message.From)
+	vars.Args = map[string]string{
+		"action":  t.text("antispam_action_"+verdict.Action.String(), nil),
+		"reason":  h.verdictReason(t, verdict),
+		"user_id": strconv.FormatInt(message.From.ID, 10),
+		"chat_id": strconv.FormatInt(message.Chat.ID, 10),
+		"text":    text,
+	}
+	sendMessage(bot, auditChat, 0, t.message("antispam_audit", vars), log)
+}
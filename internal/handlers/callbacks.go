@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"runtime/debug"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/quiz"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Tugma ma'lumotlari "<nom>:<arg1>:<arg2>..." ko'rinishida yoziladi
+// Telegram callback_data ni 64 bayt bilan cheklaydi, uzunroq ma'lumotlar omborda saqlanadi
+// va tugmaga faqat "~<kalit>" ko'rinishidagi havola yoziladi
+const (
+	callbackSeparator    = ":"
+	storedCallbackPrefix = "~"
+	maxCallbackData      = 64
+	maxAlertLength       = 200 // Callback javobi matni uzunligi
+)
+
+// callbacksBucket uzun tugma ma'lumotlari saqlanadigan bucket
+const callbacksBucket = "callbacks"
+
+// storedCallbackTTL muddati ko'rsatilmagan tugmalarning omborda saqlanish muddati
+const storedCallbackTTL = 30 * 24 * time.Hour
+
+// CallbackFunction tugma bosilganda chaqiriladi
+// args tugma nomidan keyingi qismlar, javob CallbackResult orqali qaytariladi
+type CallbackFunction func(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, args []string, log *logger.Logger) CallbackResult
+
+// CallbackSpec tugmalar guruhining tavsifi
+type CallbackSpec struct {
+	Name    string        // Callback ma'lumotidagi nom (prefiks), masalan quiz yoki roadmap
+	TTL     time.Duration // Xabar yuborilganidan keyin tugmalar qancha vaqt ishlaydi (0 - muddatsiz)
+	Expired string        // Muddati o'tgan tugma bosilganda ko'rsatiladigan matn kaliti (bo'sh - callback_expired)
+}
+
+// CallbackResult tugma bosilgandan keyin foydalanuvchiga javob va xabarga kiritiladigan o'zgarishlar
+type CallbackResult struct {
+	Text           string                         // Tugma ustida qisqa ko'rinadigan javob (bo'sh bo'lishi mumkin)
+	Alert          bool                           // true bo'lsa javob yopilishi kerak bo'lgan oynada ko'rsatiladi
+	Edit           *content.Message               // Xabar matnini almashtirish
+	Markup         *tgbotapi.InlineKeyboardMarkup // Yangi tugmalar (Edit bilan yoki alohida)
+	RemoveKeyboard bool                           // Xabardagi tugmalarni olib tashlash
+}
+
+// callbackToast tugma ustida qisqa javob
+func callbackToast(text string) CallbackResult {
+	return CallbackResult{Text: text}
+}
+
+// callbackAlert yopilishi kerak bo'lgan oynadagi javob
+func callbackAlert(text string) CallbackResult {
+	return CallbackResult{Text: text, Alert: true}
+}
+
+// callbackEdit xabar matni va tugmalarini almashtiradi
+func callbackEdit(text content.Message, markup tgbotapi.InlineKeyboardMarkup) CallbackResult {
+	return CallbackResult{Edit: &text, Markup: &markup}
+}
+
+// callbackRoute ro'yxatdan o'tgan tugmalar guruhi
+type callbackRoute struct {
+	spec CallbackSpec
+	fn   CallbackFunction
+}
+
+// storedCallback omborda saqlangan uzun tugma ma'lumoti
+type storedCallback struct {
+	Name    string    `json:"name"`
+	Args    []string  `json:"args"`
+	Expires time.Time `json:"expires"`
+}
+
+// RegisterCallback tugmalar guruhini ro'yxatdan o'tkazadi
+// Nom ":" belgisisiz va boshqa guruhlar nomidan farqli bo'lishi kerak
+func (h *CommandHandler) RegisterCallback(spec CallbackSpec, fn CallbackFunction) {
+	if h.callbacks == nil {
+		h.callbacks = make(map[string]callbackRoute)
+	}
+	h.callbacks[spec.Name] = callbackRoute{spec: spec, fn: fn}
+}
+
+// registerCallbacks bot yuboradigan inline tugmalar guruhlarini ro'yxatdan o'tkazadi
+func (h *CommandHandler) registerCallbacks() {
+	// CAPTCHA - yangi a'zo tekshiruvi, muddatni tekshiruvning o'zi kuzatadi
+	h.RegisterCallback(CallbackSpec{Name: captchaCallback}, h.handleCaptchaCallback)
+
+	// LANG - foydalanuvchi yoki guruh tilini tanlash
+	h.RegisterCallback(CallbackSpec{Name: langCallback, TTL: 24 * time.Hour}, h.handleLangCallback)
+
+	// QUIZ - viktorina javoblari, eski javob yozuvlari o'chiriladi, shuning uchun muddati o'tgan savolga javob berib bo'lmaydi
+	h.RegisterCallback(CallbackSpec{Name: quizCallback, TTL: quiz.AnswerWindow, Expired: "quiz_expired"}, h.handleQuizCallback)
+
+	// ROADMAP - yo'l xaritasi menyusi, xabar tahrirlanib turadi, shuning uchun muddatsiz
+	h.RegisterCallback(CallbackSpec{Name: roadmapCallback}, h.handleRoadmapCallback)
+
+	// REPORT - adminlarga yuborilgan shikoyat bo'yicha choralar, ko'rib chiqilmagan shikoyat muddatsiz turadi
+	h.RegisterCallback(CallbackSpec{Name: reportCallback}, h.handleReportCallback)
+
+	// ABOUT - bot haqida ma'lumot
+	h.RegisterCallback(CallbackSpec{Name: "about"}, func(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, _ []string, log *logger.Logger) CallbackResult {
+		if callback.Message != nil {
+			vars := contentVars(bot, callback.Message.Chat, callback.From)
+			sendMessage(bot, callback.Message.Chat.ID, 0, translatorFrom(ctx).message("about", vars), log)
+		}
+		return CallbackResult{}
+	})
+}
+
+// CallbackData tugma uchun callback ma'lumotini yasaydi
+// Natija 64 baytdan oshsa ma'lumot omborga yoziladi va tugmaga uning qisqa kaliti beriladi
+func (h *CommandHandler) CallbackData(name string, args ...string) string {
+	data := strings.Join(append([]string{name}, args...), callbackSeparator)
+	if len(data) <= maxCallbackData {
+		return data
+	}
+
+	ttl := h.callbacks[name].spec.TTL
+	if ttl <= 0 {
+		ttl = storedCallbackTTL
+	}
+	key := newCallbackKey()
+	stored := storedCallback{Name: name, Args: args, Expires: time.Now().Add(ttl).UTC()}
+	if err := storage.PutJSON(h.store, callbacksBucket, key, stored); err != nil {
+		// Tugma baribir yuboriladi, bosilganda esa eskirgan tugma sifatida javob beriladi
+		h.logger.Warnf("Tugma ma'lumotini saqlashda xatolik (%s): %v", name, err)
+	}
+	return storedCallbackPrefix + key
+}
+
+// newCallbackKey omborda saqlanadigan tugma uchun tasodifiy kalit
+func newCallbackKey() string {
+	b := make([]byte, 9)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// resolveCallback callback ma'lumotini tugmalar guruhi nomi va argumentlarga ajratadi
+// Omborda saqlangan ma'lumot topilmasa yoki muddati o'tgan bo'lsa expired true bo'ladi
+func (h *CommandHandler) resolveCallback(data string) (name string, args []string, expired bool) {
+	if key, ok := strings.CutPrefix(data, storedCallbackPrefix); ok {
+		var stored storedCallback
+		if err := storage.GetJSON(h.store, callbacksBucket, key, &stored); err != nil {
+			if !errors.Is(err, storage.ErrNotFound) {
+				h.logger.Warnf("Tugma ma'lumotini o'qishda xatolik: %v", err)
+			}
+			return "", nil, true
+		}
+		if time.Now().After(stored.Expires) {
+			_ = h.store.Delete(callbacksBucket, key)
+			return stored.Name, nil, true
+		}
+		return stored.Name, stored.Args, false
+	}
+
+	name, rest, found := strings.Cut(data, callbackSeparator)
+	if found {
+		args = strings.Split(rest, callbackSeparator)
+	}
+	return name, args, false
+}
+
+// HandleCallback inline klaviatura tugmalaridan kelgan so'rovni tegishli qayta ishlovchiga uzatadi
+// Telegram tugmani bosish animatsiyasini to'xtatishi uchun har bir so'rovga aynan bitta javob yuboriladi
+func (h *CommandHandler) HandleCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, log *logger.Logger) {
+	var chat *tgbotapi.Chat
+	if callback.Message != nil {
+		chat = callback.Message.Chat
+	}
+	t := h.translator(chat, callback.From)
+	ctx = withTranslator(ctx, t)
+
+	name, args, expired := h.resolveCallback(callback.Data)
+	route, ok := h.callbacks[name]
+
+	var result CallbackResult
+	switch {
+	case !ok && !expired:
+		log.Debugf("Noma'lum callback: %q", callback.Data)
+		result = callbackToast(t.text("callback_unknown", nil))
+	case !expired && route.spec.TTL > 0 && callback.Message != nil && time.Since(callback.Message.Time()) > route.spec.TTL:
+		expired = true
+	}
+	if expired {
+		key := route.spec.Expired
+		if key == "" {
+			key = "callback_expired"
+		}
+		result = CallbackResult{Text: t.text(key, nil), RemoveKeyboard: true}
+	} else if ok {
+		result = h.runCallback(ctx, bot, callback, route, args, log)
+	}
+
+	h.answerCallback(bot, callback, result, log)
+}
+
+// runCallback qayta ishlovchini chaqiradi, panic bo'lsa u log qilinadi va bo'sh javob qaytariladi
+func (h *CommandHandler) runCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, route callbackRoute, args []string, log *logger.Logger) (result CallbackResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("%s tugmasini qayta ishlashda panic: %v\n%s", route.spec.Name, r, debug.Stack())
+			result = CallbackResult{}
+		}
+	}()
+	return route.fn(ctx, bot, callback, args, log)
+}
+
+// answerCallback so'rovga javob beradi va qayta ishlovchi so'ragan o'zgarishlarni xabarga kiritadi
+func (h *CommandHandler) answerCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, result CallbackResult, log *logger.Logger) {
+	answer := tgbotapi.NewCallback(callback.ID, shortenAlert(result.Text))
+	answer.ShowAlert = result.Alert
+	if _, err := bot.Request(answer); err != nil {
+		log.Debugf("Callbackga javob berishda xatolik: %v", err)
+	}
+
+	// Inline rejimda yuborilgan xabarlar botga berilmaydi, ularni tahrirlab bo'lmaydi
+	if callback.Message == nil {
+		return
+	}
+	chatID, messageID := callback.Message.Chat.ID, callback.Message.MessageID
+
+	var edit tgbotapi.Chattable
+	switch {
+	case result.Edit != nil:
+		// Matn tugmalarsiz tahrirlansa Telegram xabardagi tugmalarni olib tashlaydi
+		cfg := tgbotapi.NewEditMessageText(chatID, messageID, result.Edit.Text)
+		cfg.ParseMode = result.Edit.ParseMode
+		cfg.DisableWebPagePreview = true
+		if result.Markup != nil && !result.RemoveKeyboard {
+			cfg.ReplyMarkup = result.Markup
+		}
+		edit = cfg
+	case result.RemoveKeyboard:
+		edit = tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+	case result.Markup != nil:
+		edit = tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, *result.Markup)
+	default:
+		return
+	}
+	if _, err := bot.Send(edit); err != nil {
+		log.Debugf("Tugma bosilgan xabarni yangilab bo'lmadi: %v", err)
+	}
+}
+
+// shortenAlert matnni callback oynasiga sig'adigan qilib qisqartiradi
+func shortenAlert(text string) string {
+	if utf8.RuneCountInString(text) <= maxAlertLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxAlertLength-1]) + "…"
+}
+
+// PruneCallbacks omborda saqlangan va muddati o'tgan tugma ma'lumotlarini o'chiradi
+func (h *CommandHandler) PruneCallbacks(now time.Time) (int, error) {
+	var stale []string
+	err := h.store.Scan(callbacksBucket, "", func(key string, value []byte) error {
+		var stored storedCallback
+		if err := json.Unmarshal(value, &stored); err != nil || now.After(stored.Expires) {
+			stale = append(stale, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range stale {
+		if err := h.store.Delete(callbacksBucket, key); err != nil {
+			return 0, err
+		}
+	}
+	return len(stale), nil
+}
+
+// RunCallbackPrune eskirgan tugma ma'lumotlarini har soatda o'chirib turadi
+func (h *CommandHandler) RunCallbackPrune(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if n, err := h.PruneCallbacks(now); err != nil {
+				h.logger.Warnf("Eskirgan tugma ma'lumotlarini o'chirishda xatolik: %v", err)
+			} else if n > 0 {
+				h.logger.Debugf("%d ta eskirgan tugma ma'lumoti o'chirildi", n)
+			}
+		}
+	}
+}
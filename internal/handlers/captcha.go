@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-bot/internal/modlog"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// captchaBucket kutilayotgan tekshiruvlar saqlanadigan bucket
+// Tekshiruvlar omborda saqlanadi, shuning uchun bot qayta ishga tushganda ham muddati o'tganlar chiqarib yuboriladi
+const captchaBucket = "captcha"
+
+// captchaCallback captcha tugmalari nomi, ma'lumot ko'rinishi "captcha:<foydalanuvchi ID si>:<variant>"
+const captchaCallback = "captcha"
+
+// captchaQuestion yangi a'zoga beriladigan Go bo'yicha oddiy savol
+type captchaQuestion struct {
+	Key     string   // Savol matni kaliti, matn tanlangan tilda content fayllaridan olinadi
+	Options []string // Javob variantlari, birinchisi doim to'g'ri javob
+}
+
+// captchaQuestions Go mavzusidagi savollar to'plami
+// Variantlar Go atamalari va nomlar bo'lgani uchun barcha tillarda bir xil, yuborishdan oldin aralashtiriladi
+var captchaQuestions = []captchaQuestion{
+	{"captcha_question_go", []string{"go", "async", "thread", "spawn"}},
+	{"captcha_question_defer", []string{"defer", "finally", "after", "later"}},
+	{"captcha_question_mascot", []string{"Gopher", "Tux", "Duke", "Octocat"}},
+	{"captcha_question_make", []string{"make", "alloc", "create", "malloc"}},
+	{"captcha_question_len", []string{"2", "1", "3", "0"}},
+	{"captcha_question_main", []string{"main", "init", "app", "start"}},
+}
+
+// captchaEmojis tugma rejimida ko'rsatiladigan belgilar, birinchisi doim to'g'ri javob
+var captchaEmojis = []string{"🐹", "🐍", "🦀", "☕"}
+
+// captchaChallenge yangi a'zo uchun yaratilgan tekshiruv holati
+type captchaChallenge struct {
+	ChatID           int64         `json:"chat_id"`            // Guruh identifikatori
+	UserID           int64         `json:"user_id"`            // Tekshirilayotgan foydalanuvchi
+	User             tgbotapi.User `json:"user"`               // Tasdiqlangandan keyin salomlashish uchun foydalanuvchi ma'lumotlari
+	MessageID        int           `json:"message_id"`         // Savol yozilgan xabar
+	ServiceMessageID int           `json:"service_message_id"` // "... guruhga qo'shildi" xizmat xabari
+	Answer           int           `json:"answer"`             // To'g'ri javob varianti tartib raqami
+	ExpiresAt        time.Time     `json:"expires_at"`         // Javob berish muddati
+}
+
+// captchaKey tekshiruv uchun ombordagi kalitni qaytaradi
+func captchaKey(chatID, userID int64) string {
+	return fmt.Sprintf("%d:%d", chatID, userID)
+}
+
+// CaptchaEnabled yangi a'zolarni tekshirish yoqilganligini bildiradi
+func (h *CommandHandler) CaptchaEnabled() bool {
+	return h.config.CaptchaSettings().Enabled
+}
+
+// OnMemberVerified yangi a'zo tekshiruvdan muvaffaqiyatli o'tganda chaqiriladigan funksiyani o'rnatadi
+func (h *CommandHandler) OnMemberVerified(fn func(bot *tgbotapi.BotAPI, chatID int64, user tgbotapi.User)) {
+	h.memberVerified = fn
+}
+
+// StartCaptcha yangi a'zoni cheklaydi va unga inline klaviaturali savol yuboradi
+// Foydalanuvchi belgilangan vaqt ichida to'g'ri javob bermasa, guruhdan chiqarib yuboriladi
+func (h *CommandHandler) StartCaptcha(bot *tgbotapi.BotAPI, message *tgbotapi.Message, user tgbotapi.User) {
+	log := h.logger
+	settings := h.config.CaptchaSettings()
+	t := h.translator(message.Chat, &user)
+
+	// Foydalanuvchini javob bergunicha cheklash
+	if err := muteMember(bot, message.Chat.ID, user.ID, time.Time{}); err != nil {
+		log.Errorf("Yangi a'zoni cheklashda xatolik (botda admin huquqlari yo'qmi?): %v", err)
+		h.verified(bot, message.Chat.ID, user)
+		return
+	}
+
+	var (
+		text    string
+		options []string
+	)
+	if strings.ToLower(settings.Mode) == "button" {
+		text = t.text("captcha_button_task", nil)
+		options = captchaEmojis
+	} else {
+		question := captchaQuestions[rand.Intn(len(captchaQuestions))]
+		text = t.text(question.Key, nil)
+		options = question.Options
+	}
+
+	// Variantlarni aralashtirib, to'g'ri javob o'rnini eslab qolamiz
+	order := rand.Perm(len(options))
+	answer := 0
+	var buttons []tgbotapi.InlineKeyboardButton
+	for i, idx := range order {
+		if idx == 0 {
+			answer = i
+		}
+		data := h.CallbackData(captchaCallback, strconv.FormatInt(user.ID, 10), strconv.Itoa(i))
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(options[idx], data))
+	}
+
+	vars := contentVars(bot, message.Chat, &user)
+	vars.Args = map[string]string{"timeout": t.duration(settings.Timeout), "question": text}
+	greeting := t.message("captcha_greeting", vars)
+	msg := tgbotapi.NewMessage(message.Chat.ID, greeting.Text)
+	msg.ParseMode = greeting.ParseMode
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons[:2], buttons[2:])
+	sent, err := bot.Send(msg)
+	if err != nil {
+		log.Errorf("Captcha xabarini yuborishda xatolik: %v", err)
+		if err := unmuteMember(bot, message.Chat.ID, user.ID); err != nil {
+			log.Errorf("Foydalanuvchi cheklovini olib tashlashda xatolik: %v", err)
+		}
+		return
+	}
+
+	challenge := captchaChallenge{
+		ChatID:           message.Chat.ID,
+		UserID:           user.ID,
+		User:             user,
+		MessageID:        sent.MessageID,
+		ServiceMessageID: message.MessageID,
+		Answer:           answer,
+		ExpiresAt:        time.Now().Add(settings.Timeout),
+	}
+	if err := storage.PutJSON(h.store, captchaBucket, captchaKey(challenge.ChatID, challenge.UserID), challenge); err != nil {
+		log.Errorf("Captcha holatini saqlashda xatolik: %v", err)
+	}
+
+	h.scheduleCaptcha(bot, challenge)
+	log.Infof("%d foydalanuvchi uchun %d guruhda captcha yuborildi", user.ID, message.Chat.ID)
+}
+
+// ResumeCaptchas bot qayta ishga tushganda ombordagi tugallanmagan tekshiruvlar uchun taymerlarni tiklaydi
+func (h *CommandHandler) ResumeCaptchas(bot *tgbotapi.BotAPI) {
+	err := h.store.Scan(captchaBucket, "", func(key string, value []byte) error {
+		var challenge captchaChallenge
+		if err := json.Unmarshal(value, &challenge); err != nil {
+			h.logger.Warnf("Captcha holatini o'qishda xatolik: %v", err)
+			return nil
+		}
+		h.scheduleCaptcha(bot, challenge)
+		return nil
+	})
+	if err != nil {
+		h.logger.Warnf("Tugallanmagan captchalarni tiklashda xatolik: %v", err)
+	}
+}
+
+// scheduleCaptcha tekshiruv muddati tugaganda foydalanuvchini chiqarib yuboruvchi taymerni o'rnatadi
+func (h *CommandHandler) scheduleCaptcha(bot *tgbotapi.BotAPI, challenge captchaChallenge) {
+	key := captchaKey(challenge.ChatID, challenge.UserID)
+	timer := time.AfterFunc(time.Until(challenge.ExpiresAt), func() {
+		h.expireCaptcha(bot, challenge.ChatID, challenge.UserID)
+	})
+
+	h.captchaMu.Lock()
+	if old, ok := h.captchaTimers[key]; ok {
+		old.Stop()
+	}
+	h.captchaTimers[key] = timer
+	h.captchaMu.Unlock()
+}
+
+// finishCaptcha tekshiruvni yakunlaydi: taymerni to'xtatadi, yozuvni o'chiradi va xabarlarni tozalaydi
+// Tekshiruv allaqachon yakunlangan bo'lsa false qaytaradi
+func (h *CommandHandler) finishCaptcha(bot *tgbotapi.BotAPI, chatID, userID int64) (captchaChallenge, bool) {
+	key := captchaKey(chatID, userID)
+
+	// Taymer va tugma bir vaqtda ishlasa ham tekshiruv faqat bir marta yakunlanishi uchun qulf ostida o'qiymiz
+	h.captchaMu.Lock()
+	if timer, ok := h.captchaTimers[key]; ok {
+		timer.Stop()
+		delete(h.captchaTimers, key)
+	}
+
+	var challenge captchaChallenge
+	err := storage.GetJSON(h.store, captchaBucket, key, &challenge)
+	if err == nil {
+		if err := h.store.Delete(captchaBucket, key); err != nil {
+			h.logger.Warnf("Captcha holatini o'chirishda xatolik: %v", err)
+		}
+	}
+	h.captchaMu.Unlock()
+
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			h.logger.Warnf("Captcha holatini o'qishda xatolik: %v", err)
+		}
+		return challenge, false
+	}
+
+	deleteMessage(bot, chatID, challenge.MessageID, h.logger)
+	deleteMessage(bot, chatID, challenge.ServiceMessageID, h.logger)
+	return challenge, true
+}
+
+// expireCaptcha muddatida javob bermagan foydalanuvchini guruhdan chiqarib yuboradi
+func (h *CommandHandler) expireCaptcha(bot *tgbotapi.BotAPI, chatID, userID int64) {
+	challenge, ok := h.finishCaptcha(bot, chatID, userID)
+	if !ok {
+		return
+	}
+
+	if err := kickMember(bot, chatID, userID); err != nil {
+		h.logger.Errorf("Captchadan o'tmagan foydalanuvchini chiqarishda xatolik: %v", err)
+		return
+	}
+	h.logger.Infof("%d foydalanuvchi %d guruhda captchaga vaqtida javob bermagani uchun chiqarildi", userID, chatID)
+	h.recordModeration(bot, &tgbotapi.Chat{ID: chatID}, nil, &challenge.User, modlog.Entry{Action: modlog.Kick, Source: modlog.SourceCaptcha}, h.logger)
+}
+
+// handleCaptchaCallback captcha tugmasi bosilganda javobni tekshiradi
+func (h *CommandHandler) handleCaptchaCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, args []string, log *logger.Logger) CallbackResult {
+	if len(args) != 2 || callback.Message == nil || callback.From == nil {
+		return CallbackResult{}
+	}
+	userID, err1 := strconv.ParseInt(args[0], 10, 64)
+	option, err2 := strconv.Atoi(args[1])
+	if err1 != nil || err2 != nil {
+		return CallbackResult{}
+	}
+
+	// Savolga faqat yangi a'zoning o'zi javob bera oladi
+	t := translatorFrom(ctx)
+	if callback.From.ID != userID {
+		return callbackToast(t.text("captcha_not_for_you", nil))
+	}
+
+	chatID := callback.Message.Chat.ID
+	challenge, ok := h.finishCaptcha(bot, chatID, userID)
+	if !ok {
+		return callbackToast(t.text("captcha_expired", nil))
+	}
+
+	if option != challenge.Answer {
+		if err := kickMember(bot, chatID, userID); err != nil {
+			log.Errorf("Captchadan o'tmagan foydalanuvchini chiqarishda xatolik: %v", err)
+		} else {
+			h.recordModeration(bot, callback.Message.Chat, nil, &challenge.User, modlog.Entry{Action: modlog.Kick, Source: modlog.SourceCaptcha}, log)
+		}
+		log.Infof("%d foydalanuvchi %d guruhda captchaga noto'g'ri javob berdi", userID, chatID)
+		return callbackToast(t.text("captcha_wrong", nil))
+	}
+
+	if err := unmuteMember(bot, chatID, userID); err != nil {
+		log.Errorf("Foydalanuvchi cheklovini olib tashlashda xatolik: %v", err)
+	}
+	log.Infof("%d foydalanuvchi %d guruhda captchadan o'tdi", userID, chatID)
+	h.verified(bot, chatID, challenge.User)
+	return callbackToast(t.text("captcha_passed", nil))
+}
+
+// Stop kutilayotgan tekshiruv taymerlarini to'xtatadi
+// Tekshiruvlar omborda qoladi va keyingi ishga tushirishda ResumeCaptchas orqali tiklanadi
+func (h *CommandHandler) Stop() {
+	h.captchaMu.Lock()
+	defer h.captchaMu.Unlock()
+
+	for key, timer := range h.captchaTimers {
+		timer.Stop()
+		delete(h.captchaTimers, key)
+	}
+}
+
+// verified tasdiqlangan a'zo uchun o'rnatilgan funksiyani chaqiradi
+func (h *CommandHandler) verified(bot *tgbotapi.BotAPI, chatID int64, user tgbotapi.User) {
+	if h.memberVerified != nil {
+		h.memberVerified(bot, chatID, user)
+	}
+}
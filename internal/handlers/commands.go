@@ -3,431 +3,423 @@
 package handlers
 
 import (
-	"fmt"
-	"strings"
-
+	"context"
+	"sync"
+	"time"
+
+	"tg-bot/internal/antispam"
+	"tg-bot/internal/config"
+	"tg-bot/internal/content"
+	"tg-bot/internal/conversation"
+	"tg-bot/internal/docs"
+	"tg-bot/internal/flood"
+	"tg-bot/internal/modlog"
+	"tg-bot/internal/quiz"
+	"tg-bot/internal/releases"
+	"tg-bot/internal/reports"
+	"tg-bot/internal/roadmap"
+	"tg-bot/internal/sandbox"
+	"tg-bot/internal/snippet"
+	"tg-bot/internal/storage"
 	"tg-bot/pkg/logger"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-// commandHandler butun dastur davomida ishlatiladigan global obyekt
-// Bu barcha buyruqlar va ularning javoblarini saqlash uchun ishlatiladi
-var commandHandler *CommandHandler
+// Config buyruqlarni qayta ishlash uchun zarur sozlamalarni belgilaydi
+type Config interface {
+	// WarnPolicy guruh uchun ogohlantirish chegaralarini qaytaradi
+	WarnPolicy(chatID int64) config.WarnPolicy
+	// CaptchaSettings yangi a'zolarni tekshirish sozlamalarini qaytaradi
+	CaptchaSettings() config.CaptchaSettings
+	// SnippetSettings kod parchalarini formatlash va tekshirish chegaralarini qaytaradi
+	SnippetSettings() config.SnippetSettings
+	// QuizSettings viktorina sozlamalarini qaytaradi
+	QuizSettings() config.QuizSettings
+	// StartSecret /start havolalari parametrlarini imzolash kalitini qaytaradi
+	StartSecret() string
+	// AntispamSettings spam filtrlari sozlamalarini qaytaradi
+	AntispamSettings() config.AntispamSettings
+	// FloodSettings flood nazorati sozlamalarini qaytaradi
+	FloodSettings() config.FloodSettings
+	// ReportSettings shikoyatlar sozlamalarini qaytaradi
+	ReportSettings() config.ReportSettings
+	// ModlogSettings moderatsiya jurnali sozlamalarini qaytaradi
+	ModlogSettings() config.ModlogSettings
+	// ContentSettings bot matnlari sozlamalarini qaytaradi
+	ContentSettings() config.ContentSettings
+}
 
 // CommandFunction muayyan buyruqni bajaradigan funksiya turi
 // Har bir buyruq alohida funksiya sifatida implementatsiya qilinadi
-type CommandFunction func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger)
-
-// commandHandlers barcha buyruq funksiyalarini saqlovchi xarita
-// Bu xarita buyruq nomi va uni qayta ishlovchi funksiya o'rtasidagi bog'lanishni ta'minlaydi
-var commandHandlers map[string]CommandFunction
+// ctx bot to'xtatilayotganda va kutish muddati tugaganda bekor qilinadi
+type CommandFunction func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger)
 
 // RegisterBotCommands botga barcha mavjud buyruqlarni ro'yxatdan o'tkazadi
-// Bu funksiya bot ishga tushganda bir marta chaqiriladi va barcha buyruqlarni sozlaydi
-func RegisterBotCommands(bot *tgbotapi.BotAPI, log *logger.Logger) {
-	// Agar commandHandler yaratilmagan bo'lsa, yangi instance yaratish
-	if commandHandler == nil {
-		commandHandler = NewCommandHandler(log)
-	}
-
-	// Buyruqlar xaritasini yaratish
-	commandHandlers = make(map[string]CommandFunction)
-
-	// Har bir buyruq uchun qayta ishlovchi funksiyani ro'yxatdan o'tkazish
+// Bu funksiya bot ishga tushganda bir marta chaqiriladi, barcha buyruqlarni sozlaydi va ularni Telegram menyusiga yuboradi
+func (h *CommandHandler) RegisterBotCommands(bot *tgbotapi.BotAPI) {
+	// Barcha buyruqlar uchun umumiy middleware'lar
+	h.Use(
+		Recover(),
+		Logging(),
+		RateLimit(5, 10*time.Second),
+	)
+
+	// Har bir buyruq uchun tavsif va qayta ishlovchi funksiyani ro'yxatdan o'tkazish
+	// Tavsiflar Telegram menyusi va /help uchun ishlatiladi
 	// START buyrug'i - botni ishga tushirish va salomlashish xabarini yuborish
-	commandHandlers["start"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetStartText())
-		bot.Send(msg)
-	}
+	h.Handle(CommandSpec{
+		Name:        "start",
+		Description: map[string]string{"": "botni ishga tushirish", "ru": "запустить бота", "en": "start the bot"},
+		Scope:       ScopePrivate,
+		Hidden:      true,
+	}, h.handleStart)
+	h.registerStartPayloads()
+	h.registerCallbacks()
+	h.registerRulesDialog()
 
 	// HELP buyrug'i - mavjud buyruqlar ro'yxati va ularning tavsifi
-	commandHandlers["help"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetHelpText())
-		bot.Send(msg)
-	}
+	h.Handle(CommandSpec{
+		Name:        "help",
+		Description: map[string]string{"": "ushbu xabarni qayta ko'rsatish", "ru": "список команд", "en": "list available commands"},
+	}, h.handleHelp)
 
 	// RULES buyrug'i - hamjamiyat qoidalari
-	commandHandlers["rules"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetRulesText())
-		bot.Send(msg)
-	}
+	h.Handle(CommandSpec{
+		Name:        "rules",
+		Description: map[string]string{"": "qoidalarni aks ettirish", "ru": "правила сообщества", "en": "community rules"},
+	}, h.handleRules)
+
+	// CANCEL buyrug'i - faol suhbatni (masalan /setrules savoliga javobni) bekor qilish
+	h.Handle(CommandSpec{
+		Name:        "cancel",
+		Description: map[string]string{"": "joriy amalni bekor qilish", "ru": "отменить текущее действие", "en": "cancel the current action"},
+	}, h.handleCancel)
+
+	// SETRULES buyrug'i - guruhning o'z qoidalarini belgilash (faqat adminlar uchun)
+	h.Handle(CommandSpec{
+		Name:        "setrules",
+		Description: map[string]string{"": "guruh qoidalarini belgilash", "ru": "задать правила группы", "en": "set the group rules"},
+		Scope:       ScopeAdmins,
+	}, h.handleSetRules, GroupOnly(), AdminOnly())
 
 	// ABOUT buyrug'i - bot va uning maqsadi haqida ma'lumot
-	commandHandlers["about"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetAboutText())
-		bot.Send(msg)
-	}
+	h.Handle(CommandSpec{
+		Name:        "about",
+		Description: map[string]string{"": "ushbu botimizning rivojlantirish qismi", "ru": "о боте", "en": "about this bot"},
+	}, h.contentCommand("about"))
 
 	// GROUP buyrug'i - Go bo'yicha guruhlar va hamjamiyatlar haqida ma'lumot
-	commandHandlers["group"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetGroupText())
-		bot.Send(msg)
-	}
+	h.Handle(CommandSpec{
+		Name:        "group",
+		Description: map[string]string{"": "Go ga oid guruh va hamjamiyatlar", "ru": "группы и сообщества по Go", "en": "Go groups and communities"},
+	}, h.contentCommand("group"))
 
-	// ROADMAP buyrug'i - Go o'rganish yo'l xaritasi
-	commandHandlers["roadmap"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetRoadmapText())
-		bot.Send(msg)
-	}
+	// ROADMAP buyrug'i - Go o'rganish yo'l xaritasi (shaxsiy chatda mavzularni belgilab borish mumkin)
+	h.Handle(CommandSpec{
+		Name:        "roadmap",
+		Description: map[string]string{"": "boshlang'ich o'rganuvchilar uchun", "ru": "план изучения Go", "en": "Go learning roadmap"},
+	}, h.handleRoadmap)
 
 	// USEFUL buyrug'i - Go bo'yicha foydali resurslar
-	commandHandlers["useful"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetUsefulText())
-		bot.Send(msg)
-	}
+	h.Handle(CommandSpec{
+		Name:        "useful",
+		Description: map[string]string{"": "Go haqida foydali yoki kerakli ma'lumotlar", "ru": "полезные ресурсы по Go", "en": "useful Go resources"},
+	}, h.contentCommand("useful"))
 
 	// LATEST buyrug'i - eng so'nggi Go versiyasi haqida ma'lumot
-	commandHandlers["latest"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetLatestText())
-		bot.Send(msg)
-	}
+	h.Handle(CommandSpec{
+		Name:        "latest",
+		Description: map[string]string{"": "eng oxirgi reliz haqida qisqacha ma'lumot", "ru": "последний релиз Go", "en": "latest Go release"},
+	}, h.handleLatest)
 
 	// VERSION buyrug'i - so'ralgan Go versiyasi haqida batafsil ma'lumot
-	commandHandlers["version"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetVersionText(message.CommandArguments()))
-		bot.Send(msg)
+	h.Handle(CommandSpec{
+		Name:        "version",
+		Description: map[string]string{"": "biron aniq reliz haqida to'liq ma'lumot", "ru": "информация о версии Go", "en": "details about a Go release"},
+	}, h.handleVersion)
+
+	// SUBSCRIBE buyrug'i - yangi Go relizlari haqidagi e'lonlarga obuna bo'lish
+	h.Handle(CommandSpec{
+		Name:        "subscribe",
+		Description: map[string]string{"": "yangi relizlar haqida xabar olish", "ru": "подписаться на новости о релизах", "en": "subscribe to release announcements"},
+	}, h.handleSubscribe)
+
+	// UNSUBSCRIBE buyrug'i - relizlar e'lonlaridan chiqish
+	h.Handle(CommandSpec{
+		Name:        "unsubscribe",
+		Description: map[string]string{"": "relizlar haqidagi xabarlarni o'chirish", "ru": "отписаться от новостей о релизах", "en": "stop release announcements"},
+	}, h.handleUnsubscribe)
+
+	// DOC buyrug'i - standart kutubxona hujjatlari (indeks o'chirilgan bo'lsa ro'yxatdan o'tkazilmaydi)
+	if h.docs != nil {
+		h.Handle(CommandSpec{
+			Name:        "doc",
+			Description: map[string]string{"": "standart kutubxona hujjatlari", "ru": "документация стандартной библиотеки", "en": "standard library docs"},
+		}, h.handleDoc)
+	}
+
+	// FMT buyrug'i - javob berilgan xabardagi Go kodini gofmt bo'yicha formatlash
+	h.Handle(CommandSpec{
+		Name:        "fmt",
+		Description: map[string]string{"": "Go kodini formatlash", "ru": "отформатировать Go-код", "en": "format a Go snippet"},
+	}, h.handleFmt)
+
+	// VET buyrug'i - javob berilgan xabardagi Go kodini kompilyatsiya xatolariga tekshirish
+	h.Handle(CommandSpec{
+		Name:        "vet",
+		Description: map[string]string{"": "Go kodini xatolarga tekshirish", "ru": "проверить Go-код на ошибки", "en": "check a Go snippet for errors"},
+	}, h.handleVet)
+
+	// RUN buyrug'i - Go dasturini sandbox ichida bajarish (sozlamalarda yoqilgan bo'lsa)
+	if h.runner != nil {
+		h.Handle(CommandSpec{
+			Name:        "run",
+			Description: map[string]string{"": "Go dasturini ishga tushirish", "ru": "запустить Go-программу", "en": "run a Go program"},
+		}, h.handleRun)
 	}
 
-	// WARN buyrug'i - foydalanuvchiga ogohlantirish xabarini yuborish
-	commandHandlers["warn"] = func(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
-		msg := tgbotapi.NewMessage(message.Chat.ID, commandHandler.GetWarnText(message.From.UserName))
-		bot.Send(msg)
+	// QUIZ, TOP va QUIZDAILY buyruqlari - Go viktorinasi (savollar banki yuklanmagan bo'lsa ro'yxatdan o'tkazilmaydi)
+	if h.quiz != nil {
+		h.Handle(CommandSpec{
+			Name:        "quiz",
+			Description: map[string]string{"": "Go bo'yicha viktorina savoli", "ru": "вопрос викторины по Go", "en": "Go quiz question"},
+		}, h.handleQuiz)
+		h.Handle(CommandSpec{
+			Name:        "top",
+			Description: map[string]string{"": "viktorina reytingi", "ru": "рейтинг викторины", "en": "quiz leaderboard"},
+		}, h.handleTop)
+		h.Handle(CommandSpec{
+			Name:        "quizdaily",
+			Description: map[string]string{"": "kun savoli vaqtini belgilash", "ru": "настроить вопрос дня", "en": "schedule the question of the day"},
+			Scope:       ScopeAdmins,
+		}, h.handleQuizDaily, GroupOnly(), AdminOnly())
 	}
 
-	log.Info("Bot buyruqlari ro'yxatdan o'tkazildi")
+	// WARN buyrug'i - javob berilgan xabar muallifiga ogohlantirish berish (faqat adminlar uchun)
+	h.Handle(CommandSpec{
+		Name:        "warn",
+		Description: map[string]string{"": "mavzudan chetlashganga ogohlantiruv", "ru": "выдать предупреждение", "en": "warn a member"},
+		Scope:       ScopeAdmins,
+	}, h.handleWarn, GroupOnly(), AdminOnly())
+
+	// WARNS buyrug'i - foydalanuvchining ogohlantirishlar tarixi
+	h.Handle(CommandSpec{
+		Name:        "warns",
+		Description: map[string]string{"": "ogohlantirishlar tarixini ko'rish", "ru": "история предупреждений", "en": "warning history"},
+		Scope:       ScopeGroup,
+	}, h.handleWarns, GroupOnly())
+
+	// UNWARN buyrug'i - foydalanuvchining ogohlantirishini olib tashlash (faqat adminlar uchun)
+	h.Handle(CommandSpec{
+		Name:        "unwarn",
+		Description: map[string]string{"": "ogohlantirishni olib tashlash", "ru": "снять предупреждение", "en": "remove a warning"},
+		Scope:       ScopeAdmins,
+	}, h.handleUnwarn, GroupOnly(), AdminOnly())
+
+	// BAN, UNBAN, MUTE, UNMUTE va KICK buyruqlari - a'zolarni cheklash (a'zolarni cheklash huquqiga ega adminlar uchun)
+	// Foydalanuvchi xabariga javob berib yoki @username/ID orqali ko'rsatiladi, /ban va /mute muddat qabul qiladi: /mute 2h spam
+	h.Handle(CommandSpec{
+		Name:        "ban",
+		Description: map[string]string{"": "a'zoni guruhdan chetlatish", "ru": "заблокировать участника", "en": "ban a member"},
+		Scope:       ScopeAdmins,
+	}, h.handleBan, GroupOnly(), RequireRight(rightRestrict))
+	h.Handle(CommandSpec{
+		Name:        "unban",
+		Description: map[string]string{"": "chetlatishni bekor qilish", "ru": "разблокировать участника", "en": "unban a member"},
+		Scope:       ScopeAdmins,
+	}, h.handleUnban, GroupOnly(), RequireRight(rightRestrict))
+	h.Handle(CommandSpec{
+		Name:        "mute",
+		Description: map[string]string{"": "a'zoni ovozsiz qilish", "ru": "запретить участнику писать", "en": "mute a member"},
+		Scope:       ScopeAdmins,
+	}, h.handleMute, GroupOnly(), RequireRight(rightRestrict))
+	h.Handle(CommandSpec{
+		Name:        "unmute",
+		Description: map[string]string{"": "ovozsiz qilishni bekor qilish", "ru": "снять запрет писать", "en": "unmute a member"},
+		Scope:       ScopeAdmins,
+	}, h.handleUnmute, GroupOnly(), RequireRight(rightRestrict))
+	h.Handle(CommandSpec{
+		Name:        "kick",
+		Description: map[string]string{"": "a'zoni guruhdan chiqarish", "ru": "исключить участника", "en": "kick a member"},
+		Scope:       ScopeAdmins,
+	}, h.handleKick, GroupOnly(), RequireRight(rightRestrict))
+
+	// PURGE buyrug'i - bir nechta xabarni birdaniga o'chirish (xabarlarni o'chirish huquqiga ega adminlar uchun)
+	h.Handle(CommandSpec{
+		Name:        "purge",
+		Description: map[string]string{"": "xabarlarni ommaviy o'chirish", "ru": "удалить несколько сообщений", "en": "delete a range of messages"},
+		Scope:       ScopeAdmins,
+	}, h.handlePurge, GroupOnly(), RequireRight(rightDelete))
+
+	// PIN va UNPIN buyruqlari - xabarlarni qadash (xabarlarni qadash huquqiga ega adminlar uchun)
+	h.Handle(CommandSpec{
+		Name:        "pin",
+		Description: map[string]string{"": "xabarni qadash", "ru": "закрепить сообщение", "en": "pin a message"},
+		Scope:       ScopeAdmins,
+	}, h.handlePin, GroupOnly(), RequireRight(rightPin))
+	h.Handle(CommandSpec{
+		Name:        "unpin",
+		Description: map[string]string{"": "qadalgan xabarni olib tashlash", "ru": "открепить сообщение", "en": "unpin a message"},
+		Scope:       ScopeAdmins,
+	}, h.handleUnpin, GroupOnly(), RequireRight(rightPin))
+
+	// SLOWMODE buyrug'i - guruh a'zolari xabarlari orasidagi eng kam oraliqni belgilash (faqat adminlar uchun)
+	h.Handle(CommandSpec{
+		Name:        "slowmode",
+		Description: map[string]string{"": "xabarlar orasidagi oraliqni belgilash", "ru": "медленный режим для участников", "en": "set a minimum interval between messages"},
+		Scope:       ScopeAdmins,
+	}, h.handleSlowMode, GroupOnly(), AdminOnly())
+
+	// MODLOG buyrug'i - guruh moderatsiya jurnali: oxirgi choralar, foydalanuvchi tarixi va jurnal kanali (faqat adminlar uchun)
+	h.Handle(CommandSpec{
+		Name:        "modlog",
+		Description: map[string]string{"": "moderatsiya jurnalini ko'rish", "ru": "журнал модерации", "en": "view the moderation log"},
+		Scope:       ScopeAdmins,
+	}, h.handleModlog, GroupOnly(), AdminOnly())
+
+	// REPORT buyrug'i - javob berilgan xabar haqida adminlarga shikoyat qilish
+	reportMiddlewares := []Middleware{GroupOnly()}
+	if settings := h.config.ReportSettings(); settings.Limit > 0 {
+		reportMiddlewares = append(reportMiddlewares, RateLimit(settings.Limit, settings.Window))
+	}
+	h.Handle(CommandSpec{
+		Name:        "report",
+		Description: map[string]string{"": "xabar haqida adminlarga shikoyat qilish", "ru": "пожаловаться админам на сообщение", "en": "report a message to admins"},
+		Scope:       ScopeGroup,
+	}, h.handleReport, reportMiddlewares...)
+
+	// LANG buyrug'i - bot tilini tanlash
+	h.Handle(CommandSpec{
+		Name:        "lang",
+		Description: map[string]string{"": "bot tilini tanlash", "ru": "выбрать язык бота", "en": "choose bot language"},
+	}, h.handleLang)
+
+	// RELOAD buyrug'i - matnlarni fayllardan qayta yuklash (faqat bot egalari uchun)
+	// Matnlar barcha chatlar uchun umumiy, shuning uchun guruh adminligi yetarli emas va buyruq menyuda ko'rsatilmaydi
+	h.Handle(CommandSpec{
+		Name:        "reload",
+		Description: map[string]string{"": "bot matnlarini qayta yuklash", "ru": "перезагрузить тексты бота", "en": "reload bot texts"},
+		Hidden:      true,
+	}, h.handleReload, OwnerOnly(h.config.ContentSettings().Owners))
+
+	h.logger.Info("Bot buyruqlari ro'yxatdan o'tkazildi")
+
+	// Buyruqlar menyusini Telegram'ga yuborish
+	// Xatoliklar PublishCommands ichida log qilinadi va botning ishlashiga to'sqinlik qilmaydi
+	h.PublishCommands(bot)
+}
+
+// sendText chatga oddiy matnli xabar yuboradi va xatolikni log qiladi
+func sendText(bot *tgbotapi.BotAPI, chatID int64, text string, log *logger.Logger) {
+	if _, err := bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+		log.Error("Xabar yuborishda xatolik yuz berdi:", err)
+	}
 }
 
 // GetCommandHandler ma'lum bir buyruq uchun qayta ishlovchi funksiyani qaytaradi
+// Qaytarilgan funksiya umumiy va buyruqqa tegishli middleware'lar bilan o'ralgan bo'ladi,
+// kontekstga esa foydalanuvchi tilidagi translator qo'shiladi
 // Bu funksiya asosiy bot logikasi tomonidan buyruq aniqlanganda chaqiriladi
-func GetCommandHandler(command string) CommandFunction {
-	handler, exists := commandHandlers[command]
+func (h *CommandHandler) GetCommandHandler(name string) CommandFunction {
+	cmd, exists := h.commands[name]
 	if !exists {
 		return nil
 	}
-	return handler
-}
 
-// HandleCallback inline klaviatura tugmachalaridan kelgan callback so'rovlarini qayta ishlaydi
-// Bu funksiya foydalanuvchi inline tugmani bosganda chaqiriladi
-func HandleCallback(bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, log *logger.Logger) {
-	// Callback so'rovini qabul qilganligimizni Telegram'ga xabar berish
-	// Bu foydalanuvchi interfeysi uchun muhim, chunki tugmani bosish animatsiyasini to'xtatadi
-	callback_config := tgbotapi.NewCallback(callback.ID, "")
-	bot.Send(callback_config)
-
-	// Callback ma'lumotlarini qayta ishlash
-	log.Debugf("Callback qabul qilindi: %s", callback.Data)
-
-	switch callback.Data {
-	case "about":
-		// Bot haqida ma'lumot yuborish
-		msg := tgbotapi.NewMessage(callback.Message.Chat.ID, commandHandler.GetAboutText())
-		bot.Send(msg)
-	case "roadmap":
-		// Go o'rganish yo'l xaritasini yuborish
-		msg := tgbotapi.NewMessage(callback.Message.Chat.ID, commandHandler.GetRoadmapText())
-		bot.Send(msg)
-	default:
-		// Noma'lum callback ID kelsa, xatolik haqida ma'lumot berish
-		msg := tgbotapi.NewMessage(callback.Message.Chat.ID, "Noma'lum tugma bosildi. Iltimos qaytadan urinib ko'ring.")
-		bot.Send(msg)
+	middlewares := make([]Middleware, 0, len(h.middlewares)+len(cmd.middlewares))
+	middlewares = append(middlewares, h.middlewares...)
+	middlewares = append(middlewares, cmd.middlewares...)
+	fn := Chain(cmd.fn, middlewares...)
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+		ctx = withTranslator(ctx, h.translator(message.Chat, message.From))
+		fn(ctx, bot, message, log)
 	}
 }
 
 // CommandHandler buyruqlar va ularning mantiqini o'z ichiga oluvchi asosiy tuzilma
 // Bu tuzilma barcha bot buyruqlari uchun javoblarni generatsiya qilish funksiyalarini o'z ichiga oladi
 type CommandHandler struct {
-	logger *logger.Logger // Logger obyekti, xatoliklar va hodisalarni qayd etish uchun
+	config        Config                   // Buyruqlar uchun sozlamalar
+	store         storage.Store            // Bot holati saqlanadigan ombor
+	texts         *content.Store           // Fayllardan yuklangan bot matnlari
+	releases      *releases.DB             // Go relizlari haqidagi ma'lumotlar
+	docs          *docs.Index              // Standart kutubxona hujjatlari indeksi (o'chirilgan bo'lsa nil)
+	snippets      *snippet.Checker         // /fmt va /vet uchun kod tekshiruvchi
+	runner        *sandbox.Runner          // /run uchun sandbox (o'chirilgan bo'lsa nil)
+	quiz          *quiz.Bank               // Viktorina savollari banki (o'chirilgan bo'lsa nil)
+	scores        *quiz.Scores             // Viktorina natijalari
+	roadmap       *roadmap.Roadmap         // Interaktiv yo'l xaritasi (yuklanmagan bo'lsa nil)
+	progress      *roadmap.Tracker         // Foydalanuvchilar belgilagan mavzular
+	conversations *conversation.Store      // Foydalanuvchilar bilan faol suhbatlar holati
+	antispam      *antispam.Pipeline       // Guruh xabarlarini tekshiruvchi spam filtrlari (o'chirilgan bo'lsa nil)
+	flood         *flood.Limiter           // Guruhlardagi flood va slow mode hisoblagichi
+	modlog        *modlog.Log              // Adminlar ko'rgan moderatsiya choralari jurnali
+	reports       *reports.Store           // A'zolar /report orqali yuborgan shikoyatlar
+	logger        *logger.Logger           // Logger obyekti, xatoliklar va hodisalarni qayd etish uchun
+	commands      map[string]command       // Buyruq nomi va uni qayta ishlovchi funksiya o'rtasidagi bog'lanish
+	order         []string                 // Buyruqlarning ro'yxatdan o'tish tartibi (menyu va /help uchun)
+	payloads      map[string]startPayload  // /start havolasi parametrlari va ularni qayta ishlovchilar
+	callbacks     map[string]callbackRoute // Inline tugmalar nomi va ularni qayta ishlovchilar
+	dialogs       map[string]DialogSpec    // Ko'p qadamli suhbatlar turlari
+	middlewares   []Middleware             // Barcha buyruqlarga qo'llaniladigan middleware'lar
+
+	quizLocation *time.Location // Kun savoli vaqti hisoblanadigan mintaqa
+
+	captchaMu      sync.Mutex                                                   // captchaTimers uchun qulf
+	captchaTimers  map[string]*time.Timer                                       // Kutilayotgan tekshiruvlar taymerlari
+	memberVerified func(bot *tgbotapi.BotAPI, chatID int64, user tgbotapi.User) // Yangi a'zo tasdiqlanganda chaqiriladi
 }
 
 // NewCommandHandler yangi CommandHandler obyektini yaratish uchun konstruktor metod
 // Bu funksiya har bir buyruq qayta ishlovchisi uchun kerakli narsalarni tayyorlaydi
-func NewCommandHandler(logger *logger.Logger) *CommandHandler {
+func NewCommandHandler(cfg Config, store storage.Store, texts *content.Store, releases *releases.DB, docs *docs.Index, runner *sandbox.Runner, bank *quiz.Bank, roadmapData *roadmap.Roadmap, filters *antispam.Pipeline, logger *logger.Logger) *CommandHandler {
+	limits := snippet.Limits{
+		MaxBytes: cfg.SnippetSettings().MaxBytes,
+		Timeout:  cfg.SnippetSettings().Timeout,
+	}
+	location, err := time.LoadLocation(cfg.QuizSettings().Timezone)
+	if err != nil {
+		logger.Warnf("Viktorina vaqt mintaqasi noto'g'ri, UTC ishlatiladi: %v", err)
+		location = time.UTC
+	}
+	// Flood nazorati o'chirilgan bo'lsa hisoblagich faqat /slowmode uchun ishlaydi
+	var floodLimits flood.Limits
+	if settings := cfg.FloodSettings(); settings.Enabled {
+		floodLimits = flood.Limits{Messages: settings.Messages, Window: settings.Window, MuteAfter: settings.MuteAfter}
+	}
 	return &CommandHandler{
-		logger: logger,
+		config:        cfg,
+		store:         store,
+		texts:         texts,
+		releases:      releases,
+		docs:          docs,
+		snippets:      snippet.NewChecker(limits),
+		runner:        runner,
+		quiz:          bank,
+		scores:        quiz.NewScores(store),
+		roadmap:       roadmapData,
+		progress:      roadmap.NewTracker(store),
+		conversations: conversation.New(store),
+		antispam:      filters,
+		flood:         flood.NewLimiter(floodLimits),
+		modlog:        modlog.New(store),
+		reports:       reports.New(store),
+		logger:        logger,
+		quizLocation:  location,
+		captchaTimers: make(map[string]*time.Timer),
 	}
 }
 
 // HandleCommand barcha buyruqlar uchun qayta ishlash funksiyasi (eski usul)
-// Bu metod to'g'ridan-to'g'ri Update obektini qabul qiladi va kerakli javoblarni yuboradi
+// Bu metod to'g'ridan-to'g'ri Update obektini qabul qiladi va buyruqni ro'yxatdan o'tgan qayta ishlovchiga uzatadi
 func (h *CommandHandler) HandleCommand(bot *tgbotapi.BotAPI, update tgbotapi.Update) {
 	h.logger.Debug("Yangi buyruq qabul qilindi: ", update.Message.Command())
 
-	msg := tgbotapi.NewMessage(update.Message.Chat.ID, "")
-
-	// Buyruq turini aniqlash va tegishli funksiyani chaqirish
-	switch update.Message.Command() {
-	case "start":
-		msg.Text = h.GetStartText()
-	case "help":
-		msg.Text = h.GetHelpText()
-	case "rules":
-		msg.Text = h.GetRulesText()
-	case "about":
-		msg.Text = h.GetAboutText()
-	case "group":
-		msg.Text = h.GetGroupText()
-	case "roadmap":
-		msg.Text = h.GetRoadmapText()
-	case "useful":
-		msg.Text = h.GetUsefulText()
-	case "latest":
-		msg.Text = h.GetLatestText()
-	case "version":
-		msg.Text = h.GetVersionText(update.Message.CommandArguments())
-	case "warn":
-		msg.Text = h.GetWarnText(update.Message.From.UserName)
-	default:
-		msg.Text = "Noma'lum buyruq. Yordam olish uchun /help buyrug'ini ishlatib ko'ring."
-	}
-
-	// Javob xabarini yuborish
-	if _, err := bot.Send(msg); err != nil {
-		h.logger.Error("Xabar yuborishda xatolik yuz berdi: ", err)
-	}
-}
-
-// GetStartText bot bilan ilk muloqotda yuboriladigan salomlashish xabari
-// Bu xabar foydalanuvchi botdan qanday foydalanish mumkinligi haqida qisqacha ma'lumot beradi
-func (h *CommandHandler) GetStartText() string {
-	return `Assalomu alaykum! GoferUz Golang botiga xush kelibsiz 👋
-
-Bu bot Go dasturlash tili bo'yicha ma'lumotlar va resurslarni taqdim etish uchun yaratilgan.
-
-Mavjud buyruqlar ro'yxatini ko'rish uchun /help buyrug'ini yuboring.
-O'zbekistondagi Go dasturchilar hamjamiyati haqida ma'lumot olish uchun /group buyrug'ini yuboring.`
-}
-
-// GetHelpText mavjud barcha buyruqlar ro'yxati va ularning qisqacha tavsifi
-// Bu funksiya foydalanuvchi botdan to'liq foydalanish uchun qo'llanma vazifasini bajaradi
-func (h *CommandHandler) GetHelpText() string {
-	return `Mavjud komandalar ro'yxati:
-
-/help - ushbu xabarni qayta ko'rsatish
-/rules - qoidalarni aks ettirish
-/about - ushbu botimizning rivojlantirish qismi
-/group - Go ga oid guruh va hamjamiyatlar
-/roadmap - boshlang'ich o'rganuvchilar uchun
-/useful - Go haqida foydali yoki kerakli ma'lumotlar
-/latest - eng oxirgi reliz haqida qisqacha ma'lumot
-/version - biron anniq reliz haqida to'liq ma'lumot
-/warn - mavzudan chetlashganga ogohlantiruv`
-}
-
-// GetRulesText hamjamiyat va guruh uchun qoidalar to'plami
-// Bu qoidalar hamjamiyat a'zolari o'rtasida hurmat va professionallikni ta'minlaydi
-func (h *CommandHandler) GetRulesText() string {
-	return `GoferUz hamjamiyati qoidalari:
-
-1. Hurmat bilan munosabatda bo'ling - boshqa a'zolarga nisbatan doimo hurmat va e'tibor ko'rsating
-2. Spam yoki reklama tarqatmang - ruxsatsiz reklama materiallarini jo'natmang
-3. Siyosiy va diniy mavzulardan chetlaning - guruh faqat Go dasturlash tili uchun
-4. Go dasturlash tili bo'yicha savollarda aniq va foydali bo'ling
-5. Maqsadimiz - O'zbekistonda Go dasturlash tilini rivojlantirish va Go jamoasini kengaytirish
-
-Qoidalarga rioya qilmaslik ogohlantirishga, takrorlanishi esa guruhdan chetlashtirishga sabab bo'lishi mumkin.`
-}
-
-// GetAboutText bot haqida va uning yaratilishi to'g'risidagi ma'lumotlarni qaytaradi
-// Bu funksiya bot arxitekturasi va uni kim tomonidan yaratilgani haqida ma'lumot beradi
-func (h *CommandHandler) GetAboutText() string {
-	return `Bu bot Go dasturlash tilida yaratilgan va Go dasturlash tiliga bag'ishlangan.
-Botning asosiy maqsadi - Go o'rganuvchilar uchun foydali ma'lumotlarni tezkor taqdim etish va 
-O'zbekistondagi Go hamjamiyatini qo'llab-quvvatlash.
-
-Bot arxitekturasi:
-- cmd/bot - asosiy dastur kirish nuqtasi va bot logikasi
-- internal/config - konfiguratsiya sozlamalari va muhit o'zgaruvchilari bilan ishlash
-- internal/handlers - barcha buyruqlarni qayta ishlash mantiqiy qismi
-- pkg/logger - tizimli log yozish uchun maxsus kutubxona
-
-Botning joriy versiyasi: 1.0.0
-Muallif: haywan
-
-Bot Golang tilida yozilgan. Go - bu Google tomonidan yaratilgan, yuqori samaradorlikka ega, 
-statik tipli va kompilyatsiya qilinadigan zamonaviy dasturlash tili. Go dasturlari juda tezkor 
-ishlaydi, parallel dasturlashni osonlashtiradi va xotiradan samarali foydalanadi.`
-}
-
-// GetGroupText Go dasturlash tili bo'yicha guruhlar va hamjamiyatlar haqida ma'lumot
-// Bu funksiya Go bo'yicha O'zbekiston va xalqaro darajadagi hamjamiyatlar haqida ma'lumot beradi
-func (h *CommandHandler) GetGroupText() string {
-	return `Go dasturlash tili bo'yicha guruhlar va hamjamiyatlar:
-
-🌍 O'zbekiston hamjamiyati:
-- Telegram: @goferuz - O'zbekistondagi Go dasturchilar guruhi
-- Veb-sayt: https://gopher.uz - O'zbekistonlik gopher'lar uchun portal
-
-🌐 Xalqaro hamjamiyatlar:
-- GitHub: https://github.com/goferuz - O'zbek Go dasturlari repozitoriyalari
-- Forum: https://forum.golangbridge.org/ - Go dasturchilar forumi
-- Reddit: https://www.reddit.com/r/golang/ - Go haqidagi Reddit jamiyati
-- Slack: https://gophers.slack.com/ - Go dasturchilar uchun Slack kanali
-- Discord: https://discord.gg/golang - Go dasturchilarning Discord serveri
-- Stack Overflow: https://stackoverflow.com/questions/tagged/go - Go savollari bazasi
-
-Ushbu hamjamiyatlarga qo'shilish orqali siz Go bo'yicha bilimlaringizni oshirish 
-va tajribali dasturchilar bilan muloqot qilish imkoniyatiga ega bo'lasiz.`
-}
-
-// GetRoadmapText Go dasturlash tilini o'rganish uchun yo'l xaritasini qaytaradi
-// Bu funksiya bosqichma-bosqich Go ni o'rganish rejasini taqdim etadi
-func (h *CommandHandler) GetRoadmapText() string {
-	return `Go dasturlash tilini o'rganish uchun mukammal yo'l xaritasi:
-
-1️⃣ Go asoslari - o'zgaruvchilar, turlari va funksiyalar
-   - O'zgaruvchilar va konstantalar deklaratsiyasi (var, const)
-   - Asosiy ma'lumot turlari (int, float64, bool, string, rune)
-   - Funksiyalar, qaytarish qiymatlari va ko'p qaytarishlar
-
-2️⃣ Ma'lumot tuzilmalari
-   - Massivlar (o'zgarmas o'lcham) va slayslar (dinamik o'lcham)
-   - Map (xaritalar) - kalit/qiymat juftliklari bilan ishlash
-   - Strukturalar (struct) va ularning usullari (methods)
-
-3️⃣ Dastur oqimi boshqaruvi
-   - If/else shartli ifodalar
-   - For looplarining turli ko'rinishlari
-   - Switch va select ifodalar
-
-4️⃣ Paralel dasturlash asoslari
-   - Goroutine - Go'ning engil vazn thread'lari
-   - Kanallar (channel) orqali ma'lumot almashish
-   - Sync paketi va mutex yordamida sinxronizatsiya
-
-5️⃣ Interfeys va xatolar bilan ishlash
-   - Interfeys tushunchasi va duck typing
-   - Xatolarni qayta ishlash metodologiyasi
-   - defer, panic va recover mexanizmlari
-
-6️⃣ Testlash va sifat ta'minoti
-   - Go texnologiyasida yozilgan unit testlar
-   - Benchmark test'lar orqali samaradorlikni baholash
-   - Table-driven test usuli
-
-7️⃣ Paketlar va modullar tizimi
-   - Go module tizimi va go.mod fayli
-   - Paket strukturasi va importlar
-   - Eksport (bosh harf) va shaxsiy (kichik harf) identifikatorlar
-
-8️⃣ Ilg'or mavzular
-   - Reflection mexanizmi bilan ishlash
-   - CGO - C kodini Go bilan integratsiyalash
-   - Context paketi va uni qo'llash usullari
-
-9️⃣ Amaliy loyihalar
-   - CLI (buyruq qatori) dasturlari yaratish
-   - Web xizmatlar va HTTP server (net/http)
-   - Ma'lumotlar bazasi bilan ishlash (SQL va NoSQL)
-
-Boshlash uchun eng yaxshi resurs: https://go.dev/learn/`
-}
-
-// GetUsefulText Go dasturlash tili bo'yicha foydali resurslarni qaytaradi
-// Bu funksiya Go o'rganish uchun eng yaxshi manbalar to'plamini taqdim etadi
-func (h *CommandHandler) GetUsefulText() string {
-	return `Go dasturlash tili bo'yicha eng foydali manbalar:
-
-📚 Asosiy manbalar:
-- Rasmiy veb-sayt: https://go.dev - barcha rasmiy hujjatlar va yangiliklar
-- Tour of Go: https://tour.golang.org/ - interaktiv o'rganish qo'llanmasi
-- Go by Example: https://gobyexample.com/ - misollarda Go'ni o'rganish
-- Effektiv Go: https://go.dev/doc/effective_go - samarali kod yozish bo'yicha tavsiyalar
-- Standard Library: https://pkg.go.dev/std - standart kutubxonalar hujjatlari
-- Go Playground: https://play.golang.org/ - brauzerda kod yozish va sinab ko'rish
-- Go hamjamiyati blogi: https://go.dev/blog/ - yangiliklar va chuqurlashtirilgan maqolalar
-
-🔍 Qo'shimcha foydali manbalar:
-- Awesome Go: https://github.com/avelino/awesome-go - Go kutubxonalari va vositalar to'plami
-- Go Design Patterns: https://github.com/tmrts/go-patterns - Go uchun dizayn patternlar
-- Go Forums: https://forum.golangbridge.org/ - savol-javoblar va muhokamalar
-
-📖 Tavsiya etiladigan kitoblar:
-- "The Go Programming Language" - Alan Donovan va Brian Kernighan
-- "Go in Action" - William Kennedy
-- "Concurrency in Go" - Katherine Cox-Buday
-
-🎓 Video darslar:
-- Golang bo'yicha o'zbek tilidagi darslar: https://youtube.com/playlist?list=PLLIX7niqDict7oqNQesQQT9b3GlqF7JAj`
-}
-
-// GetLatestText eng yangi Go versiyasi haqida ma'lumot qaytaradi
-// Bu funksiya Go'ning eng so'nggi versiyasidagi asosiy yangiliklar haqida ma'lumot beradi
-func (h *CommandHandler) GetLatestText() string {
-	// Haqiqiy dasturda bu ma'lumotlar Go rasmiy saytidan yoki API orqali olinishi mumkin
-	return `Go 1.22.1 (2024-yil 5-mart) versiyasidagi asosiy yangiliklar:
-
-🔧 Muhim xatolar tuzatildi:
-- net/http paketidagi xavfsizlik bilan bog'liq muammolar bartaraf etildi
-- crypto paketlaridagi xatolar tuzatildi
-
-🚀 Yaxshilanishlar:
-- Kompilyator ishlash tezligi oshirildi
-- Runtime samaradorligi yaxshilandi
-- Xotiradan foydalanish optimallashtirildi
-
-🔒 Xavfsizlik yangiliklari:
-- Standart kutubxonalardagi potensial zaifliklar bartaraf etildi
-
-Batafsil ma'lumot: https://go.dev/doc/devel/release#go1.22.1`
-}
-
-// GetVersionText ko'rsatilgan Go versiyasi haqida batafsil ma'lumot qaytaradi
-// Bu funksiya foydalanuvchi so'ragan versiya haqida to'liq ma'lumotni beradi
-func (h *CommandHandler) GetVersionText(version string) string {
-	// Agar versiya ko'rsatilmagan bo'lsa, foydalanuvchiga ko'rsatma berish
-	if version == "" {
-		return "Iltimos, ma'lumot olmoqchi bo'lgan versiya raqamini kiriting. Masalan: /version 1.22.0"
-	}
-
-	// Haqiqiy dasturda bu ma'lumotlar ma'lumotlar bazasi yoki API orqali olinishi kerak
-	versions := map[string]string{
-		"1.22.1": "Go 1.22.1 (2024-yil 5-mart):\n\n" +
-			"🔧 Xatoliklar tuzatishlari:\n" +
-			"- net/http paketidagi HTTP sarlavhalarni qayta ishlashdagi xatolar tuzatildi\n" +
-			"- crypto/tls paketidagi sertifikat tekshirishda optimizatsiyalar qilindi\n" +
-			"- reflect paketidagi xotira sizishlar bartaraf etildi\n\n" +
-			"🚀 Yaxshilanishlar:\n" +
-			"- Paralel garbage collection algoritmi takomillashtirildi\n" +
-			"- GOEXPERIMENT=rangefunc bayroq orqali yangi range funksiyalarini sinash imkoniyati qo'shildi",
-
-		"1.22.0": "Go 1.22.0 (2024-yil 6-fevral):\n\n" +
-			"🆕 Yangi imkoniyatlar:\n" +
-			"- Butun sonlar ustida iteratsiya qilish uchun yangi range sintaksisi (range 10)\n" +
-			"- HTTP router pattern matching qo'llab-quvvatlash bilan yaxshilandi\n" +
-			"- For loop'larda xatoliklarni qayta ishlash takomillashtirildi\n\n" +
-			"🔄 Muhim o'zgarishlar:\n" +
-			"- Orqaga moslik yanada kuchaytirildi\n" +
-			"- Xatolik xabarlari tushunarliroq bo'ldi\n" +
-			"- Paket importi optimallashtirildi",
-
-		"1.21.0": "Go 1.21.0 (2023-yil 8-avgust):\n\n" +
-			"🆕 Yangi imkoniyatlar:\n" +
-			"- min() va max() o'rnatilgan funksiyalar qo'shildi\n" +
-			"- Loop o'zgaruvchilari semantikasi o'zgartirildi (har bir iteratsiya uchun yangi o'zgaruvchi)\n" +
-			"- slog paketi orqali strukturaviy log yozish imkoniyati qo'shildi\n\n" +
-			"🔧 Yaxshilanishlar:\n" +
-			"- Forward compatible method chaqirishlari\n" +
-			"- PGO (Profile-guided optimization) orqali dastur ishlash tezligini oshirish",
-	}
-
-	// Versiya topilganda, uni batafsil ma'lumot bilan qaytarish
-	if info, ok := versions[version]; ok {
-		return info + "\n\nRasmiy hujjatlar va batafsilroq ma'lumot: https://go.dev/doc/devel/release#go" + strings.ReplaceAll(version, ".", "")
-	}
-
-	// Agar versiya topilmasa, xato xabarini qaytarish
-	return fmt.Sprintf("Kechirasiz, %s versiyasi haqida ma'lumot bazamizda topilmadi. Mavjud versiyalar: 1.21.0, 1.22.0, 1.22.1", version)
-}
-
-// GetWarnText ko'rsatilgan foydalanuvchiga ogohlantirish xabari qaytaradi
-// Bu funksiya guruh qoidalarini buzgan foydalanuvchilarni ogohlantirish uchun ishlatiladi
-func (h *CommandHandler) GetWarnText(username string) string {
-	// Agar foydalanuvchi nomi mavjud bo'lmasa, umumiy murojaat ishlatish
-	if username == "" {
-		username = "Foydalanuvchi"
+	if handler := h.GetCommandHandler(update.Message.Command()); handler != nil {
+		handler(context.Background(), bot, update.Message, h.logger)
+		return
 	}
-	// Ogohlantirish xabarini formatlash va qaytarish
-	return fmt.Sprintf("⚠️ Diqqat @%s! Iltimos, guruh qoidalariga rioya qiling va mavzudan chetlashmang. Qoidalar bilan tanishish uchun /rules buyrug'ini yuboring.", username)
+	h.UnknownCommand(bot, update.Message, h.logger)
 }
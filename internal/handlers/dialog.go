@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/conversation"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// defaultDialogTimeout muddati ko'rsatilmagan suhbatlarda javob kutish muddati
+const defaultDialogTimeout = 10 * time.Minute
+
+// DialogValidator foydalanuvchi javobini tekshiradi va saqlanadigan qiymatni qaytaradi
+// Javob to'g'ri bo'lmasa problem foydalanuvchiga ko'rsatiladigan tayyor matn bo'ladi va qadam takrorlanadi
+type DialogValidator func(t *translator, message *tgbotapi.Message) (value string, problem string)
+
+// DialogFunction suhbatning barcha qadamlari to'ldirilganda chaqiriladi
+// message oxirgi javob xabari, data qadam nomlari bo'yicha yig'ilgan javoblar
+type DialogFunction func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, data map[string]string, log *logger.Logger)
+
+// DialogStep suhbatdagi bitta savol
+type DialogStep struct {
+	Name     string          // Javob data ichida shu kalit bilan saqlanadi
+	Prompt   string          // Qadam boshlanganda yuboriladigan matn kaliti
+	Validate DialogValidator // Javobni tekshiruvchi (nil bo'lsa bo'sh bo'lmagan matn qabul qilinadi)
+}
+
+// DialogSpec bir nechta qadamdan iborat suhbat tavsifi
+type DialogSpec struct {
+	Name    string         // Suhbat nomi, omborda shu nom bilan saqlanadi
+	Steps   []DialogStep   // Qadamlar tartib bilan so'raladi
+	Timeout time.Duration  // Har bir javobni kutish muddati (0 - defaultDialogTimeout)
+	Done    DialogFunction // Suhbat yakunlanganda chaqiriladi
+}
+
+// RegisterDialog suhbat turini ro'yxatdan o'tkazadi
+func (h *CommandHandler) RegisterDialog(spec DialogSpec) {
+	if h.dialogs == nil {
+		h.dialogs = make(map[string]DialogSpec)
+	}
+	if spec.Timeout <= 0 {
+		spec.Timeout = defaultDialogTimeout
+	}
+	h.dialogs[spec.Name] = spec
+}
+
+// dialogKey xabar yuborgan foydalanuvchining shu chatdagi suhbati kaliti
+func dialogKey(message *tgbotapi.Message) conversation.Key {
+	return conversation.Key{ChatID: message.Chat.ID, UserID: message.From.ID}
+}
+
+// StartDialog xabar muallifi bilan suhbatni boshlaydi va birinchi savolni yuboradi
+// Foydalanuvchining shu chatdagi avvalgi suhbati bo'lsa u bekor qilinadi
+func (h *CommandHandler) StartDialog(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, name string, data map[string]string, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	spec, ok := h.dialogs[name]
+	if !ok || len(spec.Steps) == 0 || message.From == nil {
+		log.Errorf("%q suhbati ro'yxatdan o'tmagan yoki qadamlari yo'q", name)
+		return
+	}
+
+	if data == nil {
+		data = make(map[string]string)
+	}
+	state := conversation.State{Flow: name, Data: data, Expires: time.Now().Add(spec.Timeout)}
+	if err := h.conversations.Put(dialogKey(message), state); err != nil {
+		log.Errorf("Suhbatni saqlashda xatolik: %v", err)
+		t.reply(bot, message, "dialog_failed", content.Vars{}, log)
+		return
+	}
+	h.sendDialogPrompt(bot, t, message, spec.Steps[0], log)
+}
+
+// sendDialogPrompt qadam savolini foydalanuvchining xabariga javob sifatida yuboradi
+// Guruhlarda javob maydoni faqat shu foydalanuvchiga ochiladi, shunda uning javobi maxfiylik rejimida ham botga yetib keladi
+func (h *CommandHandler) sendDialogPrompt(bot *tgbotapi.BotAPI, t *translator, message *tgbotapi.Message, step DialogStep, log *logger.Logger) {
+	vars := contentVars(bot, message.Chat, message.From)
+	text := joinMessages(t.message(step.Prompt, vars), t.message("dialog_cancel_hint", vars))
+	msg := tgbotapi.NewMessage(message.Chat.ID, text.Text)
+	msg.ParseMode = text.ParseMode
+	msg.ReplyToMessageID = message.MessageID
+	if !message.Chat.IsPrivate() {
+		msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true}
+	}
+	if _, err := bot.Send(msg); err != nil {
+		log.Error("Suhbat savolini yuborishda xatolik:", err)
+	}
+}
+
+// textAnswer bo'sh bo'lmagan matnli javobni qabul qiluvchi standart tekshiruvchi
+func textAnswer(t *translator, message *tgbotapi.Message) (string, string) {
+	text := strings.TrimSpace(message.Text)
+	if text == "" {
+		return "", t.text("dialog_text_only", nil)
+	}
+	return text, ""
+}
+
+// HandleMessage oddiy xabarni foydalanuvchining shu chatdagi faol suhbatiga uzatadi
+// Xabar suhbatga tegishli bo'lsa true qaytariladi, aks holda xabar keyingi qayta ishlovchilarga o'tishi mumkin
+func (h *CommandHandler) HandleMessage(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) bool {
+	if message.From == nil || message.From.IsBot {
+		return false
+	}
+	key := dialogKey(message)
+	state, ok, err := h.conversations.Get(key)
+	if err != nil {
+		log.Warnf("Suhbat holatini o'qishda xatolik: %v", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	t := h.translator(message.Chat, message.From)
+	ctx = withTranslator(ctx, t)
+
+	spec, known := h.dialogs[state.Flow]
+	if !known || state.Step < 0 || state.Step >= len(spec.Steps) {
+		// Suhbat turi yoki qadamlari bot yangilanganda o'zgargan
+		log.Warnf("%d chatidagi %q suhbati endi mavjud emas, bekor qilindi", message.Chat.ID, state.Flow)
+		if err := h.conversations.Delete(key); err != nil {
+			log.Warnf("Suhbatni o'chirishda xatolik: %v", err)
+		}
+		return false
+	}
+	if state.Expired(time.Now()) {
+		if err := h.conversations.Delete(key); err != nil {
+			log.Warnf("Suhbatni o'chirishda xatolik: %v", err)
+		}
+		t.reply(bot, message, "dialog_expired", contentVars(bot, message.Chat, message.From), log)
+		return true
+	}
+
+	step := spec.Steps[state.Step]
+	validate := step.Validate
+	if validate == nil {
+		validate = textAnswer
+	}
+	value, problem := validate(t, message)
+	if problem != "" {
+		state.Expires = time.Now().Add(spec.Timeout)
+		if err := h.conversations.Put(key, state); err != nil {
+			log.Warnf("Suhbatni saqlashda xatolik: %v", err)
+		}
+		sendMessage(bot, message.Chat.ID, message.MessageID, content.Message{Text: problem}, log)
+		return true
+	}
+
+	state.Data[step.Name] = value
+	state.Step++
+	if state.Step < len(spec.Steps) {
+		state.Expires = time.Now().Add(spec.Timeout)
+		if err := h.conversations.Put(key, state); err != nil {
+			log.Errorf("Suhbatni saqlashda xatolik: %v", err)
+			t.reply(bot, message, "dialog_failed", content.Vars{}, log)
+			return true
+		}
+		h.sendDialogPrompt(bot, t, message, spec.Steps[state.Step], log)
+		return true
+	}
+
+	if err := h.conversations.Delete(key); err != nil {
+		log.Warnf("Suhbatni o'chirishda xatolik: %v", err)
+	}
+	log.Debugf("%d foydalanuvchi %d chatida %q suhbatini yakunladi", message.From.ID, message.Chat.ID, state.Flow)
+	spec.Done(ctx, bot, message, state.Data, log)
+	return true
+}
+
+// handleCancel foydalanuvchining shu chatdagi faol suhbatini bekor qiladi
+func (h *CommandHandler) handleCancel(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	if message.From == nil {
+		return
+	}
+	key := dialogKey(message)
+	_, ok, err := h.conversations.Get(key)
+	if err == nil && ok {
+		err = h.conversations.Delete(key)
+	}
+	switch {
+	case err != nil:
+		log.Errorf("Suhbatni bekor qilishda xatolik: %v", err)
+		t.reply(bot, message, "dialog_failed", content.Vars{}, log)
+	case !ok:
+		t.reply(bot, message, "dialog_none", content.Vars{}, log)
+	default:
+		t.reply(bot, message, "dialog_cancelled", content.Vars{}, log)
+	}
+}
+
+// RunDialogTimeouts muddati o'tgan suhbatlarni har daqiqada bekor qiladi va egalariga xabar beradi
+func (h *CommandHandler) RunDialogTimeouts(ctx context.Context, bot *tgbotapi.BotAPI) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, err := h.conversations.Expire(now)
+			if err != nil {
+				h.logger.Warnf("Muddati o'tgan suhbatlarni o'chirishda xatolik: %v", err)
+				continue
+			}
+			for key := range expired {
+				chat := &tgbotapi.Chat{ID: key.ChatID}
+				if saved, err := h.store.GetChat(key.ChatID); err == nil {
+					chat.Title = saved.Title
+					chat.Type = saved.Type
+				}
+				user := &tgbotapi.User{ID: key.UserID}
+				if saved, err := h.store.GetUser(key.UserID); err == nil {
+					user.FirstName = saved.FirstName
+					user.LastName = saved.LastName
+					user.UserName = saved.UserName
+				}
+				t := h.translator(chat, user)
+				sendMessage(bot, chat.ID, 0, t.message("dialog_expired", contentVars(bot, chat, user)), h.logger)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/docs"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Inline rejimdagi javoblar chegaralari
+const (
+	inlineResultsLimit = 10  // Bitta so'rovga qaytariladigan natijalar soni
+	inlineCacheTime    = 300 // Telegram natijalarni keshlash muddati (soniyalarda)
+)
+
+// docAlsoLimit bir xil nomli belgilar ro'yxatida ko'rsatiladigan paketlar soni
+const docAlsoLimit = 4
+
+// handleDoc standart kutubxona belgisi haqidagi hujjatni yuboradi
+// So'rov aniq topilmasa eng yaqin belgi "balki ... nazarda tutilgandir" izohi bilan ko'rsatiladi
+func (h *CommandHandler) handleDoc(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	query := strings.TrimSpace(message.CommandArguments())
+	if query == "" {
+		t.reply(bot, message, "doc_usage", content.Vars{}, log)
+		return
+	}
+
+	found, err := h.docs.Lookup(query)
+	if errors.Is(err, docs.ErrNotReady) {
+		t.reply(bot, message, "doc_not_ready", content.Vars{}, log)
+		return
+	}
+
+	note := ""
+	if len(found) == 0 {
+		closest, ok := h.docs.Closest(query)
+		if !ok {
+			t.reply(bot, message, "doc_not_found", content.Vars{Args: map[string]string{"query": query}}, log)
+			return
+		}
+		found = []docs.Symbol{closest}
+		note = t.text("doc_did_you_mean", map[string]string{"name": closest.FullName()})
+	}
+
+	sendMessage(bot, message.Chat.ID, message.MessageID, docMessage(t, found, note), log)
+}
+
+// docMessage belgi hujjatini foydalanuvchi tilidagi shablon bo'yicha tayyorlaydi
+// Bir xil nomli belgilar bir nechta paketda bo'lsa, birinchisi ko'rsatiladi, qolganlari esa ro'yxat sifatida qo'shiladi
+func docMessage(t *translator, found []docs.Symbol, note string) content.Message {
+	symbol := found[0]
+
+	also := ""
+	if len(found) > 1 {
+		var names []string
+		for _, other := range found[1:min(len(found), docAlsoLimit+1)] {
+			names = append(names, other.ImportPath+"."+other.Name)
+		}
+		also = t.text("doc_also", map[string]string{"names": strings.Join(names, ", ")})
+	}
+
+	name := symbol.FullName()
+	if symbol.Package != symbol.ImportPath && symbol.Name != "" {
+		name = symbol.ImportPath + "." + symbol.Name
+	}
+
+	return t.message("doc_symbol", content.Vars{Args: map[string]string{
+		"note":      note,
+		"name":      name,
+		"kind":      string(symbol.Kind),
+		"signature": symbol.Signature,
+		"doc":       symbol.Doc,
+		"also":      also,
+		"link":      symbol.URL(),
+	}})
+}
+
+// HandleInlineQuery inline rejimdagi so'rovga (@bot strings.Cut) mos belgilar ro'yxati bilan javob beradi
+func (h *CommandHandler) HandleInlineQuery(ctx context.Context, bot *tgbotapi.BotAPI, query *tgbotapi.InlineQuery, log *logger.Logger) {
+	if h.docs == nil {
+		return
+	}
+	t := h.translator(nil, query.From)
+
+	found, err := h.docs.Search(query.Query, inlineResultsLimit)
+	if err != nil {
+		log.Debugf("Inline so'rovga javob tayyorlab bo'lmadi: %v", err)
+	}
+
+	results := make([]interface{}, 0, len(found))
+	for i, symbol := range found {
+		text := docMessage(t, []docs.Symbol{symbol}, "")
+		article := tgbotapi.NewInlineQueryResultArticle(strconv.Itoa(i), symbol.FullName(), text.Text)
+		article.InputMessageContent = tgbotapi.InputTextMessageContent{
+			Text:                  text.Text,
+			ParseMode:             text.ParseMode,
+			DisableWebPagePreview: true,
+		}
+		article.Description = firstLine(symbol.Signature)
+		results = append(results, article)
+	}
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheTime,
+	}
+	if _, err := bot.Request(answer); err != nil {
+		log.Errorf("Inline so'rovga javob berishda xatolik: %v", err)
+	}
+}
+
+// firstLine matnning birinchi qatorini qaytaradi
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/flood"
+	"tg-bot/internal/modlog"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// slowModeSetting chatdagi slow mode oralig'i saqlanadigan sozlama (bo'sh - o'chirilgan)
+const slowModeSetting = "slowmode"
+
+// noticeLifetime flood va slow mode ogohlantirishlari chatda turadigan vaqt
+const noticeLifetime = 15 * time.Second
+
+// slowMode chatda belgilangan slow mode oralig'ini qaytaradi (0 - o'chirilgan)
+func (h *CommandHandler) slowMode(chatID int64) time.Duration {
+	value, err := h.store.GetSetting(chatID, slowModeSetting)
+	if err != nil || value == "" {
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// LimitMessage guruhdagi xabarni flood va slow mode cheklovlari bo'yicha tekshiradi
+// Xabar o'chirilgan bo'lsa true qaytariladi va uni boshqa qayta ishlash kerak emas
+func (h *CommandHandler) LimitMessage(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) bool {
+	if message.From == nil || message.From.IsBot {
+		return false
+	}
+	if message.Chat.IsPrivate() || message.Chat.IsChannel() || message.SenderChat != nil || message.IsAutomaticForward {
+		return false
+	}
+
+	decision := h.flood.Hit(message.Chat.ID, message.From.ID, h.slowMode(message.Chat.ID), time.Now())
+	if decision.Action == flood.Allow {
+		return false
+	}
+	// Adminlar cheklanmaydi, ularni tekshirish faqat cheklovdan oshgan xabarlar uchun so'raladi
+	// Tekshirib bo'lmasa cheklov qo'llanilmaydi, adminni xato bilan ovozsiz qilmaslik kerak
+	admin, err := isChatAdmin(bot, message.Chat.ID, message.From.ID)
+	if err != nil {
+		log.Warnf("Flood nazorati: admin huquqlarini tekshirishda xatolik, xabar o'tkazib yuborildi: %v", err)
+		return false
+	}
+	if admin {
+		return false
+	}
+
+	deleteMessage(bot, message.Chat.ID, message.MessageID, log)
+	t := h.translator(message.Chat, message.From)
+	vars := contentVars(bot, message.Chat, message.From)
+
+	switch decision.Action {
+	case flood.Slow:
+		if !decision.Repeat {
+			wait := (decision.Wait + time.Second - 1).Truncate(time.Second)
+			vars.Args = map[string]string{
+				"interval": t.duration(h.slowMode(message.Chat.ID)),
+				"wait":     t.duration(wait),
+			}
+			sendNotice(bot, message.Chat.ID, t.message("slowmode_wait", vars), log)
+		}
+	case flood.Delete:
+		if !decision.Repeat {
+			log.Infof("Flood nazorati: %d foydalanuvchi %d guruhda chegaradan oshdi", message.From.ID, message.Chat.ID)
+			sendNotice(bot, message.Chat.ID, t.message("flood_slow_down", vars), log)
+		}
+	case flood.Mute:
+		h.muteFlooder(bot, t, message, log)
+	}
+	return true
+}
+
+// muteFlooder flood qilayotgan foydalanuvchini vaqtincha ovozsiz qiladi va adminlarga xabar beradi
+func (h *CommandHandler) muteFlooder(bot *tgbotapi.BotAPI, t *translator, message *tgbotapi.Message, log *logger.Logger) {
+	duration := h.config.FloodSettings().MuteDuration
+	vars := contentVars(bot, message.Chat, message.From)
+	vars.Args = map[string]string{"duration": t.duration(duration)}
+
+	key, action := "flood_muted", "flood_action_muted"
+	if err := muteMember(bot, message.Chat.ID, message.From.ID, time.Now().Add(duration)); err != nil {
+		log.Errorf("Flood nazorati: foydalanuvchini ovozsiz qilishda xatolik: %v", err)
+		key, action = "flood_mute_failed", "flood_action_mute_failed"
+	} else {
+		log.Infof("Flood nazorati: %d foydalanuvchi %d guruhda %s ovozsiz qilindi", message.From.ID, message.Chat.ID, duration)
+		h.recordModeration(bot, message.Chat, nil, message.From, modlog.Entry{Action: modlog.Mute, Source: modlog.SourceFlood, Duration: duration}, log)
+	}
+	sendMessage(bot, message.Chat.ID, 0, t.message(key, vars), log)
+
+	auditChat := h.config.AntispamSettings().AuditChat
+	if auditChat == 0 {
+		return
+	}
+	at := h.translator(&tgbotapi.Chat{ID: auditChat}, nil)
+	vars.Args = map[string]string{
+		"action":  at.text(action, map[string]string{"duration": at.duration(duration)}),
+		"user_id": strconv.FormatInt(message.From.ID, 10),
+		"chat_id": strconv.FormatInt(message.Chat.ID, 10),
+	}
+	sendMessage(bot, auditChat, 0, at.message("flood_audit", vars), log)
+}
+
+// sendNotice chatga qisqa ogohlantirish yuboradi va uni noticeLifetime dan keyin o'chiradi
+func sendNotice(bot *tgbotapi.BotAPI, chatID int64, text content.Message, log *logger.Logger) {
+	msg := tgbotapi.NewMessage(chatID, text.Text)
+	msg.ParseMode = text.ParseMode
+	sent, err := bot.Send(msg)
+	if err != nil {
+		log.Error("Xabar yuborishda xatolik yuz berdi:", err)
+		return
+	}
+	time.AfterFunc(noticeLifetime, func() {
+		deleteMessage(bot, chatID, sent.MessageID, log)
+	})
+}
+
+// handleSlowMode guruhda bot tomonidan qo'llaniladigan slow mode oralig'ini belgilaydi yoki o'chiradi
+func (h *CommandHandler) handleSlowMode(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	chatID := message.Chat.ID
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+
+	if arg == "" {
+		status := t.text("slowmode_status_off", nil)
+		if interval := h.slowMode(chatID); interval > 0 {
+			status = t.text("slowmode_status_on", map[string]string{"interval": t.duration(interval)})
+		}
+		t.reply(bot, message, "slowmode_usage", content.Vars{Args: map[string]string{"status": status}}, log)
+		return
+	}
+
+	if arg == "off" || arg == "0" {
+		if err := h.store.SetSetting(chatID, slowModeSetting, ""); err != nil {
+			log.Errorf("Slow mode sozlamasini saqlashda xatolik: %v", err)
+			t.reply(bot, message, "slowmode_failed", content.Vars{}, log)
+			return
+		}
+		log.Infof("%d chatida slow mode o'chirildi", chatID)
+		t.reply(bot, message, "slowmode_off", content.Vars{}, log)
+		return
+	}
+
+	interval, ok := parseSlowMode(arg)
+	if !ok {
+		t.reply(bot, message, "slowmode_bad_interval", content.Vars{Args: map[string]string{"max": t.duration(flood.MaxInterval)}}, log)
+		return
+	}
+	if err := h.store.SetSetting(chatID, slowModeSetting, interval.String()); err != nil {
+		log.Errorf("Slow mode sozlamasini saqlashda xatolik: %v", err)
+		t.reply(bot, message, "slowmode_failed", content.Vars{}, log)
+		return
+	}
+
+	log.Infof("%d chatida slow mode %s ga belgilandi", chatID, interval)
+	t.reply(bot, message, "slowmode_set", content.Vars{Args: map[string]string{"interval": t.duration(interval)}}, log)
+}
+
+// parseSlowMode "30s", "5m" yoki soniyalardagi son ko'rinishidagi oraliqni o'qiydi
+func parseSlowMode(arg string) (time.Duration, bool) {
+	interval, err := time.ParseDuration(arg)
+	if err != nil {
+		seconds, err := strconv.Atoi(arg)
+		if err != nil {
+			return 0, false
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+	interval = interval.Truncate(time.Second)
+	if interval < time.Second || interval > flood.MaxInterval {
+		return 0, false
+	}
+	return interval, true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/i18n"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// localeSetting til tanlovi saqlanadigan sozlama kaliti
+// Foydalanuvchi tanlovi uning shaxsiy chati ID si (foydalanuvchi ID si bilan bir xil), guruh tanlovi esa guruh ID si ostida saqlanadi
+const localeSetting = "locale"
+
+// langCallback til tanlash tugmalari nomi, ma'lumot ko'rinishi "lang:<til kodi>"
+const langCallback = "lang"
+
+// savedLocale ombordan saqlangan til tanlovini o'qiydi
+func (h *CommandHandler) savedLocale(id int64) (i18n.Locale, bool) {
+	value, err := h.store.GetSetting(id, localeSetting)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			h.logger.Debugf("%d uchun til sozlamasini o'qishda xatolik: %v", id, err)
+		}
+		return "", false
+	}
+	return i18n.Parse(value)
+}
+
+// locale javob qaysi tilda yuborilishini aniqlaydi
+// Tartib: foydalanuvchining saqlangan tanlovi, Telegram'dagi til kodi, guruhning standart tili, asosiy til
+func (h *CommandHandler) locale(chat *tgbotapi.Chat, user *tgbotapi.User) i18n.Locale {
+	if user != nil {
+		if locale, ok := h.savedLocale(user.ID); ok {
+			return locale
+		}
+		if locale, ok := i18n.Parse(user.LanguageCode); ok {
+			return locale
+		}
+	}
+	if chat != nil {
+		if locale, ok := h.savedLocale(chat.ID); ok {
+			return locale
+		}
+	}
+	return i18n.Default
+}
+
+// translator chat va foydalanuvchi uchun tanlangan tildagi translatorni yaratadi
+func (h *CommandHandler) translator(chat *tgbotapi.Chat, user *tgbotapi.User) *translator {
+	return h.translatorFor(h.locale(chat, user))
+}
+
+// translatorFor ko'rsatilgan til uchun translator yaratadi
+func (h *CommandHandler) translatorFor(locale i18n.Locale) *translator {
+	return &translator{texts: h.texts, locale: locale, log: h.logger}
+}
+
+// languageKeyboard barcha tillar uchun tanlash tugmalarini yasaydi
+func (h *CommandHandler) languageKeyboard() tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := 0; i < len(i18n.Supported); i += 2 {
+		var row []tgbotapi.InlineKeyboardButton
+		for _, locale := range i18n.Supported[i:min(i+2, len(i18n.Supported))] {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(locale.Name(), h.CallbackData(langCallback, string(locale))))
+		}
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleLang til tanlash tugmalarini yuboradi
+// Shaxsiy chatda foydalanuvchining o'z tili, guruhda esa guruhning standart tili tanlanadi (faqat adminlar uchun)
+func (h *CommandHandler) handleLang(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	key := "lang_choose"
+	if !message.Chat.IsPrivate() {
+		if !requireGroupAdmin(ctx, bot, message, log) {
+			return
+		}
+		key = "lang_choose_chat"
+	}
+
+	text := t.message(key, contentVars(bot, message.Chat, message.From))
+	msg := tgbotapi.NewMessage(message.Chat.ID, text.Text)
+	msg.ParseMode = text.ParseMode
+	msg.ReplyToMessageID = message.MessageID
+	msg.ReplyMarkup = h.languageKeyboard()
+	if _, err := bot.Send(msg); err != nil {
+		log.Error("Xabar yuborishda xatolik yuz berdi:", err)
+	}
+}
+
+// handleLangCallback tanlangan tilni saqlaydi va xabarni yangi tilda tasdiq bilan almashtiradi
+func (h *CommandHandler) handleLangCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, args []string, log *logger.Logger) CallbackResult {
+	if len(args) != 1 || callback.Message == nil || callback.From == nil {
+		return CallbackResult{}
+	}
+	locale, ok := i18n.Parse(args[0])
+	if !ok {
+		return CallbackResult{}
+	}
+	chat := callback.Message.Chat
+
+	// Guruh tilini faqat adminlar o'zgartira oladi
+	target := callback.From.ID
+	if !chat.IsPrivate() {
+		if admin, err := isChatAdmin(bot, chat.ID, callback.From.ID); err != nil || !admin {
+			return callbackAlert(translatorFrom(ctx).text("lang_admins_only", nil))
+		}
+		target = chat.ID
+	}
+
+	if err := h.store.SetSetting(target, localeSetting, string(locale)); err != nil {
+		log.Errorf("Til sozlamasini saqlashda xatolik: %v", err)
+		return CallbackResult{}
+	}
+	log.Infof("%d uchun til o'zgartirildi: %s", target, locale)
+
+	text := h.translatorFor(locale).message("lang_changed", content.Vars{Args: map[string]string{"language": locale.Name()}})
+	return CallbackResult{Edit: &text}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Middleware buyruq funksiyasini o'rab, unga umumiy xatti-harakat qo'shadi
+// Masalan, xatoliklarni ushlash, log yozish yoki huquqlarni tekshirish
+type Middleware func(next CommandFunction) CommandFunction
+
+// Chain middleware'larni buyruq funksiyasi atrofida ketma-ket o'raydi
+// Birinchi berilgan middleware eng tashqi qatlam bo'lib, birinchi ishga tushadi
+func Chain(fn CommandFunction, middlewares ...Middleware) CommandFunction {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		fn = middlewares[i](fn)
+	}
+	return fn
+}
+
+// Use barcha buyruqlarga qo'llaniladigan middleware'larni qo'shadi
+// Umumiy middleware'lar har doim buyruqning o'z middleware'laridan tashqarida ishlaydi
+func (h *CommandHandler) Use(middlewares ...Middleware) {
+	h.middlewares = append(h.middlewares, middlewares...)
+}
+
+// Recover buyruq ichidagi panic'ni ushlaydi va botning ishdan chiqishining oldini oladi
+func Recover() Middleware {
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Errorf("/%s buyrug'ini bajarishda panic: %v\n%s", message.Command(), r, debug.Stack())
+				}
+			}()
+			next(ctx, bot, message, log)
+		}
+	}
+}
+
+// Logging har bir buyruqni kim yuborgani va qancha vaqtda bajarilganini log qiladi
+func Logging() Middleware {
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			start := time.Now()
+			next(ctx, bot, message, log)
+
+			var userID int64
+			if message.From != nil {
+				userID = message.From.ID
+			}
+			log.Infof("Foydalanuvchi %d \"%s\" buyrug'ini yubordi (%d chat), bajarilish vaqti: %s",
+				userID, message.Command(), message.Chat.ID, time.Since(start))
+		}
+	}
+}
+
+// AdminOnly buyruqni faqat guruh adminlari bajarishiga ruxsat beradi
+// Shaxsiy chatlarda buyruq rad etiladi, chunki u yerda adminlik tushunchasi yo'q
+func AdminOnly() Middleware {
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			if !requireGroupAdmin(ctx, bot, message, log) {
+				return
+			}
+			next(ctx, bot, message, log)
+		}
+	}
+}
+
+// RequireRight buyruqni faqat kerakli huquqqa ega adminlar bajarishiga ruxsat beradi
+// Botning o'zida ham shu huquq bo'lishi tekshiriladi, shunda buyruq yarim yo'lda xatolik bilan to'xtamaydi
+func RequireRight(right adminRight) Middleware {
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			if !requireRight(ctx, bot, message, right, log) {
+				return
+			}
+			next(ctx, bot, message, log)
+		}
+	}
+}
+
+// OwnerOnly buyruqni faqat sozlamalarda ko'rsatilgan bot egalari bajarishiga ruxsat beradi
+// Ro'yxat bo'sh bo'lsa buyruqni hech kim bajara olmaydi
+func OwnerOnly(owners []int64) Middleware {
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			if message.From == nil || !slices.Contains(owners, message.From.ID) {
+				translatorFrom(ctx).reply(bot, message, "only_owners", content.Vars{}, log)
+				return
+			}
+			next(ctx, bot, message, log)
+		}
+	}
+}
+
+// GroupOnly buyruqni faqat guruh va superguruhlarda ishlashiga cheklaydi
+func GroupOnly() Middleware {
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			if !message.Chat.IsGroup() && !message.Chat.IsSuperGroup() {
+				translatorFrom(ctx).reply(bot, message, "only_groups", content.Vars{}, log)
+				return
+			}
+			next(ctx, bot, message, log)
+		}
+	}
+}
+
+// PrivateOnly buyruqni faqat bot bilan shaxsiy yozishmada ishlashiga cheklaydi
+func PrivateOnly() Middleware {
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			if !message.Chat.IsPrivate() {
+				translatorFrom(ctx).reply(bot, message, "only_private", content.Vars{}, log)
+				return
+			}
+			next(ctx, bot, message, log)
+		}
+	}
+}
+
+// RateLimit har bir foydalanuvchiga window davomida ko'pi bilan limit marta buyruq yuborishga ruxsat beradi
+// Cheklovdan oshgan foydalanuvchi bir marta ogohlantiriladi, keyingi buyruqlari esa jimgina e'tiborsiz qoldiriladi
+func RateLimit(limit int, window time.Duration) Middleware {
+	var (
+		mu     sync.Mutex
+		hits   = make(map[int64][]time.Time)
+		warned = make(map[int64]bool)
+		sweep  time.Time // Eskirgan hisoblagichlar oxirgi marta tozalangan vaqt
+	)
+
+	// prune oxirgi window davomida buyruq yubormagan foydalanuvchilarni unutadi
+	prune := func(now time.Time) {
+		if now.Sub(sweep) < window {
+			return
+		}
+		sweep = now
+		for userID, times := range hits {
+			if len(times) == 0 || now.Sub(times[len(times)-1]) >= window {
+				delete(hits, userID)
+				delete(warned, userID)
+			}
+		}
+	}
+
+	// allow foydalanuvchining oxirgi window ichidagi buyruqlarini sanaydi
+	allow := func(userID int64) (ok bool, warn bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		prune(now)
+		recent := hits[userID][:0]
+		for _, t := range hits[userID] {
+			if now.Sub(t) < window {
+				recent = append(recent, t)
+			}
+		}
+
+		if len(recent) >= limit {
+			hits[userID] = recent
+			warn = !warned[userID]
+			warned[userID] = true
+			return false, warn
+		}
+
+		hits[userID] = append(recent, now)
+		delete(warned, userID)
+		return true, false
+	}
+
+	return func(next CommandFunction) CommandFunction {
+		return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+			if message.From == nil {
+				next(ctx, bot, message, log)
+				return
+			}
+
+			ok, warn := allow(message.From.ID)
+			if !ok {
+				if warn {
+					translatorFrom(ctx).reply(bot, message, "rate_limited", content.Vars{}, log)
+				}
+				log.Debugf("%d foydalanuvchi buyruqlar chegarasidan oshdi", message.From.ID)
+				return
+			}
+			next(ctx, bot, message, log)
+		}
+	}
+}
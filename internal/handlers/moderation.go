@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/modlog"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// isChatAdmin foydalanuvchining guruhda admin yoki yaratuvchi ekanligini getChatMember orqali tekshiradi
+func isChatAdmin(bot *tgbotapi.BotAPI, chatID, userID int64) (bool, error) {
+	member, err := bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{
+			ChatID: chatID,
+			UserID: userID,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return member.IsAdministrator() || member.IsCreator(), nil
+}
+
+// muteMember foydalanuvchiga guruhda xabar yozishni ko'rsatilgan vaqtgacha taqiqlaydi
+// until nol qiymatli bo'lsa, cheklov muddatsiz qo'llaniladi
+func muteMember(bot *tgbotapi.BotAPI, chatID, userID int64, until time.Time) error {
+	config := tgbotapi.RestrictChatMemberConfig{
+		ChatMemberConfig: tgbotapi.ChatMemberConfig{
+			ChatID: chatID,
+			UserID: userID,
+		},
+		Permissions: &tgbotapi.ChatPermissions{},
+	}
+	if !until.IsZero() {
+		config.UntilDate = until.Unix()
+	}
+	_, err := bot.Request(config)
+	return err
+}
+
+// unmuteMember foydalanuvchidan cheklovlarni olib tashlaydi
+// Foydalanuvchiga guruhning standart ruxsatlari qaytariladi, ularni olib bo'lmasa oddiy yozish huquqlari beriladi
+func unmuteMember(bot *tgbotapi.BotAPI, chatID, userID int64) error {
+	permissions := &tgbotapi.ChatPermissions{
+		CanSendMessages:       true,
+		CanSendMediaMessages:  true,
+		CanSendPolls:          true,
+		CanSendOtherMessages:  true,
+		CanAddWebPagePreviews: true,
+	}
+	chat, err := bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: chatID}})
+	if err == nil && chat.Permissions != nil {
+		permissions = chat.Permissions
+	}
+
+	_, err = bot.Request(tgbotapi.RestrictChatMemberConfig{
+		ChatMemberConfig: tgbotapi.ChatMemberConfig{
+			ChatID: chatID,
+			UserID: userID,
+		},
+		Permissions: permissions,
+	})
+	return err
+}
+
+// kickMember foydalanuvchini guruhdan chiqarib yuboradi, lekin keyinroq qayta qo'shilishiga ruxsat beradi
+func kickMember(bot *tgbotapi.BotAPI, chatID, userID int64) error {
+	if err := banMember(bot, chatID, userID); err != nil {
+		return err
+	}
+	_, err := bot.Request(tgbotapi.UnbanChatMemberConfig{
+		ChatMemberConfig: tgbotapi.ChatMemberConfig{
+			ChatID: chatID,
+			UserID: userID,
+		},
+		OnlyIfBanned: true,
+	})
+	return err
+}
+
+// deleteMessage xabarni o'chiradi, xatolik bo'lsa faqat log qiladi
+func deleteMessage(bot *tgbotapi.BotAPI, chatID int64, messageID int, log *logger.Logger) {
+	if messageID == 0 {
+		return
+	}
+	if _, err := bot.Request(tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+		log.Debugf("%d xabarni o'chirib bo'lmadi: %v", messageID, err)
+	}
+}
+
+// banMember foydalanuvchini guruhdan butunlay chetlatadi
+func banMember(bot *tgbotapi.BotAPI, chatID, userID int64) error {
+	return banMemberUntil(bot, chatID, userID, time.Time{})
+}
+
+// banMemberUntil foydalanuvchini guruhdan ko'rsatilgan vaqtgacha chetlatadi
+// until nol qiymatli bo'lsa, foydalanuvchi muddatsiz chetlatiladi
+func banMemberUntil(bot *tgbotapi.BotAPI, chatID, userID int64, until time.Time) error {
+	config := tgbotapi.BanChatMemberConfig{
+		ChatMemberConfig: tgbotapi.ChatMemberConfig{
+			ChatID: chatID,
+			UserID: userID,
+		},
+	}
+	if !until.IsZero() {
+		config.UntilDate = until.Unix()
+	}
+	_, err := bot.Request(config)
+	return err
+}
+
+// requireGroupAdmin buyruq guruhda va admin tomonidan yuborilganini tekshiradi
+// Shartlar bajarilmasa foydalanuvchiga sababini yozadi va false qaytaradi
+func requireGroupAdmin(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) bool {
+	t := translatorFrom(ctx)
+	if message.From == nil || message.Chat.IsPrivate() || message.Chat.IsChannel() {
+		t.reply(bot, message, "only_groups", content.Vars{}, log)
+		return false
+	}
+
+	admin, err := isChatAdmin(bot, message.Chat.ID, message.From.ID)
+	if err != nil {
+		log.Errorf("Admin huquqlarini tekshirishda xatolik: %v", err)
+		t.reply(bot, message, "admin_check_failed", content.Vars{}, log)
+		return false
+	}
+	if !admin {
+		t.reply(bot, message, "only_admins", content.Vars{}, log)
+		return false
+	}
+	return true
+}
+
+// handleWarn javob berilgan xabar muallifiga ogohlantirish beradi
+// Ogohlantirishlar soni guruh siyosatidagi chegaralarga yetganda foydalanuvchi avtomatik ovozsiz qilinadi yoki chetlatiladi
+func (h *CommandHandler) handleWarn(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	if message.ReplyToMessage == nil || message.ReplyToMessage.From == nil {
+		t.reply(bot, message, "warn_usage", content.Vars{}, log)
+		return
+	}
+
+	target := message.ReplyToMessage.From
+	if target.IsBot {
+		t.reply(bot, message, "warn_no_bots", content.Vars{}, log)
+		return
+	}
+	admin, err := isChatAdmin(bot, message.Chat.ID, target.ID)
+	if err != nil {
+		log.Errorf("Admin huquqlarini tekshirishda xatolik: %v", err)
+		t.reply(bot, message, "admin_check_failed", content.Vars{}, log)
+		return
+	}
+	if admin {
+		t.reply(bot, message, "warn_no_admins", content.Vars{}, log)
+		return
+	}
+
+	reason := strings.TrimSpace(message.CommandArguments())
+	if reason == "" {
+		reason = t.text("warn_no_reason", nil)
+	}
+
+	parts, err := h.warnMember(bot, t, message.Chat, target, message.From, "", reason, log)
+	if err != nil {
+		log.Errorf("Ogohlantirishni saqlashda xatolik: %v", err)
+		t.reply(bot, message, "warn_save_failed", content.Vars{}, log)
+		return
+	}
+
+	// Ogohlantirish o'zi berilgan xabarga javob sifatida yuboriladi
+	sendMessage(bot, message.Chat.ID, message.ReplyToMessage.MessageID, joinMessages(parts...), log)
+}
+
+// warnMember foydalanuvchiga ogohlantirish yozadi va guruh siyosati bo'yicha uni ovozsiz qiladi yoki chetlatadi
+// issuer nil bo'lsa ogohlantirish source manbasidan avtomatik berilgan hisoblanadi, barcha choralar moderatsiya jurnaliga yoziladi.
+// Guruhga yuboriladigan xabar qismlari qaytariladi, ogohlantirishni saqlab bo'lmasa xatolik qaytadi
+func (h *CommandHandler) warnMember(bot *tgbotapi.BotAPI, t *translator, chat *tgbotapi.Chat, target, issuer *tgbotapi.User, source, reason string, log *logger.Logger) ([]content.Message, error) {
+	issuerID := bot.Self.ID
+	if issuer != nil {
+		issuerID = issuer.ID
+	}
+	_, err := h.store.AddWarning(storage.Warning{
+		ChatID:    chat.ID,
+		UserID:    target.ID,
+		IssuerID:  issuerID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := h.store.ListWarnings(chat.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	count := len(warnings)
+	log.Infof("%d foydalanuvchiga %d guruhda ogohlantirish berildi (%d-chi)", target.ID, chat.ID, count)
+	h.recordModeration(bot, chat, issuer, target, modlog.Entry{Action: modlog.Warn, Source: source, Reason: reason}, log)
+
+	policy := h.config.WarnPolicy(chat.ID)
+	args := map[string]string{"reason": reason, "count": strconv.Itoa(count)}
+	if policy.BanAfter > 0 {
+		args["limit"] = strconv.Itoa(policy.BanAfter)
+	}
+	parts := []content.Message{
+		t.message("warn", contentVars(bot, chat, target)),
+		t.message("warn_details", content.Vars{Args: args}),
+	}
+
+	switch {
+	case policy.BanAfter > 0 && count >= policy.BanAfter:
+		if err := banMember(bot, chat.ID, target.ID); err != nil {
+			log.Errorf("Foydalanuvchini chetlatishda xatolik: %v", err)
+			parts = append(parts, t.message("warn_ban_failed", content.Vars{}))
+		} else {
+			parts = append(parts, t.message("warn_banned", content.Vars{}))
+			h.recordModeration(bot, chat, nil, target, modlog.Entry{Action: modlog.Ban, Source: modlog.SourceWarns, Reason: reason}, log)
+			// Guruhga qaytarilgan foydalanuvchi birinchi ogohlantirishdayoq qayta chetlatilmasligi uchun hisob yangilanadi
+			h.clearWarnings(chat.ID, target.ID, log)
+		}
+	case policy.MuteAfter > 0 && count >= policy.MuteAfter:
+		until := time.Now().Add(policy.MuteDuration)
+		if err := muteMember(bot, chat.ID, target.ID, until); err != nil {
+			log.Errorf("Foydalanuvchini ovozsiz qilishda xatolik: %v", err)
+			parts = append(parts, t.message("warn_mute_failed", content.Vars{}))
+		} else {
+			h.recordModeration(bot, chat, nil, target, modlog.Entry{Action: modlog.Mute, Source: modlog.SourceWarns, Reason: reason, Duration: policy.MuteDuration}, log)
+			parts = append(parts, t.message("warn_muted", content.Vars{Args: map[string]string{
+				"until":    until.Format("2006-01-02 15:04"),
+				"duration": t.duration(policy.MuteDuration),
+			}}))
+		}
+	}
+	return parts, nil
+}
+
+// clearWarnings foydalanuvchining guruhdagi barcha ogohlantirishlarini o'chiradi
+func (h *CommandHandler) clearWarnings(chatID, userID int64, log *logger.Logger) {
+	warnings, err := h.store.ListWarnings(chatID, userID)
+	if err != nil {
+		log.Errorf("Ogohlantirishlarni o'qishda xatolik: %v", err)
+		return
+	}
+	for _, w := range warnings {
+		if err := h.store.DeleteWarning(w.ChatID, w.UserID, w.ID); err != nil {
+			log.Errorf("Ogohlantirishni o'chirishda xatolik: %v", err)
+			return
+		}
+	}
+}
+
+// handleWarns foydalanuvchining guruhdagi ogohlantirishlar tarixini ko'rsatadi
+// Xabarga javob sifatida yuborilsa o'sha xabar muallifi, aks holda buyruq yuboruvchining o'zi tekshiriladi
+func (h *CommandHandler) handleWarns(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	target := message.From
+	if target == nil {
+		return
+	}
+	if message.ReplyToMessage != nil && message.ReplyToMessage.From != nil {
+		target = message.ReplyToMessage.From
+	}
+
+	warnings, err := h.store.ListWarnings(message.Chat.ID, target.ID)
+	if err != nil {
+		log.Errorf("Ogohlantirishlarni o'qishda xatolik: %v", err)
+		t.reply(bot, message, "warns_read_failed", content.Vars{}, log)
+		return
+	}
+	vars := contentVars(bot, message.Chat, target)
+	if len(warnings) == 0 {
+		t.reply(bot, message, "warns_empty", vars, log)
+		return
+	}
+
+	vars.Args = map[string]string{"count": strconv.Itoa(len(warnings))}
+	header := t.message("warns_header", vars)
+	var items []string
+	for i, w := range warnings {
+		items = append(items, t.text("warns_item", map[string]string{
+			"n":      strconv.Itoa(i + 1),
+			"date":   w.CreatedAt.Format("2006-01-02 15:04"),
+			"reason": w.Reason,
+		}))
+	}
+	parts := []content.Message{header, {Text: strings.Join(items, "\n")}}
+
+	policy := h.config.WarnPolicy(message.Chat.ID)
+	var limits []string
+	if policy.MuteAfter > 0 {
+		limits = append(limits, t.text("warns_mute_policy", map[string]string{"n": strconv.Itoa(policy.MuteAfter)}))
+	}
+	if policy.BanAfter > 0 {
+		limits = append(limits, t.text("warns_ban_policy", map[string]string{"n": strconv.Itoa(policy.BanAfter)}))
+	}
+	if len(limits) > 0 {
+		parts = append(parts, content.Message{Text: strings.Join(limits, "\n")})
+	}
+
+	sendMessage(bot, message.Chat.ID, message.MessageID, joinMessages(parts...), log)
+}
+
+// handleUnwarn javob berilgan xabar muallifining ogohlantirishini olib tashlaydi
+// Argument sifatida tartib raqami berilsa o'sha ogohlantirish, aks holda eng oxirgisi o'chiriladi
+func (h *CommandHandler) handleUnwarn(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	if message.ReplyToMessage == nil || message.ReplyToMessage.From == nil {
+		t.reply(bot, message, "unwarn_usage", content.Vars{}, log)
+		return
+	}
+	target := message.ReplyToMessage.From
+
+	index := 0
+	if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			t.reply(bot, message, "unwarn_bad_number", content.Vars{}, log)
+			return
+		}
+		index = n
+	}
+
+	warnings, err := h.store.ListWarnings(message.Chat.ID, target.ID)
+	if err != nil {
+		log.Errorf("Ogohlantirishlarni o'qishda xatolik: %v", err)
+		t.reply(bot, message, "warns_read_failed", content.Vars{}, log)
+		return
+	}
+	vars := contentVars(bot, message.Chat, target)
+	if index == 0 {
+		index = len(warnings)
+	}
+	if index < 1 || index > len(warnings) {
+		t.reply(bot, message, "unwarn_not_found", vars, log)
+		return
+	}
+
+	removed := warnings[index-1]
+	if err := h.store.DeleteWarning(removed.ChatID, removed.UserID, removed.ID); err != nil {
+		log.Errorf("Ogohlantirishni o'chirishda xatolik: %v", err)
+		t.reply(bot, message, "unwarn_delete_failed", content.Vars{}, log)
+		return
+	}
+	h.recordModeration(bot, message.Chat, message.From, target, modlog.Entry{Action: modlog.Unwarn, Reason: removed.Reason}, log)
+	vars.Args = map[string]string{"reason": removed.Reason, "remaining": strconv.Itoa(len(warnings) - 1)}
+	t.reply(bot, message, "unwarn_done", vars, log)
+}
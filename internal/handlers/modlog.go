@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/modlog"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// modlogChannelSetting guruh choralari yoziladigan kanal saqlanadigan sozlama (bo'sh - standart kanal)
+const modlogChannelSetting = "modlog_channel"
+
+// modlogChannelOff guruh jurnal kanalini, shu jumladan sozlamalardagi standart kanalni ham o'chirib qo'ygan
+const modlogChannelOff = "off"
+
+// /modlog javobidagi yozuvlar soni va sabab matnining uzunligi
+const (
+	defaultModlogQuery = 10
+	maxModlogQuery     = 25
+	modlogReasonLength = 60
+)
+
+// recordModeration ko'rilgan chorani moderatsiya jurnaliga yozadi va uni guruh jurnal kanaliga yuboradi
+// actor nil bo'lsa chora avtomatik hisoblanadi, uning manbasi entry.Source da ko'rsatiladi.
+// Chora allaqachon bajarilgani uchun jurnalga yozib bo'lmasa faqat log qilinadi
+func (h *CommandHandler) recordModeration(bot *tgbotapi.BotAPI, chat *tgbotapi.Chat, actor, target *tgbotapi.User, entry modlog.Entry, log *logger.Logger) {
+	entry.ChatID = chat.ID
+	if actor != nil {
+		entry.ActorID = actor.ID
+		entry.ActorName = userLabel(actor)
+	}
+	if target != nil {
+		entry.TargetID = target.ID
+		entry.TargetName = userLabel(target)
+	}
+	saved, err := h.modlog.Add(entry)
+	if err != nil {
+		log.Errorf("Moderatsiya jurnaliga yozishda xatolik: %v", err)
+		saved = entry
+	}
+	h.mirrorModeration(bot, chat, saved, log)
+}
+
+// modlogChannel guruh choralari yoziladigan kanal: guruh o'zi belgilagan kanal yoki sozlamalardagi standart kanal
+// Guruh jurnalni o'chirgan bo'lsa 0 qaytariladi
+func (h *CommandHandler) modlogChannel(chatID int64) int64 {
+	value, err := h.store.GetSetting(chatID, modlogChannelSetting)
+	if err != nil || value == "" {
+		return h.config.ModlogSettings().Channel
+	}
+	channel, err := strconv.ParseInt(value, 10, 64)
+	if value == modlogChannelOff || err != nil {
+		return 0
+	}
+	return channel
+}
+
+// mirrorModeration jurnal yozuvini guruh jurnal kanaliga yuboradi
+func (h *CommandHandler) mirrorModeration(bot *tgbotapi.BotAPI, chat *tgbotapi.Chat, entry modlog.Entry, log *logger.Logger) {
+	channel := h.modlogChannel(chat.ID)
+	if channel == 0 {
+		return
+	}
+	title := chat.Title
+	if title == "" {
+		if stored, err := h.store.GetChat(chat.ID); err == nil {
+			title = stored.Title
+		}
+	}
+	t := h.translator(&tgbotapi.Chat{ID: channel}, nil)
+	vars := content.Vars{BotUsername: bot.Self.UserName, ChatTitle: title, Args: modlogArgs(t, entry)}
+	sendMessage(bot, channel, 0, t.message("modlog_post", vars), log)
+}
+
+// modlogArgs jurnal yozuvini matn shablonlari uchun qiymatlarga aylantiradi
+func modlogArgs(t *translator, e modlog.Entry) map[string]string {
+	args := map[string]string{
+		"id":      strconv.FormatInt(e.ID, 10),
+		"chat_id": strconv.FormatInt(e.ChatID, 10),
+		"time":    e.CreatedAt.Format("2006-01-02 15:04"),
+		"action":  t.text("modlog_action_"+e.Action, map[string]string{"count": strconv.Itoa(e.Count)}),
+		"actor":   e.ActorName,
+		"reason":  e.Reason,
+	}
+	if e.Source != "" {
+		args["source"] = t.text("modlog_source_"+e.Source, nil)
+	}
+	if e.TargetID != 0 {
+		args["target"] = e.TargetName
+		args["target_id"] = strconv.FormatInt(e.TargetID, 10)
+	}
+	if e.Duration > 0 {
+		args["duration"] = t.duration(e.Duration)
+	}
+	return args
+}
+
+// handleModlog guruh moderatsiya jurnalini ko'rsatadi yoki jurnal kanalini belgilaydi
+// /modlog [last N] - oxirgi choralar, /modlog @username (ID yoki xabarga javob) - foydalanuvchiga nisbatan choralar,
+// /modlog channel <kanal> | off - jurnal kanali
+func (h *CommandHandler) handleModlog(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	word, rest := cutWord(message.CommandArguments())
+
+	var filter modlog.Filter
+	var target *tgbotapi.User
+	switch strings.ToLower(word) {
+	case "channel":
+		h.setModlogChannel(bot, t, message, rest, log)
+		return
+	case "last", "":
+		filter.Limit = defaultModlogQuery
+		if rest != "" {
+			n, err := strconv.Atoi(rest)
+			if err != nil || n < 1 {
+				h.modlogUsage(bot, t, message, log)
+				return
+			}
+			filter.Limit = min(n, maxModlogQuery)
+		}
+		if word == "" && message.ReplyToMessage != nil && message.ReplyToMessage.From != nil {
+			target = message.ReplyToMessage.From
+		}
+	default:
+		var problem string
+		if target, _, problem = h.resolveTarget(message); problem != "" {
+			h.modlogUsage(bot, t, message, log)
+			return
+		}
+	}
+	if target != nil {
+		filter.TargetID, filter.Limit = target.ID, maxModlogQuery
+	}
+
+	entries, err := h.modlog.Find(message.Chat.ID, filter)
+	if err != nil {
+		log.Errorf("Moderatsiya jurnalini o'qishda xatolik: %v", err)
+		t.reply(bot, message, "modlog_failed", content.Vars{}, log)
+		return
+	}
+	vars := contentVars(bot, message.Chat, target)
+	if len(entries) == 0 {
+		key := "modlog_empty"
+		if target != nil {
+			key = "modlog_empty_user"
+		}
+		t.reply(bot, message, key, vars, log)
+		return
+	}
+
+	vars.Args = map[string]string{"count": strconv.Itoa(len(entries))}
+	key := "modlog_header"
+	if target != nil {
+		key = "modlog_header_user"
+	}
+	items := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if utf8.RuneCountInString(e.Reason) > modlogReasonLength {
+			e.Reason = string([]rune(e.Reason)[:modlogReasonLength]) + "…"
+		}
+		items = append(items, t.text("modlog_item", modlogArgs(t, e)))
+	}
+	sendMessage(bot, message.Chat.ID, message.MessageID, joinMessages(t.message(key, vars), content.Message{Text: strings.Join(items, "\n")}), log)
+}
+
+// modlogUsage /modlog buyrug'idan foydalanish yo'riqnomasini hozirgi jurnal kanali bilan yuboradi
+func (h *CommandHandler) modlogUsage(bot *tgbotapi.BotAPI, t *translator, message *tgbotapi.Message, log *logger.Logger) {
+	status := t.text("modlog_channel_status_off", nil)
+	if channel := h.modlogChannel(message.Chat.ID); channel != 0 {
+		status = t.text("modlog_channel_status_on", map[string]string{"channel": strconv.FormatInt(channel, 10)})
+	}
+	t.reply(bot, message, "modlog_usage", content.Vars{Args: map[string]string{"max": strconv.Itoa(maxModlogQuery), "status": status}}, log)
+}
+
+// setModlogChannel guruh choralari yoziladigan kanalni belgilaydi yoki o'chiradi
+// Kanal begona bo'lmasligi uchun buyruq yuboruvchi ham, bot ham kanalda admin bo'lishi kerak
+func (h *CommandHandler) setModlogChannel(bot *tgbotapi.BotAPI, t *translator, message *tgbotapi.Message, arg string, log *logger.Logger) {
+	chatID := message.Chat.ID
+	if arg == "" {
+		h.modlogUsage(bot, t, message, log)
+		return
+	}
+	if strings.EqualFold(arg, "off") {
+		if err := h.store.SetSetting(chatID, modlogChannelSetting, modlogChannelOff); err != nil {
+			log.Errorf("Jurnal kanali sozlamasini saqlashda xatolik: %v", err)
+			t.reply(bot, message, "modlog_failed", content.Vars{}, log)
+			return
+		}
+		log.Infof("%d guruh jurnal kanali o'chirildi", chatID)
+		t.reply(bot, message, "modlog_channel_off", content.Vars{}, log)
+		return
+	}
+
+	config := tgbotapi.ChatInfoConfig{}
+	if strings.HasPrefix(arg, "@") {
+		config.SuperGroupUsername = arg
+	} else if id, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		config.ChatID = id
+	} else {
+		t.reply(bot, message, "modlog_channel_bad", content.Vars{}, log)
+		return
+	}
+	channel, err := bot.GetChat(config)
+	if err != nil {
+		t.reply(bot, message, "modlog_channel_bad", content.Vars{}, log)
+		return
+	}
+	for _, userID := range []int64{message.From.ID, bot.Self.ID} {
+		member, err := chatMember(bot, channel.ID, userID)
+		if err != nil || (!member.IsCreator() && !member.IsAdministrator()) {
+			t.reply(bot, message, "modlog_channel_not_admin", content.Vars{}, log)
+			return
+		}
+	}
+
+	// Kanalga birinchi xabar yuborib ko'riladi, shunda yozish huquqi yo'qligi darhol ma'lum bo'ladi
+	ct := h.translator(&tgbotapi.Chat{ID: channel.ID}, nil)
+	probe := ct.message("modlog_channel_test", contentVars(bot, message.Chat, message.From))
+	msg := tgbotapi.NewMessage(channel.ID, probe.Text)
+	msg.ParseMode = probe.ParseMode
+	if _, err := bot.Send(msg); err != nil {
+		log.Warnf("Jurnal kanaliga yozib bo'lmadi: %v", err)
+		t.reply(bot, message, "mod_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+		return
+	}
+	if err := h.store.SetSetting(chatID, modlogChannelSetting, strconv.FormatInt(channel.ID, 10)); err != nil {
+		log.Errorf("Jurnal kanali sozlamasini saqlashda xatolik: %v", err)
+		t.reply(bot, message, "modlog_failed", content.Vars{}, log)
+		return
+	}
+	log.Infof("%d guruh choralari %d kanalga yoziladi", chatID, channel.ID)
+	t.reply(bot, message, "modlog_channel_set", content.Vars{Args: map[string]string{"channel": channel.Title}}, log)
+}
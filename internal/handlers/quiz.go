@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/quiz"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	_ "time/tzdata" // Kun savoli vaqt mintaqasi tizimda tzdata bo'lmasa ham topilishi uchun
+)
+
+// quizCallback viktorina javob tugmalari nomi
+// To'liq ko'rinishi "quiz:<savol ID>:<variant raqami>", variant raqami bankdagi (aralashtirilmagan) tartib bo'yicha
+const quizCallback = "quiz"
+
+// Viktorina sozlamalari kalitlari
+const (
+	quizRecentSetting    = "quiz_recent"     // Chatga oxirgi yuborilgan savollar, ular yana darhol tanlanmaydi
+	quizDailySetting     = "quiz_daily"      // Kun savoli vaqti (HH:MM), bo'sh bo'lsa o'chirilgan
+	quizDailyLastSetting = "quiz_daily_last" // Kun savoli oxirgi yuborilgan sana
+)
+
+// Viktorina chegaralari
+const (
+	quizRecentLimit = 10 // Nechta oxirgi savol takrorlanmaydi
+	quizTopLimit    = 10 // Reytingda ko'rsatiladigan ishtirokchilar soni
+)
+
+// handleQuiz chatga yo'l xaritasi bo'limlaridan tasodifiy savol yuboradi
+// Argument sifatida bo'lim raqami berilsa savol faqat shu bo'limdan tanlanadi
+func (h *CommandHandler) handleQuiz(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	section := 0
+	if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		n, err := strconv.Atoi(strings.TrimSuffix(arg, "."))
+		if _, ok := h.quiz.Section(n); err != nil || !ok {
+			t.reply(bot, message, "quiz_usage", content.Vars{Args: map[string]string{"sections": quizSections(t, h.quiz)}}, log)
+			return
+		}
+		section = n
+	}
+
+	if err := h.sendQuestion(bot, t, message.Chat.ID, section, "quiz_question"); err != nil {
+		log.Errorf("Viktorina savolini yuborishda xatolik: %v", err)
+		t.reply(bot, message, "quiz_failed", content.Vars{}, log)
+	}
+}
+
+// quizSections bo'limlar ro'yxatini matn ko'rinishida qaytaradi
+func quizSections(t *translator, bank *quiz.Bank) string {
+	var lines []string
+	for _, s := range bank.Sections() {
+		lines = append(lines, fmt.Sprintf("%d. %s", s.ID, t.uzText(s.Title)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sendQuestion chatga bo'limdan tasodifiy savol yuboradi, chatda yaqinda berilgan savollar takrorlanmaydi
+func (h *CommandHandler) sendQuestion(bot *tgbotapi.BotAPI, t *translator, chatID int64, section int, key string) error {
+	question, err := h.quiz.Random(section, h.recentQuestions(chatID)...)
+	if err != nil {
+		return err
+	}
+	return h.postQuestion(bot, t, chatID, question, key)
+}
+
+// postQuestion chatga savolni aralashtirilgan javob tugmalari bilan yuboradi
+// Yuborilgan savol chatning oxirgi savollari ro'yxatiga qo'shiladi
+func (h *CommandHandler) postQuestion(bot *tgbotapi.BotAPI, t *translator, chatID int64, question quiz.Question, key string) error {
+	title := ""
+	if s, ok := h.quiz.Section(question.Section); ok {
+		title = t.uzText(s.Title)
+	}
+
+	text := t.message(key, content.Vars{Args: map[string]string{
+		"n":        strconv.Itoa(question.Section),
+		"section":  title,
+		"question": t.uzText(question.Text),
+		"code":     question.Code,
+	}})
+
+	// Har bir variant alohida qatorda, chunki variantlar kod yoki uzunroq matn bo'lishi mumkin
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, i := range rand.Perm(len(question.Options)) {
+		data := h.CallbackData(quizCallback, question.ID, strconv.Itoa(i))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(question.Options[i], data)))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text.Text)
+	msg.ParseMode = text.ParseMode
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := bot.Send(msg); err != nil {
+		return err
+	}
+
+	recent := append(h.recentQuestions(chatID), question.ID)
+	if len(recent) > quizRecentLimit {
+		recent = recent[len(recent)-quizRecentLimit:]
+	}
+	if err := h.store.SetSetting(chatID, quizRecentSetting, strings.Join(recent, ",")); err != nil {
+		h.logger.Warnf("Oxirgi savollar ro'yxatini saqlashda xatolik: %v", err)
+	}
+	return nil
+}
+
+// recentQuestions chatga oxirgi yuborilgan savollar identifikatorlarini qaytaradi
+func (h *CommandHandler) recentQuestions(chatID int64) []string {
+	value, err := h.store.GetSetting(chatID, quizRecentSetting)
+	if err != nil || value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// handleQuizCallback javob tugmasi bosilganda natijani hisoblaydi va izohni ko'rsatadi
+// Har bir ishtirokchining bitta savolga faqat birinchi javobi hisoblanadi
+func (h *CommandHandler) handleQuizCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, args []string, log *logger.Logger) CallbackResult {
+	if len(args) != 2 || callback.Message == nil || callback.From == nil || h.quiz == nil {
+		return CallbackResult{}
+	}
+	index, err := strconv.Atoi(args[1])
+	if err != nil {
+		return CallbackResult{}
+	}
+
+	t := translatorFrom(ctx)
+	question, found := h.quiz.Question(args[0])
+	if !found || index < 0 || index >= len(question.Options) {
+		return callbackAlert(t.text("quiz_unknown", nil))
+	}
+
+	correct := index == question.Answer
+	score, err := h.scores.Record(callback.Message.Chat.ID, callback.Message.MessageID, callback.From.ID, correct)
+	if errors.Is(err, quiz.ErrAlreadyAnswered) {
+		return callbackAlert(t.text("quiz_already", nil))
+	}
+	if err != nil {
+		log.Errorf("Viktorina natijasini saqlashda xatolik: %v", err)
+		return callbackAlert(t.text("quiz_failed", nil))
+	}
+
+	vars := map[string]string{
+		"answer":      question.Options[question.Answer],
+		"streak":      strconv.Itoa(score.Streak),
+		"explanation": t.uzText(question.Explanation),
+	}
+	log.Debugf("%d foydalanuvchi %s savoliga javob berdi: %t", callback.From.ID, question.ID, correct)
+	if correct {
+		return callbackAlert(t.text("quiz_correct", vars))
+	}
+	return callbackAlert(t.text("quiz_wrong", vars))
+}
+
+// handleTop viktorina reytingini ko'rsatadi
+// Guruhda shu guruh reytingi, shaxsiy chatda yoki "global" argumenti bilan umumiy reyting chiqariladi
+func (h *CommandHandler) handleTop(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	scope := message.Chat.ID
+	if message.Chat.IsPrivate() || strings.EqualFold(strings.TrimSpace(message.CommandArguments()), "global") {
+		scope = quiz.Global
+	}
+
+	top, err := h.scores.Top(scope, quizTopLimit)
+	if err != nil {
+		log.Errorf("Viktorina reytingini o'qishda xatolik: %v", err)
+		t.reply(bot, message, "quiz_failed", content.Vars{}, log)
+		return
+	}
+	if len(top) == 0 {
+		t.reply(bot, message, "quiz_top_empty", content.Vars{}, log)
+		return
+	}
+
+	header := "quiz_top_chat"
+	if scope == quiz.Global {
+		header = "quiz_top_global"
+	}
+	parts := []content.Message{t.message(header, contentVars(bot, message.Chat, message.From))}
+
+	var items []string
+	listed := false
+	for i, score := range top {
+		items = append(items, t.text("quiz_top_item", map[string]string{
+			"n":        strconv.Itoa(i + 1),
+			"name":     h.displayName(score.UserID),
+			"correct":  strconv.Itoa(score.Correct),
+			"answered": strconv.Itoa(score.Answered),
+			"best":     strconv.Itoa(score.BestStreak),
+		}))
+		listed = listed || (message.From != nil && score.UserID == message.From.ID)
+	}
+	parts = append(parts, content.Message{Text: strings.Join(items, "\n")})
+
+	// Ro'yxatga kirmagan ishtirokchiga o'z natijasi alohida ko'rsatiladi
+	if !listed && message.From != nil {
+		if own, err := h.scores.Get(scope, message.From.ID); err == nil && own.Answered > 0 {
+			parts = append(parts, content.Message{Text: t.text("quiz_top_you", map[string]string{
+				"correct":  strconv.Itoa(own.Correct),
+				"answered": strconv.Itoa(own.Answered),
+				"streak":   strconv.Itoa(own.Streak),
+			})})
+		}
+	}
+
+	sendMessage(bot, message.Chat.ID, message.MessageID, joinMessages(parts...), log)
+}
+
+// displayName foydalanuvchining ombordagi ismini qaytaradi
+func (h *CommandHandler) displayName(userID int64) string {
+	user, err := h.store.GetUser(userID)
+	if err != nil {
+		return strconv.FormatInt(userID, 10)
+	}
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	if user.UserName != "" {
+		return "@" + user.UserName
+	}
+	return strconv.FormatInt(userID, 10)
+}
+
+// parseDailyTime kun savoli vaqtini "HH:MM" ko'rinishidan o'qiydi
+func parseDailyTime(value string) (time.Time, bool) {
+	at, err := time.Parse("15:04", strings.TrimSpace(value))
+	return at, err == nil
+}
+
+// handleQuizDaily guruh uchun kun savoli vaqtini belgilaydi yoki o'chiradi (faqat adminlar uchun)
+func (h *CommandHandler) handleQuizDaily(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	chatID := message.Chat.ID
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+
+	if arg == "" {
+		status := t.text("quizdaily_status_off", nil)
+		if value, err := h.store.GetSetting(chatID, quizDailySetting); err == nil && value != "" {
+			status = t.text("quizdaily_status_on", map[string]string{"time": value, "timezone": h.quizLocation.String()})
+		}
+		t.reply(bot, message, "quizdaily_usage", content.Vars{Args: map[string]string{"status": status}}, log)
+		return
+	}
+
+	if arg == "off" {
+		if err := h.store.SetSetting(chatID, quizDailySetting, ""); err != nil {
+			log.Errorf("Kun savoli sozlamasini saqlashda xatolik: %v", err)
+			t.reply(bot, message, "quiz_failed", content.Vars{}, log)
+			return
+		}
+		log.Infof("%d chatida kun savoli o'chirildi", chatID)
+		t.reply(bot, message, "quizdaily_off", content.Vars{}, log)
+		return
+	}
+
+	at, ok := parseDailyTime(arg)
+	if !ok {
+		t.reply(bot, message, "quizdaily_bad_time", content.Vars{}, log)
+		return
+	}
+	value := at.Format("15:04")
+
+	// Bugungi vaqt o'tib ketgan bo'lsa birinchi savol ertaga yuboriladi
+	now := time.Now().In(h.quizLocation)
+	if dailyDue(now, at) {
+		if err := h.store.SetSetting(chatID, quizDailyLastSetting, now.Format(time.DateOnly)); err != nil {
+			log.Warnf("Kun savoli sanasini saqlashda xatolik: %v", err)
+		}
+	}
+	if err := h.store.SetSetting(chatID, quizDailySetting, value); err != nil {
+		log.Errorf("Kun savoli sozlamasini saqlashda xatolik: %v", err)
+		t.reply(bot, message, "quiz_failed", content.Vars{}, log)
+		return
+	}
+
+	log.Infof("%d chatida kun savoli %s ga belgilandi", chatID, value)
+	t.reply(bot, message, "quizdaily_set", content.Vars{Args: map[string]string{"time": value, "timezone": h.quizLocation.String()}}, log)
+}
+
+// dailyDue bugungi kun savoli vaqti kelganini tekshiradi
+func dailyDue(now, at time.Time) bool {
+	return now.Hour() > at.Hour() || (now.Hour() == at.Hour() && now.Minute() >= at.Minute())
+}
+
+// RunDailyQuiz kun savolini belgilangan vaqtda guruhlarga yuboradi va eski javob yozuvlarini tozalaydi
+// Har daqiqada tekshiriladi, ctx bekor qilinguncha ishlaydi
+func (h *CommandHandler) RunDailyQuiz(ctx context.Context, bot *tgbotapi.BotAPI) {
+	if h.quiz == nil {
+		return
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	var pruned time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.sendDailyQuestions(ctx, bot, now.In(h.quizLocation))
+
+			if now.Sub(pruned) >= time.Hour {
+				pruned = now
+				if n, err := h.scores.Prune(now); err != nil {
+					h.logger.Warnf("Eski viktorina javoblarini o'chirishda xatolik: %v", err)
+				} else if n > 0 {
+					h.logger.Debugf("%d ta eski viktorina javobi o'chirildi", n)
+				}
+			}
+		}
+	}
+}
+
+// sendDailyQuestions vaqti kelgan va bugun hali savol olmagan chatlarga kun savolini yuboradi
+// Sana savol yuborilishidan oldin yoziladi, shuning uchun bot qayta ishga tushsa ham savol takrorlanmaydi
+func (h *CommandHandler) sendDailyQuestions(ctx context.Context, bot *tgbotapi.BotAPI, now time.Time) {
+	chats, err := h.store.ListChats()
+	if err != nil {
+		h.logger.Errorf("Chatlar ro'yxatini olishda xatolik: %v", err)
+		return
+	}
+
+	today := now.Format(time.DateOnly)
+	for _, chat := range chats {
+		if ctx.Err() != nil {
+			return
+		}
+		value, err := h.store.GetSetting(chat.ID, quizDailySetting)
+		if err != nil {
+			if !errors.Is(err, storage.ErrNotFound) {
+				h.logger.Debugf("%d uchun kun savoli sozlamasini o'qishda xatolik: %v", chat.ID, err)
+			}
+			continue
+		}
+		at, ok := parseDailyTime(value)
+		if !ok || !dailyDue(now, at) {
+			continue
+		}
+		if last, _ := h.store.GetSetting(chat.ID, quizDailyLastSetting); last == today {
+			continue
+		}
+
+		if err := h.store.SetSetting(chat.ID, quizDailyLastSetting, today); err != nil {
+			h.logger.Errorf("Kun savoli sanasini saqlashda xatolik: %v", err)
+			continue
+		}
+		t := h.translatorFor(h.locale(&tgbotapi.Chat{ID: chat.ID}, nil))
+		if err := h.sendQuestion(bot, t, chat.ID, 0, "quiz_daily_question"); err != nil {
+			var apiErr *tgbotapi.Error
+			if errors.As(err, &apiErr) && apiErr.Code == 403 {
+				h.logger.Warnf("%d chatiga kun savoli yuborib bo'lmadi, u o'chirildi: %v", chat.ID, err)
+				if err := h.store.SetSetting(chat.ID, quizDailySetting, ""); err != nil {
+					h.logger.Errorf("Kun savoli sozlamasini saqlashda xatolik: %v", err)
+				}
+				continue
+			}
+			h.logger.Warnf("%d chatiga kun savolini yuborishda xatolik: %v", chat.ID, err)
+			continue
+		}
+		h.logger.Infof("%d chatiga kun savoli yuborildi", chat.ID)
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"tg-bot/internal/i18n"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Scope buyruq Telegram menyusida qayerda ko'rinishini belgilaydi
+type Scope string
+
+// Buyruqlar ko'rinadigan joylar
+const (
+	ScopeDefault Scope = "default"                 // Barcha chatlarda
+	ScopePrivate Scope = "all_private_chats"       // Faqat bot bilan shaxsiy yozishmada
+	ScopeGroup   Scope = "all_group_chats"         // Faqat guruhlarda
+	ScopeAdmins  Scope = "all_chat_administrators" // Faqat guruh adminlariga
+)
+
+// defaultLanguage tavsiflar uchun asosiy til (til kodi ko'rsatilmagan menyu)
+const defaultLanguage = ""
+
+// CommandSpec buyruqning Telegram menyusi va /help uchun tavsifi
+type CommandSpec struct {
+	Name        string            // Buyruq nomi, "/" belgisisiz
+	Description map[string]string // Til kodi bo'yicha tavsiflar, "" kaliti asosiy (o'zbekcha) tavsif
+	Scope       Scope             // Buyruq ko'rinadigan joy
+	Hidden      bool              // true bo'lsa buyruq menyuda va /help da ko'rsatilmaydi
+}
+
+// description til uchun tavsifni qaytaradi, topilmasa asosiy tavsifga qaytadi
+func (s CommandSpec) description(lang string) string {
+	if text, ok := s.Description[lang]; ok && text != "" {
+		return text
+	}
+	return s.Description[defaultLanguage]
+}
+
+// localizedDescription bot tili uchun tavsifni qaytaradi
+// O'zbekcha kirill tavsifi lotin tavsifidan transliteratsiya qilinadi
+func (s CommandSpec) localizedDescription(locale i18n.Locale) string {
+	switch locale {
+	case i18n.Ru, i18n.En:
+		return s.description(string(locale))
+	case i18n.UzCyrl:
+		return i18n.ToCyrillic(s.description(defaultLanguage))
+	default:
+		return s.description(defaultLanguage)
+	}
+}
+
+// command ro'yxatdan o'tgan buyruq, uning tavsifi va unga tegishli middleware'lar
+type command struct {
+	spec        CommandSpec
+	fn          CommandFunction
+	middlewares []Middleware
+}
+
+// Handle buyruqni uning tavsifi, qayta ishlovchisi va faqat shu buyruqqa tegishli middleware'lar bilan ro'yxatdan o'tkazadi
+// Buyruqlar ro'yxatdan o'tish tartibida menyuda va /help da ko'rsatiladi
+func (h *CommandHandler) Handle(spec CommandSpec, fn CommandFunction, middlewares ...Middleware) {
+	if h.commands == nil {
+		h.commands = make(map[string]command)
+	}
+	if spec.Scope == "" {
+		spec.Scope = ScopeDefault
+	}
+	if _, exists := h.commands[spec.Name]; !exists {
+		h.order = append(h.order, spec.Name)
+	}
+	h.commands[spec.Name] = command{spec: spec, fn: fn, middlewares: middlewares}
+}
+
+// Commands ro'yxatdan o'tgan buyruqlar tavsiflarini ro'yxatdan o'tish tartibida qaytaradi
+func (h *CommandHandler) Commands() []CommandSpec {
+	specs := make([]CommandSpec, 0, len(h.order))
+	for _, name := range h.order {
+		specs = append(specs, h.commands[name].spec)
+	}
+	return specs
+}
+
+// visibleIn buyruq berilgan menyu doirasida ko'rinishi kerakligini aniqlaydi
+// Telegram aniqroq doira uchun ro'yxat topsa umumiy ro'yxatni ko'rsatmaydi,
+// shuning uchun har bir doira ro'yxati umumiy buyruqlarni ham o'z ichiga oladi
+func visibleIn(spec CommandSpec, scope Scope) bool {
+	if spec.Hidden {
+		return false
+	}
+	switch spec.Scope {
+	case ScopeDefault:
+		return true
+	case ScopeGroup:
+		return scope == ScopeGroup || scope == ScopeAdmins
+	default:
+		return spec.Scope == scope
+	}
+}
+
+// botCommandScope doirani Telegram API turiga o'giradi
+func botCommandScope(scope Scope) tgbotapi.BotCommandScope {
+	switch scope {
+	case ScopePrivate:
+		return tgbotapi.NewBotCommandScopeAllPrivateChats()
+	case ScopeGroup:
+		return tgbotapi.NewBotCommandScopeAllGroupChats()
+	case ScopeAdmins:
+		return tgbotapi.NewBotCommandScopeAllChatAdministrators()
+	default:
+		return tgbotapi.NewBotCommandScopeDefault()
+	}
+}
+
+// languages tavsiflarda uchraydigan barcha til kodlarini qaytaradi
+func (h *CommandHandler) languages() []string {
+	seen := map[string]bool{defaultLanguage: true}
+	for _, cmd := range h.commands {
+		for lang := range cmd.spec.Description {
+			seen[lang] = true
+		}
+	}
+
+	langs := make([]string, 0, len(seen))
+	for lang := range seen {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// PublishCommands buyruqlar ro'yxatini setMyCommands orqali Telegram'ga yuboradi
+// Har bir til va har bir doira uchun alohida ro'yxat yuboriladi, shunda foydalanuvchilar
+// o'z tilida va faqat o'zlariga tegishli buyruqlarni avtomatik to'ldirishda ko'radi
+func (h *CommandHandler) PublishCommands(bot *tgbotapi.BotAPI) error {
+	scopes := []Scope{ScopeDefault, ScopePrivate, ScopeGroup, ScopeAdmins}
+
+	var firstErr error
+	for _, lang := range h.languages() {
+		for _, scope := range scopes {
+			var list []tgbotapi.BotCommand
+			for _, spec := range h.Commands() {
+				if visibleIn(spec, scope) {
+					list = append(list, tgbotapi.BotCommand{Command: spec.Name, Description: spec.description(lang)})
+				}
+			}
+
+			cfg := tgbotapi.NewSetMyCommandsWithScopeAndLanguage(botCommandScope(scope), lang, list...)
+			if _, err := bot.Request(cfg); err != nil {
+				h.logger.Errorf("%s doirasi uchun buyruqlarni yuborishda xatolik (til: %q): %v", scope, lang, err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+
+	if firstErr == nil {
+		h.logger.Infof("Buyruqlar ro'yxati Telegram'ga yuborildi (%d til)", len(h.languages()))
+	}
+	return firstErr
+}
+
+// GetHelpText mavjud barcha buyruqlar ro'yxati va ularning ko'rsatilgan tildagi qisqacha tavsifi
+// Ro'yxat buyruqlar registridan yasaladi, shuning uchun u Telegram menyusi bilan doim bir xil bo'ladi
+func (h *CommandHandler) GetHelpText(locale i18n.Locale) string {
+	t := h.translatorFor(locale)
+
+	var b strings.Builder
+	b.WriteString(t.text("help_header", nil))
+	b.WriteString("\n")
+
+	for _, spec := range h.Commands() {
+		if spec.Hidden {
+			continue
+		}
+		b.WriteString("\n/" + spec.Name + " - " + spec.localizedDescription(locale))
+		switch spec.Scope {
+		case ScopeAdmins:
+			b.WriteString(" " + t.text("help_scope_admins", nil))
+		case ScopeGroup:
+			b.WriteString(" " + t.text("help_scope_group", nil))
+		case ScopePrivate:
+			b.WriteString(" " + t.text("help_scope_private", nil))
+		}
+	}
+	return b.String()
+}
+
+// handleHelp foydalanuvchi tilidagi buyruqlar ro'yxatini yuboradi
+func (h *CommandHandler) handleHelp(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	locale := i18n.Default
+	if t := translatorFrom(ctx); t != nil {
+		locale = t.locale
+	}
+	sendText(bot, message.Chat.ID, h.GetHelpText(locale), log)
+}
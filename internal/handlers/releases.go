@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/releases"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// versionSuggestions topilmagan versiya uchun taklif qilinadigan versiyalar soni
+const versionSuggestions = 5
+
+// handleLatest eng so'nggi barqaror Go relizi haqida ma'lumot yuboradi
+// Undan yangiroq sinov versiyasi chiqqan bo'lsa, bu haqda ham eslatiladi
+func (h *CommandHandler) handleLatest(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	release, err := h.releases.Latest()
+	if err != nil {
+		log.Debugf("Oxirgi relizni olib bo'lmadi: %v", err)
+		t.reply(bot, message, "release_not_ready", content.Vars{}, log)
+		return
+	}
+
+	note := ""
+	if pre, ok := h.releases.Prerelease(); ok {
+		note = t.text("release_prerelease_available", map[string]string{"version": pre.Version.String()})
+	}
+	sendMessage(bot, message.Chat.ID, 0, h.releaseMessage(t, release, note), log)
+}
+
+// handleVersion so'ralgan Go versiyasi haqida ma'lumot yuboradi
+// So'rov aniq versiya (1.22.1, go1.21rc2), qator (1.22) yoki latest-N ko'rinishida bo'lishi mumkin
+func (h *CommandHandler) handleVersion(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	query := strings.TrimSpace(message.CommandArguments())
+	if query == "" {
+		t.reply(bot, message, "version_usage", content.Vars{}, log)
+		return
+	}
+
+	release, err := h.releases.Find(query)
+	switch {
+	case errors.Is(err, releases.ErrNotReady):
+		t.reply(bot, message, "release_not_ready", content.Vars{}, log)
+		return
+	case errors.Is(err, releases.ErrBadQuery):
+		t.reply(bot, message, "version_bad_query", content.Vars{Args: map[string]string{"query": query}}, log)
+		return
+	case err != nil:
+		suggestions := strings.Join(h.releases.Suggestions(query, versionSuggestions), ", ")
+		t.reply(bot, message, "version_not_found", content.Vars{Args: map[string]string{"query": query, "suggestions": suggestions}}, log)
+		return
+	}
+
+	// Qatorning eski versiyasi so'ralgan bo'lsa, eng so'nggisini ham ko'rsatamiz
+	note := ""
+	if newest, ok := h.releases.Newest(release); ok && newest.Version.Compare(release.Version) > 0 {
+		note = t.text("release_newer", map[string]string{"version": newest.Version.String()})
+	}
+	sendMessage(bot, message.Chat.ID, message.MessageID, h.releaseMessage(t, release, note), log)
+}
+
+// releaseMessage reliz haqidagi xabarni foydalanuvchi tilida tayyorlaydi
+func (h *CommandHandler) releaseMessage(t *translator, release releases.Release, note string) content.Message {
+	status := "release_status_unsupported"
+	switch {
+	case release.Version.Prerelease():
+		status = "release_status_prerelease"
+	case h.isLatest(release):
+		status = "release_status_latest"
+	case h.releases.Supported(release):
+		status = "release_status_supported"
+	}
+
+	security := ""
+	if release.Security {
+		security = t.text("release_security", map[string]string{"fixes": release.SecurityFixes})
+	}
+
+	date := ""
+	if !release.Date.IsZero() {
+		date = release.Date.Format(time.DateOnly)
+	}
+
+	var files []string
+	for _, f := range release.Downloads() {
+		platform := f.OS + "/" + f.Arch
+		if f.Kind == "source" {
+			platform = t.text("release_source", nil)
+		}
+		files = append(files, t.text("release_file", map[string]string{
+			"platform": platform,
+			"url":      f.URL(),
+			"sha256":   f.SHA256,
+		}))
+	}
+
+	return t.message("release", content.Vars{Args: map[string]string{
+		"version":  release.Version.String(),
+		"date":     date,
+		"status":   t.text(status, nil),
+		"security": security,
+		"note":     note,
+		"files":    strings.Join(files, "\n\n"),
+		"notes":    release.NotesURL(),
+	}})
+}
+
+// isLatest reliz eng so'nggi barqaror reliz ekanligini tekshiradi
+func (h *CommandHandler) isLatest(release releases.Release) bool {
+	latest, err := h.releases.Latest()
+	return err == nil && latest.Version.Compare(release.Version) == 0
+}
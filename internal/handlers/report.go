@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/modlog"
+	"tg-bot/internal/reports"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// reportCallback shikoyat tugmalari nomi
+const reportCallback = "report"
+
+// Shikoyat bo'yicha adminlar ko'rishi mumkin bo'lgan choralar
+const (
+	reportDelete  = "delete"
+	reportWarn    = "warn"
+	reportMute    = "mute"
+	reportBan     = "ban"
+	reportDismiss = "dismiss"
+)
+
+// handleReport javob berilgan xabar haqida adminlarga shikoyat yuboradi
+// Bitta xabar bo'yicha adminlarga bir marta yoziladi, keyingi shikoyatchilar mavjud shikoyatga qo'shiladi
+func (h *CommandHandler) handleReport(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	reported := message.ReplyToMessage
+	if reported == nil || reported.From == nil || reported.SenderChat != nil || message.From == nil {
+		t.reply(bot, message, "report_usage", content.Vars{}, log)
+		return
+	}
+	author := reported.From
+	switch {
+	case author.IsBot:
+		t.reply(bot, message, "report_target_bot", content.Vars{}, log)
+		return
+	case author.ID == message.From.ID:
+		t.reply(bot, message, "report_target_yourself", content.Vars{}, log)
+		return
+	}
+	admin, err := isChatAdmin(bot, message.Chat.ID, author.ID)
+	if err != nil {
+		log.Errorf("Admin huquqlarini tekshirishda xatolik: %v", err)
+		t.reply(bot, message, "admin_check_failed", content.Vars{}, log)
+		return
+	}
+	if admin {
+		t.reply(bot, message, "report_target_admin", content.Vars{}, log)
+		return
+	}
+
+	text := reported.Text
+	if text == "" {
+		text = reported.Caption
+	}
+	if utf8.RuneCountInString(text) > auditExcerptLength {
+		text = string([]rune(text)[:auditExcerptLength]) + "…"
+	}
+	report, created, err := h.reports.File(reports.Report{
+		ChatID:         message.Chat.ID,
+		ChatTitle:      message.Chat.Title,
+		ChatUserName:   message.Chat.UserName,
+		MessageID:      reported.MessageID,
+		Text:           text,
+		AuthorID:       author.ID,
+		AuthorName:     strings.TrimSpace(author.FirstName + " " + author.LastName),
+		AuthorUserName: author.UserName,
+		ReporterName:   userLabel(message.From),
+		Reason:         strings.TrimSpace(message.CommandArguments()),
+	}, message.From.ID)
+	switch {
+	case errors.Is(err, reports.ErrAlreadyReported):
+		t.reply(bot, message, "report_already", content.Vars{}, log)
+		return
+	case errors.Is(err, reports.ErrHandled):
+		t.reply(bot, message, "report_already_handled", content.Vars{}, log)
+		return
+	case err != nil:
+		log.Errorf("Shikoyatni saqlashda xatolik: %v", err)
+		t.reply(bot, message, "report_failed", content.Vars{}, log)
+		return
+	}
+
+	// Avvalgi urinishda shikoyat hech bir adminga yetib bormagan bo'lsa u qayta yuboriladi
+	if created || len(report.Deliveries) == 0 {
+		report.Deliveries = h.deliverReport(bot, report, log)
+		if err := h.reports.SetDeliveries(report.ChatID, report.MessageID, report.Deliveries); err != nil {
+			log.Errorf("Shikoyat xabarlarini saqlashda xatolik: %v", err)
+		}
+		if len(report.Deliveries) == 0 {
+			t.reply(bot, message, "report_no_admins", content.Vars{}, log)
+			return
+		}
+		log.Infof("%d foydalanuvchi %d guruhdagi %d xabar haqida shikoyat qildi", message.From.ID, report.ChatID, report.MessageID)
+	} else {
+		h.refreshReport(bot, report, log)
+	}
+
+	// Shikoyatchini boshqalarga ko'rsatmaslik uchun buyruq o'chiriladi, javob esa qisqa vaqt turadi
+	deleteMessage(bot, message.Chat.ID, message.MessageID, log)
+	sendNotice(bot, message.Chat.ID, t.message("report_sent", contentVars(bot, message.Chat, message.From)), log)
+}
+
+// reportRecipients shikoyat yuboriladigan chatlar: sozlangan adminlar guruhi yoki guruhning har bir admini
+func (h *CommandHandler) reportRecipients(bot *tgbotapi.BotAPI, chatID int64) ([]int64, error) {
+	if adminChat := h.config.ReportSettings().Chat; adminChat != 0 {
+		return []int64{adminChat}, nil
+	}
+	admins, err := bot.GetChatAdministrators(tgbotapi.ChatAdministratorsConfig{
+		ChatConfig: tgbotapi.ChatConfig{ChatID: chatID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var recipients []int64
+	for _, admin := range admins {
+		if admin.User != nil && !admin.User.IsBot {
+			recipients = append(recipients, admin.User.ID)
+		}
+	}
+	return recipients, nil
+}
+
+// reportTranslator shikoyat xabari yuboriladigan chat tilidagi translator
+func (h *CommandHandler) reportTranslator(chatID int64) *translator {
+	if chatID == h.config.ReportSettings().Chat {
+		return h.translator(&tgbotapi.Chat{ID: chatID}, nil)
+	}
+	return h.translator(nil, &tgbotapi.User{ID: chatID})
+}
+
+// deliverReport shikoyat qilingan xabarni adminlarga uzatadi va uning ostiga choralar tugmalari bilan shikoyat xabarini yuboradi
+// Botga hali yozmagan adminlarga xabar yetib bormaydi, ular tashlab ketiladi
+func (h *CommandHandler) deliverReport(bot *tgbotapi.BotAPI, report reports.Report, log *logger.Logger) []reports.Delivery {
+	recipients, err := h.reportRecipients(bot, report.ChatID)
+	if err != nil {
+		log.Errorf("Shikoyat uchun adminlar ro'yxatini olishda xatolik: %v", err)
+		return nil
+	}
+
+	var deliveries []reports.Delivery
+	for _, chatID := range recipients {
+		// Xabar o'chirilgan yoki uni uzatish taqiqlangan bo'lsa shikoyatda uning matnidan parcha qoladi
+		var replyTo int
+		if forwarded, err := bot.Send(tgbotapi.NewForward(chatID, report.ChatID, report.MessageID)); err != nil {
+			log.Debugf("Shikoyat qilingan xabarni %d chatga uzatib bo'lmadi: %v", chatID, err)
+		} else {
+			replyTo = forwarded.MessageID
+		}
+
+		t := h.reportTranslator(chatID)
+		card := h.reportCard(t, bot, report)
+		msg := tgbotapi.NewMessage(chatID, card.Text)
+		msg.ParseMode = card.ParseMode
+		msg.DisableWebPagePreview = true
+		msg.ReplyToMessageID = replyTo
+		msg.ReplyMarkup = h.reportKeyboard(t, report)
+		sent, err := bot.Send(msg)
+		if err != nil {
+			log.Debugf("Shikoyatni %d chatga yuborib bo'lmadi: %v", chatID, err)
+			continue
+		}
+		deliveries = append(deliveries, reports.Delivery{ChatID: chatID, MessageID: sent.MessageID})
+	}
+	return deliveries
+}
+
+// refreshReport adminlarga yuborilgan shikoyat xabarlarini shikoyatning hozirgi holatiga moslab tahrirlaydi
+// Ko'rib chiqilgan shikoyatlar xabaridan tugmalar olib tashlanadi
+func (h *CommandHandler) refreshReport(bot *tgbotapi.BotAPI, report reports.Report, log *logger.Logger) {
+	for _, d := range report.Deliveries {
+		t := h.reportTranslator(d.ChatID)
+		card := h.reportCard(t, bot, report)
+		edit := tgbotapi.NewEditMessageText(d.ChatID, d.MessageID, card.Text)
+		edit.ParseMode = card.ParseMode
+		edit.DisableWebPagePreview = true
+		if !report.Handled() {
+			markup := h.reportKeyboard(t, report)
+			edit.ReplyMarkup = &markup
+		}
+		if _, err := bot.Send(edit); err != nil {
+			log.Debugf("Shikoyat xabarini yangilab bo'lmadi: %v", err)
+		}
+	}
+}
+
+// reportCard shikoyat xabari matni, shikoyat ko'rib chiqilgan bo'lsa kim va qanday chora ko'rgani qo'shiladi
+func (h *CommandHandler) reportCard(t *translator, bot *tgbotapi.BotAPI, report reports.Report) content.Message {
+	vars := content.Vars{
+		BotUsername: bot.Self.UserName,
+		ChatTitle:   report.ChatTitle,
+		UserID:      report.AuthorID,
+		UserName:    report.AuthorUserName,
+		FirstName:   report.AuthorName,
+		Args: map[string]string{
+			"chat_id":  strconv.FormatInt(report.ChatID, 10),
+			"user_id":  strconv.FormatInt(report.AuthorID, 10),
+			"reporter": report.ReporterName,
+			"reason":   report.Reason,
+			"text":     report.Text,
+			"link":     messageLink(report.ChatID, report.ChatUserName, report.MessageID),
+		},
+	}
+	if others := len(report.Reporters) - 1; others > 0 {
+		vars.Args["others"] = strconv.Itoa(others)
+	}
+	card := t.message("report_card", vars)
+	if !report.Handled() {
+		return card
+	}
+	handled := t.message("report_handled", content.Vars{Args: map[string]string{
+		"admin":  report.HandlerName,
+		"action": t.text("report_action_"+report.Action, map[string]string{"duration": t.duration(h.config.ReportSettings().MuteDuration)}),
+		"time":   report.HandledAt.Format("2006-01-02 15:04"),
+	}})
+	return joinMessages(card, handled)
+}
+
+// reportKeyboard shikoyat bo'yicha choralar tugmalari
+func (h *CommandHandler) reportKeyboard(t *translator, report reports.Report) tgbotapi.InlineKeyboardMarkup {
+	chatID, messageID := strconv.FormatInt(report.ChatID, 10), strconv.Itoa(report.MessageID)
+	button := func(action string) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(t.text("report_button_"+action, nil), h.CallbackData(reportCallback, action, chatID, messageID))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(button(reportDelete), button(reportWarn)),
+		tgbotapi.NewInlineKeyboardRow(button(reportMute), button(reportBan)),
+		tgbotapi.NewInlineKeyboardRow(button(reportDismiss)),
+	)
+}
+
+// messageLink guruhdagi xabarga havola, oddiy (superguruh bo'lmagan) guruh xabarlariga havola berib bo'lmaydi
+func messageLink(chatID int64, chatUserName string, messageID int) string {
+	const supergroupPrefix = -1000000000000
+	switch {
+	case chatUserName != "":
+		return "https://t.me/" + chatUserName + "/" + strconv.Itoa(messageID)
+	case chatID < supergroupPrefix:
+		return "https://t.me/c/" + strconv.FormatInt(supergroupPrefix-chatID, 10) + "/" + strconv.Itoa(messageID)
+	}
+	return ""
+}
+
+// reportRight chora uchun admin va botda bo'lishi kerak bo'lgan huquq
+func reportRight(action string) adminRight {
+	if action == reportDelete {
+		return rightDelete
+	}
+	return rightRestrict
+}
+
+// handleReportCallback shikoyat ostidagi tugma bosilganda chorani asl guruhda bajaradi
+// Tugmani faqat guruhning kerakli huquqqa ega adminlari bosa oladi, shikoyatni faqat bitta admin ko'rib chiqadi
+func (h *CommandHandler) handleReportCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, args []string, log *logger.Logger) CallbackResult {
+	t := translatorFrom(ctx)
+	if len(args) != 3 {
+		return callbackToast(t.text("callback_unknown", nil))
+	}
+	action := args[0]
+	chatID, err1 := strconv.ParseInt(args[1], 10, 64)
+	messageID, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		return callbackToast(t.text("callback_unknown", nil))
+	}
+	switch action {
+	case reportDelete, reportWarn, reportMute, reportBan, reportDismiss:
+	default:
+		return callbackToast(t.text("callback_unknown", nil))
+	}
+
+	report, err := h.reports.Get(chatID, messageID)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return CallbackResult{Text: t.text("callback_expired", nil), RemoveKeyboard: true}
+	case err != nil:
+		log.Errorf("Shikoyatni o'qishda xatolik: %v", err)
+		return callbackAlert(t.text("report_failed", nil))
+	case report.Handled():
+		h.refreshReport(bot, report, log)
+		return callbackToast(t.text("report_already_handled", nil))
+	}
+
+	// Tugmani bosgan admin va botning o'zida chora uchun kerakli huquq borligi tekshiriladi
+	right := reportRight(action)
+	rightVars := map[string]string{"right": t.text(right.key(), nil)}
+	member, err := chatMember(bot, chatID, callback.From.ID)
+	if err != nil {
+		log.Errorf("Admin huquqlarini tekshirishda xatolik: %v", err)
+		return callbackAlert(t.text("admin_check_failed", nil))
+	}
+	if !member.IsCreator() && !member.IsAdministrator() {
+		return callbackAlert(t.text("report_not_admin", map[string]string{"chat": report.ChatTitle}))
+	}
+	if action != reportDismiss {
+		if !right.granted(member) {
+			return callbackAlert(t.text("report_no_right", rightVars))
+		}
+		self, err := chatMember(bot, chatID, bot.Self.ID)
+		if err != nil {
+			log.Errorf("Bot huquqlarini tekshirishda xatolik: %v", err)
+			return callbackAlert(t.text("admin_check_failed", nil))
+		}
+		if !right.granted(self) {
+			return callbackAlert(t.text("mod_bot_no_right", rightVars))
+		}
+	}
+
+	// Shikoyat chora ko'rilishidan oldin band qilinadi, shunda ikki admin bir vaqtda bossa chora bir marta ko'riladi
+	report, err = h.reports.Claim(chatID, messageID, action, callback.From.ID, userLabel(callback.From))
+	switch {
+	case errors.Is(err, reports.ErrHandled):
+		h.refreshReport(bot, report, log)
+		return callbackToast(t.text("report_already_handled", nil))
+	case err != nil:
+		log.Errorf("Shikoyatni saqlashda xatolik: %v", err)
+		return callbackAlert(t.text("report_failed", nil))
+	}
+	if err := h.applyReport(bot, report, callback.From, log); err != nil {
+		log.Errorf("Shikoyat bo'yicha %s chorasi bajarilmadi: %v", action, err)
+		if err := h.reports.Release(chatID, messageID); err != nil {
+			log.Errorf("Shikoyatni qayta ochishda xatolik: %v", err)
+		}
+		return callbackAlert(t.text("mod_failed", map[string]string{"error": err.Error()}))
+	}
+
+	log.Infof("%d admin %d guruhdagi %d xabar haqidagi shikoyatni ko'rib chiqdi: %s", callback.From.ID, chatID, messageID, action)
+	h.refreshReport(bot, report, log)
+	return callbackToast(t.text("report_action_"+action, map[string]string{"duration": t.duration(h.config.ReportSettings().MuteDuration)}))
+}
+
+// applyReport admin tanlagan chorani shikoyat qilingan xabar muallifiga nisbatan asl guruhda bajaradi
+// Xabarni o'chirishdan boshqa choralarda xabar imkon qadar o'chiriladi
+func (h *CommandHandler) applyReport(bot *tgbotapi.BotAPI, report reports.Report, admin *tgbotapi.User, log *logger.Logger) error {
+	chat := &tgbotapi.Chat{ID: report.ChatID, Title: report.ChatTitle, UserName: report.ChatUserName}
+	author := &tgbotapi.User{ID: report.AuthorID, FirstName: report.AuthorName, UserName: report.AuthorUserName}
+	entry := modlog.Entry{Source: modlog.SourceReport, Reason: report.Reason, MessageID: report.MessageID}
+
+	switch report.Action {
+	case reportDismiss:
+		return nil
+	case reportDelete:
+		if _, err := bot.Request(tgbotapi.NewDeleteMessage(report.ChatID, report.MessageID)); err != nil {
+			return err
+		}
+		entry.Action = modlog.Delete
+	case reportWarn:
+		t := h.translator(chat, author)
+		reason := report.Reason
+		if reason == "" {
+			reason = t.text("warn_no_reason", nil)
+		}
+		// Ogohlantirish jurnalga warnMember ichida yoziladi
+		parts, err := h.warnMember(bot, t, chat, author, admin, modlog.SourceReport, reason, log)
+		if err != nil {
+			return err
+		}
+		deleteMessage(bot, report.ChatID, report.MessageID, log)
+		sendMessage(bot, report.ChatID, 0, joinMessages(parts...), log)
+		return nil
+	case reportMute:
+		duration := h.config.ReportSettings().MuteDuration
+		if err := muteMember(bot, report.ChatID, report.AuthorID, time.Now().Add(duration)); err != nil {
+			return err
+		}
+		deleteMessage(bot, report.ChatID, report.MessageID, log)
+		entry.Action, entry.Duration = modlog.Mute, duration
+	case reportBan:
+		if err := banMember(bot, report.ChatID, report.AuthorID); err != nil {
+			return err
+		}
+		deleteMessage(bot, report.ChatID, report.MessageID, log)
+		entry.Action = modlog.Ban
+	default:
+		return errors.New("noma'lum chora: " + report.Action)
+	}
+	h.recordModeration(bot, chat, admin, author, entry, log)
+	return nil
+}
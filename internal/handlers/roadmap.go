@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/roadmap"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// roadmapCallback yo'l xaritasi menyusi tugmalari nomi
+// Ko'rinishlari: "roadmap:menu", "roadmap:s:<bo'lim>", "roadmap:t:<bo'lim>:<mavzu>" (belgilash), "roadmap:r:<bo'lim>:<mavzu>" (manbalar)
+// Argumentsiz "roadmap" tugmasi menyuni (guruhda umumiy ko'rinishni) yangi xabar sifatida yuboradi
+const roadmapCallback = "roadmap"
+
+// roadmapStartPayload guruhdagi /roadmap dan shaxsiy chatdagi menyuga o'tish havolasi parametri
+const roadmapStartPayload = "roadmap"
+
+// progressBarWidth yutuqlar chizig'i uzunligi
+const progressBarWidth = 10
+
+// progressBar belgilangan mavzular ulushini chiziq va foiz ko'rinishida qaytaradi
+func progressBar(done, total int) string {
+	filled, percent := 0, 0
+	if total > 0 {
+		filled = done * progressBarWidth / total
+		percent = done * 100 / total
+	}
+	return fmt.Sprintf("%s%s %d%%", strings.Repeat("▰", filled), strings.Repeat("▱", progressBarWidth-filled), percent)
+}
+
+// handleRoadmap yo'l xaritasini ko'rsatadi
+// Shaxsiy chatda mavzularni belgilash mumkin bo'lgan interaktiv menyu, guruhda esa umumiy ko'rinish
+// va shaxsiy chatga o'tish havolasi yuboriladi
+func (h *CommandHandler) handleRoadmap(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	if h.roadmap == nil || !message.Chat.IsPrivate() || message.From == nil {
+		h.sendRoadmapSummary(bot, t, message.Chat, message.From, log)
+		return
+	}
+	h.sendRoadmapMenu(bot, t, message.Chat.ID, message.From.ID, log)
+}
+
+// sendRoadmapSummary yo'l xaritasining umumiy ko'rinishini yuboradi
+// Interaktiv yo'l xaritasi mavjud bo'lsa shaxsiy chatga o'tish tugmasi qo'shiladi
+func (h *CommandHandler) sendRoadmapSummary(bot *tgbotapi.BotAPI, t *translator, chat *tgbotapi.Chat, user *tgbotapi.User, log *logger.Logger) {
+	text := t.message("roadmap", contentVars(bot, chat, user))
+	msg := tgbotapi.NewMessage(chat.ID, text.Text)
+	msg.ParseMode = text.ParseMode
+	if h.roadmap != nil {
+		link := h.StartLink(bot, roadmapStartPayload, "")
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonURL(t.text("roadmap_button", nil), link)),
+		)
+	}
+	if _, err := bot.Send(msg); err != nil {
+		log.Error("Yo'l xaritasini yuborishda xatolik:", err)
+	}
+}
+
+// sendRoadmapMenu foydalanuvchiga yo'l xaritasi bo'limlari menyusini yuboradi
+func (h *CommandHandler) sendRoadmapMenu(bot *tgbotapi.BotAPI, t *translator, chatID, userID int64, log *logger.Logger) {
+	progress, err := h.progress.Get(userID)
+	if err != nil {
+		log.Errorf("Yo'l xaritasi yutuqlarini o'qishda xatolik: %v", err)
+		sendMessage(bot, chatID, 0, t.message("roadmap_failed", content.Vars{}), log)
+		return
+	}
+
+	text, markup := h.roadmapMenu(t, progress)
+	msg := tgbotapi.NewMessage(chatID, text.Text)
+	msg.ParseMode = text.ParseMode
+	msg.ReplyMarkup = markup
+	if _, err := bot.Send(msg); err != nil {
+		log.Error("Yo'l xaritasi menyusini yuborishda xatolik:", err)
+	}
+}
+
+// roadmapMenu umumiy yutuqlar va bo'limlar tugmalaridan iborat menyu
+func (h *CommandHandler) roadmapMenu(t *translator, progress roadmap.Progress) (content.Message, tgbotapi.InlineKeyboardMarkup) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	done, total := 0, 0
+	for _, s := range h.roadmap.Sections() {
+		n := progress.Count(s)
+		done += n
+		total += len(s.Items)
+
+		mark := "▫️"
+		if n == len(s.Items) {
+			mark = "✅"
+		}
+		label := fmt.Sprintf("%s %d. %s · %d/%d", mark, s.ID, t.uzText(s.Title), n, len(s.Items))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, h.CallbackData(roadmapCallback, "s", strconv.Itoa(s.ID))),
+		))
+	}
+
+	complete := ""
+	if done == total {
+		complete = "1"
+	}
+	text := t.message("roadmap_menu", content.Vars{Args: map[string]string{
+		"done":     strconv.Itoa(done),
+		"total":    strconv.Itoa(total),
+		"bar":      progressBar(done, total),
+		"complete": complete,
+	}})
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// roadmapSection bo'lim mavzulari ro'yxati: har bir mavzu uchun belgilash va manbalar tugmalari
+func (h *CommandHandler) roadmapSection(t *translator, section roadmap.Section, progress roadmap.Progress) (content.Message, tgbotapi.InlineKeyboardMarkup) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, item := range section.Items {
+		mark := "⬜"
+		if progress.Has(section.ID, item.ID) {
+			mark = "✅"
+		}
+		row := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			mark+" "+t.uzText(item.Title),
+			h.CallbackData(roadmapCallback, "t", strconv.Itoa(section.ID), item.ID),
+		))
+		if len(item.Resources) > 0 {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("📚", h.CallbackData(roadmapCallback, "r", strconv.Itoa(section.ID), item.ID)))
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(t.text("roadmap_back", nil), h.CallbackData(roadmapCallback, "menu"))))
+
+	done := progress.Count(section)
+	text := t.message("roadmap_section", content.Vars{Args: map[string]string{
+		"n":       strconv.Itoa(section.ID),
+		"section": t.uzText(section.Title),
+		"done":    strconv.Itoa(done),
+		"total":   strconv.Itoa(len(section.Items)),
+		"bar":     progressBar(done, len(section.Items)),
+	}})
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// roadmapResources mavzu bo'yicha tanlangan manbalar havolalari
+func (h *CommandHandler) roadmapResources(t *translator, section roadmap.Section, item roadmap.Item) (content.Message, tgbotapi.InlineKeyboardMarkup) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, res := range item.Resources {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonURL("🔗 "+res.Title, res.URL)))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+		t.text("roadmap_back", nil),
+		h.CallbackData(roadmapCallback, "s", strconv.Itoa(section.ID)),
+	)))
+
+	text := t.message("roadmap_resources", content.Vars{Args: map[string]string{
+		"n":       strconv.Itoa(section.ID),
+		"section": t.uzText(section.Title),
+		"item":    t.uzText(item.Title),
+	}})
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleRoadmapCallback yo'l xaritasi menyusidagi tugmalarni qayta ishlaydi va menyu xabarini yangilaydi
+// Yutuqlar tugmani bosgan foydalanuvchiga tegishli, shuning uchun menyu faqat shaxsiy chatda ishlaydi
+func (h *CommandHandler) handleRoadmapCallback(ctx context.Context, bot *tgbotapi.BotAPI, callback *tgbotapi.CallbackQuery, args []string, log *logger.Logger) CallbackResult {
+	if callback.Message == nil || callback.From == nil {
+		return CallbackResult{}
+	}
+	t := translatorFrom(ctx)
+	if len(args) == 0 || h.roadmap == nil {
+		if h.roadmap != nil && callback.Message.Chat.IsPrivate() {
+			h.sendRoadmapMenu(bot, t, callback.Message.Chat.ID, callback.From.ID, log)
+		} else {
+			h.sendRoadmapSummary(bot, t, callback.Message.Chat, callback.From, log)
+		}
+		return CallbackResult{}
+	}
+	if !callback.Message.Chat.IsPrivate() {
+		return callbackToast(t.text("roadmap_private_only", nil))
+	}
+
+	progress, err := h.progress.Get(callback.From.ID)
+	if err != nil {
+		log.Errorf("Yo'l xaritasi yutuqlarini o'qishda xatolik: %v", err)
+		return callbackToast(t.text("roadmap_failed", nil))
+	}
+
+	// Tugma eski bo'lsa (bo'lim yoki mavzu yo'l xaritasidan olib tashlangan) asosiy menyu ko'rsatiladi
+	text, markup := h.roadmapMenu(t, progress)
+	if len(args) >= 2 {
+		id, _ := strconv.Atoi(args[1])
+		if section, ok := h.roadmap.Section(id); ok {
+			text, markup = h.roadmapSection(t, section, progress)
+
+			var item roadmap.Item
+			found := false
+			if len(args) == 3 {
+				item, found = section.Item(args[2])
+			}
+			switch {
+			case found && args[0] == "t":
+				if progress, err = h.progress.Toggle(callback.From.ID, section.ID, item.ID); err != nil {
+					log.Errorf("Yo'l xaritasi yutuqlarini saqlashda xatolik: %v", err)
+					return callbackToast(t.text("roadmap_failed", nil))
+				}
+				text, markup = h.roadmapSection(t, section, progress)
+			case found && args[0] == "r":
+				text, markup = h.roadmapResources(t, section, item)
+			}
+		}
+	}
+	return callbackEdit(text, markup)
+}
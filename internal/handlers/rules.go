@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"tg-bot/internal/content"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// rulesSetting guruh adminlari belgilagan qoidalar saqlanadigan chat sozlamasi
+const rulesSetting = "rules"
+
+// maxRulesLength guruh qoidalari uzunligi, sarlavha bilan birga bitta xabarga sig'ishi kerak
+const maxRulesLength = 3500
+
+// rulesMessage guruh qoidalari xabarini yaratadi
+// Adminlar /setrules orqali o'z qoidalarini belgilagan bo'lsa ular, aks holda hamjamiyatning umumiy qoidalari qaytariladi
+func (h *CommandHandler) rulesMessage(t *translator, bot *tgbotapi.BotAPI, chat *tgbotapi.Chat, user *tgbotapi.User) content.Message {
+	vars := contentVars(bot, chat, user)
+	if chat != nil && !chat.IsPrivate() {
+		if rules, err := h.store.GetSetting(chat.ID, rulesSetting); err == nil && rules != "" {
+			vars.Args = map[string]string{"rules": rules}
+			return t.message("rules_custom", vars)
+		}
+	}
+	return t.message("rules", vars)
+}
+
+// handleRules shu guruh qoidalarini ko'rsatadi
+func (h *CommandHandler) handleRules(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	sendMessage(bot, message.Chat.ID, 0, h.rulesMessage(t, bot, message.Chat, message.From), log)
+}
+
+// setRulesDialog /setrules matnsiz yuborilganda qoidalarni alohida xabar sifatida so'raydigan suhbat
+const setRulesDialog = "setrules"
+
+// registerRulesDialog qoidalarni so'rash suhbatini ro'yxatdan o'tkazadi
+func (h *CommandHandler) registerRulesDialog() {
+	h.RegisterDialog(DialogSpec{
+		Name:  setRulesDialog,
+		Steps: []DialogStep{{Name: "rules", Prompt: "setrules_prompt", Validate: validateRules}},
+		Done: func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, data map[string]string, log *logger.Logger) {
+			h.saveRules(translatorFrom(ctx), bot, message, data["rules"], log)
+		},
+	})
+}
+
+// validateRules qoidalar matni bo'sh emas va bitta xabarga sig'ishini tekshiradi
+func validateRules(t *translator, message *tgbotapi.Message) (string, string) {
+	rules := strings.TrimSpace(message.Text)
+	switch {
+	case rules == "":
+		return "", t.text("dialog_text_only", nil)
+	case utf8.RuneCountInString(rules) > maxRulesLength:
+		return "", t.text("setrules_too_long", map[string]string{"max": strconv.Itoa(maxRulesLength)})
+	}
+	return rules, ""
+}
+
+// handleSetRules guruh qoidalarini belgilaydi (faqat adminlar uchun)
+// Qoidalar buyruqdan keyin, javob berilgan xabar matni sifatida yoki bot so'raganidan keyin alohida xabarda beriladi,
+// "reset" umumiy qoidalarga qaytaradi
+func (h *CommandHandler) handleSetRules(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	rules := strings.TrimSpace(message.CommandArguments())
+	if rules == "" && message.ReplyToMessage != nil {
+		rules = strings.TrimSpace(message.ReplyToMessage.Text)
+	}
+
+	switch {
+	case rules == "":
+		h.StartDialog(ctx, bot, message, setRulesDialog, nil, log)
+		return
+	case strings.EqualFold(rules, "reset"):
+		rules = ""
+	case utf8.RuneCountInString(rules) > maxRulesLength:
+		t.reply(bot, message, "setrules_too_long", content.Vars{Args: map[string]string{"max": strconv.Itoa(maxRulesLength)}}, log)
+		return
+	}
+	h.saveRules(t, bot, message, rules, log)
+}
+
+// saveRules guruh qoidalarini saqlaydi, bo'sh matn umumiy qoidalarga qaytaradi
+func (h *CommandHandler) saveRules(t *translator, bot *tgbotapi.BotAPI, message *tgbotapi.Message, rules string, log *logger.Logger) {
+	if err := h.store.SetSetting(message.Chat.ID, rulesSetting, rules); err != nil {
+		log.Errorf("Guruh qoidalarini saqlashda xatolik: %v", err)
+		t.reply(bot, message, "setrules_failed", content.Vars{}, log)
+		return
+	}
+
+	if rules == "" {
+		log.Infof("%d chatida qoidalar umumiy qoidalarga qaytarildi", message.Chat.ID)
+		t.reply(bot, message, "setrules_reset", content.Vars{}, log)
+		return
+	}
+	log.Infof("%d chatida yangi qoidalar belgilandi", message.Chat.ID)
+	t.reply(bot, message, "setrules_done", content.Vars{}, log)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/sandbox"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleRun javob berilgan xabardagi Go dasturini sandbox ichida bajaradi
+// Kod avval /vet kabi tekshiriladi, keyin navbatga qo'yiladi. Natija tayyor bo'lgach buyruqqa javob sifatida yuboriladi,
+// shuning uchun dastur bajarilayotganda chat ishchisi band bo'lib qolmaydi
+func (h *CommandHandler) handleRun(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	code := snippetCode(message)
+	if code == "" {
+		t.reply(bot, message, "run_usage", content.Vars{}, log)
+		return
+	}
+
+	program, problems, err := h.snippets.Program(code)
+	if err != nil {
+		h.replySnippetError(t, bot, message, err, log)
+		return
+	}
+	if len(problems) > 0 {
+		t.reply(bot, message, "vet_problems", content.Vars{Args: map[string]string{
+			"count":    strconv.Itoa(len(problems)),
+			"problems": joinProblems(problems),
+		}}, log)
+		return
+	}
+
+	userID := message.Chat.ID
+	if message.From != nil {
+		userID = message.From.ID
+	}
+	chatID, replyTo := message.Chat.ID, message.MessageID
+	position, err := h.runner.Submit(userID, program, func(result sandbox.Result) {
+		sendMessage(bot, chatID, replyTo, runResultMessage(t, result), log)
+	})
+
+	var denied *sandbox.ImportError
+	var quota *sandbox.QuotaError
+	switch {
+	case err == nil:
+		if position > 0 {
+			t.reply(bot, message, "run_queued", content.Vars{Args: map[string]string{"position": strconv.Itoa(position)}}, log)
+		}
+	case errors.As(err, &denied):
+		t.reply(bot, message, "run_import_denied", content.Vars{Args: map[string]string{"imports": strings.Join(denied.Denied, ", ")}}, log)
+	case errors.As(err, &quota):
+		wait := quota.RetryAfter.Round(time.Second)
+		if wait > time.Minute {
+			wait = wait.Round(time.Minute)
+		}
+		t.reply(bot, message, "run_quota", content.Vars{Args: map[string]string{"wait": t.duration(wait)}}, log)
+	case errors.Is(err, sandbox.ErrPending):
+		t.reply(bot, message, "run_pending", content.Vars{}, log)
+	case errors.Is(err, sandbox.ErrQueueFull):
+		t.reply(bot, message, "run_queue_full", content.Vars{}, log)
+	default:
+		log.Errorf("Dasturni navbatga qo'yishda xatolik: %v", err)
+		t.reply(bot, message, "run_failed", content.Vars{}, log)
+	}
+}
+
+// runResultMessage dastur natijasini foydalanuvchi tilida tayyorlaydi
+func runResultMessage(t *translator, result sandbox.Result) content.Message {
+	if result.Err != nil {
+		return t.message("run_failed", content.Vars{})
+	}
+	if result.Stage == sandbox.StageBuild {
+		return t.message("run_build_failed", content.Vars{Args: map[string]string{"output": result.Output}})
+	}
+
+	elapsed := result.Elapsed.Round(time.Millisecond).String()
+	var status string
+	switch {
+	case result.TimedOut:
+		status = t.text("run_status_timeout", nil)
+	case result.Signal != "":
+		status = t.text("run_status_signal", map[string]string{"signal": result.Signal})
+	case result.ExitCode != 0:
+		status = t.text("run_status_exit", map[string]string{"code": strconv.Itoa(result.ExitCode), "elapsed": elapsed})
+	default:
+		status = t.text("run_status_ok", map[string]string{"elapsed": elapsed})
+	}
+
+	truncated := ""
+	if result.Truncated {
+		truncated = t.text("run_truncated", nil)
+	}
+
+	return t.message("run_result", content.Vars{Args: map[string]string{
+		"output":    strings.TrimRight(result.Output, "\n"),
+		"status":    status,
+		"truncated": truncated,
+	}})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/snippet"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleFmt javob berilgan xabardagi (yoki buyruqdan keyingi) Go kodini formatlab qaytaradi
+func (h *CommandHandler) handleFmt(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	code := snippetCode(message)
+	if code == "" {
+		t.reply(bot, message, "fmt_usage", content.Vars{}, log)
+		return
+	}
+
+	formatted, err := h.snippets.Format(code)
+	if err != nil {
+		h.replySnippetError(t, bot, message, err, log)
+		return
+	}
+	if strings.TrimSpace(formatted) == strings.TrimSpace(code) {
+		t.reply(bot, message, "fmt_unchanged", content.Vars{}, log)
+		return
+	}
+
+	t.reply(bot, message, "fmt_result", content.Vars{Args: map[string]string{"code": strings.TrimRight(formatted, "\n")}}, log)
+}
+
+// handleVet Go kodini go/types yordamida tekshiradi va topilgan xatolarni qator raqamlari bilan yuboradi
+func (h *CommandHandler) handleVet(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+
+	code := snippetCode(message)
+	if code == "" {
+		t.reply(bot, message, "vet_usage", content.Vars{}, log)
+		return
+	}
+
+	problems, err := h.snippets.Vet(code)
+	if err != nil {
+		h.replySnippetError(t, bot, message, err, log)
+		return
+	}
+	if len(problems) == 0 {
+		t.reply(bot, message, "vet_ok", content.Vars{}, log)
+		return
+	}
+
+	t.reply(bot, message, "vet_problems", content.Vars{Args: map[string]string{
+		"count":    strconv.Itoa(len(problems)),
+		"problems": joinProblems(problems),
+	}}, log)
+}
+
+// replySnippetError kod bilan ishlashdagi xatolikni foydalanuvchiga tushuntiradi
+func (h *CommandHandler) replySnippetError(t *translator, bot *tgbotapi.BotAPI, message *tgbotapi.Message, err error, log *logger.Logger) {
+	var syntax *snippet.SyntaxError
+	switch {
+	case errors.As(err, &syntax):
+		t.reply(bot, message, "snippet_syntax", content.Vars{Args: map[string]string{"problems": joinProblems(syntax.Problems)}}, log)
+	case errors.Is(err, snippet.ErrEmpty):
+		t.reply(bot, message, "fmt_usage", content.Vars{}, log)
+	case errors.Is(err, snippet.ErrTooLarge):
+		t.reply(bot, message, "snippet_too_large", content.Vars{Args: map[string]string{"limit": strconv.Itoa(h.snippets.Limits().MaxBytes)}}, log)
+	case errors.Is(err, snippet.ErrTimeout):
+		log.Warnf("%d chatidagi kod parchasi vaqt chegarasiga sig'madi", message.Chat.ID)
+		t.reply(bot, message, "snippet_timeout", content.Vars{}, log)
+	case errors.Is(err, snippet.ErrBusy):
+		t.reply(bot, message, "snippet_busy", content.Vars{}, log)
+	default:
+		log.Errorf("Kod parchasini qayta ishlashda xatolik: %v", err)
+		t.reply(bot, message, "snippet_failed", content.Vars{}, log)
+	}
+}
+
+// joinProblems muammolarni har birini alohida qatorda birlashtiradi
+func joinProblems(problems []snippet.Problem) string {
+	lines := make([]string, len(problems))
+	for i, p := range problems {
+		lines[i] = p.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// snippetCode buyruq qo'llanadigan Go kodini topadi
+// Avval buyruq xabarining o'zidagi kod bloki yoki argumentlari, keyin javob berilgan xabardagi kod bloklari,
+// ular bo'lmasa javob berilgan xabarning butun matni olinadi
+func snippetCode(message *tgbotapi.Message) string {
+	if code := codeBlocks(message.Text, message.Entities); code != "" {
+		return code
+	}
+	if args := stripFences(message.CommandArguments()); args != "" {
+		return args
+	}
+
+	reply := message.ReplyToMessage
+	if reply == nil {
+		return ""
+	}
+	if code := codeBlocks(reply.Text, reply.Entities); code != "" {
+		return code
+	}
+	if code := codeBlocks(reply.Caption, reply.CaptionEntities); code != "" {
+		return code
+	}
+	if reply.Text != "" {
+		return stripFences(reply.Text)
+	}
+	return stripFences(reply.Caption)
+}
+
+// codeBlocks xabardagi "pre" turidagi barcha bloklarni bo'sh qator bilan birlashtirib qaytaradi
+// Telegram entity pozitsiyalarini UTF-16 birliklarida beradi, shuning uchun matn avval UTF-16 ga o'giriladi
+func codeBlocks(text string, entities []tgbotapi.MessageEntity) string {
+	var encoded []uint16
+	var blocks []string
+	for _, e := range entities {
+		if e.Type != "pre" {
+			continue
+		}
+		if encoded == nil {
+			encoded = utf16.Encode([]rune(text))
+		}
+		if e.Offset < 0 || e.Length <= 0 || e.Offset+e.Length > len(encoded) {
+			continue
+		}
+		blocks = append(blocks, string(utf16.Decode(encoded[e.Offset:e.Offset+e.Length])))
+	}
+	return strings.TrimSpace(strings.Join(blocks, "\n\n"))
+}
+
+// stripFences Markdown ``` belgilari bilan o'ralgan kodni ulardan tozalaydi
+// Telegram ularni kod blokiga aylantirmagan holatlar uchun (masalan, formatlashsiz nusxa ko'chirilgan matn)
+func stripFences(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(text, "```")
+	// Birinchi qatordagi til nomi (```go) ham olib tashlanadi
+	if first, rest, ok := strings.Cut(text, "\n"); ok && !strings.ContainsAny(first, " \t(){};=") {
+		text = rest
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// /start havolasi parametri "<nom>", "<nom>_<qiymat>" yoki imzolangan "<nom>_<qiymat>_<imzo>" ko'rinishida bo'ladi
+// Telegram parametrda faqat A-Z, a-z, 0-9, _ va - belgilariga va 64 belgigacha uzunlikka ruxsat beradi,
+// shuning uchun nom va qiymatda _ bo'lmaydi, imzo esa qisqartirilgan HMAC-SHA256 ning hex ko'rinishi
+const (
+	payloadSeparator = "_"
+	payloadSigLength = 16 // Imzo uzunligi (hex belgilarda)
+	maxPayloadLength = 64
+)
+
+// payloadValuePattern havola qiymati uchun ruxsat etilgan belgilar
+var payloadValuePattern = regexp.MustCompile(`^[A-Za-z0-9-]{1,40}$`)
+
+// errStartSignature imzolangan /start parametrining imzosi yo'q yoki soxta
+var errStartSignature = errors.New("/start parametri imzosi noto'g'ri")
+
+// referralsBucket foydalanuvchi botga qaysi taklif kodi orqali kelgani yoziladigan bucket (kalit - foydalanuvchi ID si)
+const referralsBucket = "referrals"
+
+// StartFunction /start havolasi parametri bilan kelgan foydalanuvchini qayta ishlaydi
+// value parametrdagi qiymat (bo'lmasa bo'sh satr), imzolangan parametrlarda imzo allaqachon tekshirilgan bo'ladi
+type StartFunction func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, value string, log *logger.Logger)
+
+// StartSpec /start havolasi parametrining tavsifi
+type StartSpec struct {
+	Name   string // Parametr nomi, masalan welcome yoki rules
+	Signed bool   // true bo'lsa qiymat imzolangan bo'lishi kerak, imzosiz yoki soxta havolalar oddiy /start kabi qayta ishlanadi
+}
+
+// startPayload ro'yxatdan o'tgan /start parametri
+type startPayload struct {
+	spec StartSpec
+	fn   StartFunction
+}
+
+// HandleStart /start havolasi parametri uchun qayta ishlovchini ro'yxatdan o'tkazadi
+func (h *CommandHandler) HandleStart(spec StartSpec, fn StartFunction) {
+	if h.payloads == nil {
+		h.payloads = make(map[string]startPayload)
+	}
+	h.payloads[spec.Name] = startPayload{spec: spec, fn: fn}
+}
+
+// payloadSignature nom va qiymat uchun imzoni hisoblaydi
+func (h *CommandHandler) payloadSignature(name, value string) string {
+	mac := hmac.New(sha256.New, []byte(h.config.StartSecret()))
+	mac.Write([]byte(name + payloadSeparator + value))
+	return hex.EncodeToString(mac.Sum(nil))[:payloadSigLength]
+}
+
+// StartLink bot bilan shaxsiy chatni ochadigan va /start ga parametr uzatadigan havola yaratadi
+// Parametr imzolangan deb ro'yxatdan o'tgan bo'lsa qiymatga imzo qo'shiladi
+func (h *CommandHandler) StartLink(bot *tgbotapi.BotAPI, name, value string) string {
+	payload := name
+	if value != "" {
+		payload += payloadSeparator + value
+		if h.payloads[name].spec.Signed {
+			payload += payloadSeparator + h.payloadSignature(name, value)
+		}
+	}
+	return "https://t.me/" + bot.Self.UserName + "?start=" + payload
+}
+
+// parsePayload /start parametrini nom, qiymat va imzoga ajratadi
+func parsePayload(payload string) (name, value, sig string, ok bool) {
+	if payload == "" || len(payload) > maxPayloadLength {
+		return "", "", "", false
+	}
+	parts := strings.Split(payload, payloadSeparator)
+	switch len(parts) {
+	case 1:
+		return parts[0], "", "", true
+	case 2:
+		name, value = parts[0], parts[1]
+	case 3:
+		name, value, sig = parts[0], parts[1], parts[2]
+	default:
+		return "", "", "", false
+	}
+	if !payloadValuePattern.MatchString(value) {
+		return "", "", "", false
+	}
+	return name, value, sig, true
+}
+
+// handleStart /start buyrug'ini qayta ishlaydi
+// Havola parametri bo'lsa u ro'yxatdan o'tgan qayta ishlovchiga uzatiladi, aks holda salomlashish xabari yuboriladi
+func (h *CommandHandler) handleStart(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	payload := strings.TrimSpace(message.CommandArguments())
+	if payload == "" {
+		h.contentCommand("start")(ctx, bot, message, log)
+		return
+	}
+
+	route, value, err := h.resolveStart(payload)
+	switch {
+	case errors.Is(err, errStartSignature):
+		log.Warnf("/start parametri rad etildi (foydalanuvchi %d): %v", message.Chat.ID, err)
+	case err != nil:
+		log.Debugf("/start parametri rad etildi: %v", err)
+	default:
+		route.fn(ctx, bot, message, value, log)
+		return
+	}
+	h.contentCommand("start")(ctx, bot, message, log)
+}
+
+// resolveStart /start parametrini ro'yxatdan o'tgan qayta ishlovchi va qiymatga aylantiradi
+// Imzolangan parametrning imzosi qiymatga mos kelmasa xato qaytariladi
+func (h *CommandHandler) resolveStart(payload string) (startPayload, string, error) {
+	name, value, sig, ok := parsePayload(payload)
+	route, exists := h.payloads[name]
+	switch {
+	case !ok || !exists:
+		return startPayload{}, "", fmt.Errorf("noma'lum parametr %q", payload)
+	case route.spec.Signed && value != "" && !hmac.Equal([]byte(sig), []byte(h.payloadSignature(name, value))):
+		return startPayload{}, "", fmt.Errorf("%w: %q", errStartSignature, payload)
+	case !route.spec.Signed && sig != "":
+		return startPayload{}, "", fmt.Errorf("imzolanmaydigan parametrda imzo bor: %q", payload)
+	}
+	return route, value, nil
+}
+
+// registerStartPayloads /start havolalari parametrlarini ro'yxatdan o'tkazadi
+func (h *CommandHandler) registerStartPayloads() {
+	// WELCOME - guruhdagi kutib olish xabaridan kelgan yangi a'zo, qiymat guruh ID si
+	h.HandleStart(StartSpec{Name: "welcome", Signed: true}, h.startWelcome)
+
+	// RULES - aniq guruh qoidalari, qiymat guruh ID si
+	h.HandleStart(StartSpec{Name: "rules", Signed: true}, h.startRules)
+
+	// ROADMAP - guruhdagi /roadmap dan shaxsiy chatdagi interaktiv yo'l xaritasiga o'tish
+	h.HandleStart(StartSpec{Name: roadmapStartPayload}, func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, _ string, log *logger.Logger) {
+		h.handleRoadmap(ctx, bot, message, log)
+	})
+
+	// QUIZ - aniq viktorina savoli, qiymat savol ID si
+	h.HandleStart(StartSpec{Name: "quiz"}, h.startQuiz)
+
+	// REF - taklif kodi, foydalanuvchi qaysi havola orqali kelgani yozib qo'yiladi
+	h.HandleStart(StartSpec{Name: "ref"}, h.startReferral)
+}
+
+// startChat imzolangan qiymatdagi guruhni ombordan o'qiydi
+func (h *CommandHandler) startChat(value string) (*tgbotapi.Chat, bool) {
+	chatID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	chat := &tgbotapi.Chat{ID: chatID}
+	if saved, err := h.store.GetChat(chatID); err == nil {
+		chat.Title = saved.Title
+	}
+	return chat, true
+}
+
+// startWelcome guruhdan kelgan yangi a'zoni kutib oladi va o'sha guruh qoidalarini ko'rsatadi
+func (h *CommandHandler) startWelcome(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, value string, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	chat, ok := h.startChat(value)
+	if !ok {
+		h.contentCommand("start")(ctx, bot, message, log)
+		return
+	}
+
+	greeting := t.message("start_welcome", contentVars(bot, chat, message.From))
+	sendMessage(bot, message.Chat.ID, 0, joinMessages(greeting, h.rulesMessage(t, bot, chat, message.From)), log)
+}
+
+// startRules ko'rsatilgan guruh qoidalarini yuboradi
+func (h *CommandHandler) startRules(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, value string, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	chat, ok := h.startChat(value)
+	if !ok {
+		h.contentCommand("rules")(ctx, bot, message, log)
+		return
+	}
+	sendMessage(bot, message.Chat.ID, 0, h.rulesMessage(t, bot, chat, message.From), log)
+}
+
+// startQuiz havolada ko'rsatilgan viktorina savolini yuboradi
+func (h *CommandHandler) startQuiz(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, value string, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	if h.quiz == nil {
+		h.contentCommand("start")(ctx, bot, message, log)
+		return
+	}
+	question, ok := h.quiz.Question(value)
+	if !ok {
+		t.reply(bot, message, "quiz_unknown", content.Vars{}, log)
+		return
+	}
+	if err := h.postQuestion(bot, t, message.Chat.ID, question, "quiz_question"); err != nil {
+		log.Errorf("Viktorina savolini yuborishda xatolik: %v", err)
+		t.reply(bot, message, "quiz_failed", content.Vars{}, log)
+	}
+}
+
+// startReferral foydalanuvchi botga birinchi marta kelgan taklif kodini yozib qo'yadi va salomlashadi
+// Keyingi havolalar avvalgi yozuvni o'zgartirmaydi
+func (h *CommandHandler) startReferral(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, value string, log *logger.Logger) {
+	if message.From != nil {
+		key := strconv.FormatInt(message.From.ID, 10)
+		if _, err := h.store.Get(referralsBucket, key); errors.Is(err, storage.ErrNotFound) {
+			if err := h.store.Put(referralsBucket, key, []byte(value+" "+time.Now().UTC().Format(time.RFC3339))); err != nil {
+				log.Warnf("Taklif kodini saqlashda xatolik: %v", err)
+			} else {
+				log.Infof("%d foydalanuvchi %q taklif kodi orqali keldi", message.From.ID, value)
+			}
+		}
+	}
+	h.contentCommand("start")(ctx, bot, message, log)
+}
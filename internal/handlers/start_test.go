@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePayload(t *testing.T) {
+	tests := []struct {
+		payload          string
+		name, value, sig string
+		ok               bool
+	}{
+		{payload: "rules", name: "rules", ok: true},
+		{payload: "roadmap_basics", name: "roadmap", value: "basics", ok: true},
+		{payload: "rules_-100123_ab12cd", name: "rules", value: "-100123", sig: "ab12cd", ok: true},
+		{payload: ""},
+		{payload: "rules_"},
+		{payload: "rules_a_b_c"},
+		{payload: "rules_bad.value"},
+		{payload: "rules_" + strings.Repeat("a", 41)},
+		{payload: strings.Repeat("a", maxPayloadLength+1)},
+	}
+	for _, tt := range tests {
+		name, value, sig, ok := parsePayload(tt.payload)
+		if name != tt.name || value != tt.value || sig != tt.sig || ok != tt.ok {
+			t.Errorf("parsePayload(%q) = %q, %q, %q, %v; want %q, %q, %q, %v",
+				tt.payload, name, value, sig, ok, tt.name, tt.value, tt.sig, tt.ok)
+		}
+	}
+}
+
+// startConfig faqat imzolash kalitini beruvchi sozlamalar
+type startConfig struct {
+	Config
+	secret string
+}
+
+func (c startConfig) StartSecret() string { return c.secret }
+
+func TestResolveStartSignature(t *testing.T) {
+	h := &CommandHandler{config: startConfig{secret: "test-secret"}}
+	h.registerStartPayloads()
+	sig := h.payloadSignature("rules", "-100123")
+	other := (&CommandHandler{config: startConfig{secret: "other-secret"}}).payloadSignature("rules", "-100123")
+
+	tests := []struct {
+		payload   string
+		value     string
+		ok        bool
+		forged    bool // Imzo xatosi kutiladi
+		routeName string
+	}{
+		{payload: "rules_-100123_" + sig, value: "-100123", ok: true, routeName: "rules"},
+		{payload: "rules", ok: true, routeName: "rules"},
+		{payload: "rules_-100999_" + sig, forged: true},
+		{payload: "rules_-100123_" + sig[:8], forged: true},
+		{payload: "rules_-100123_" + strings.ToUpper(sig), forged: true},
+		{payload: "rules_-100123_" + other, forged: true},
+		{payload: "rules_-100123", forged: true},
+		{payload: "welcome_-100123_" + sig, forged: true},
+		{payload: "quiz_5", value: "5", ok: true, routeName: "quiz"},
+		{payload: "quiz_5_" + sig},
+		{payload: "unknown_1"},
+	}
+	for _, tt := range tests {
+		route, value, err := h.resolveStart(tt.payload)
+		if (err == nil) != tt.ok || errors.Is(err, errStartSignature) != tt.forged {
+			t.Errorf("resolveStart(%q) error = %v, want ok %v, forged %v", tt.payload, err, tt.ok, tt.forged)
+			continue
+		}
+		if tt.ok && (value != tt.value || route.spec.Name != tt.routeName) {
+			t.Errorf("resolveStart(%q) = %s, %q; want %s, %q", tt.payload, route.spec.Name, value, tt.routeName, tt.value)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/i18n"
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ContentKeys handlerlar ishlatadigan matnlar kalitlari
+// Bot ishga tushganda va matnlar qayta yuklanganda bu kalitlarning barchasi asosiy tilda mavjudligi tekshiriladi
+var ContentKeys = []string{
+	"start", "rules", "about", "group", "roadmap", "useful",
+	"unknown_command", "welcome", "welcome_button",
+	"help_header", "help_scope_admins", "help_scope_group", "help_scope_private",
+	"only_groups", "only_private", "only_admins", "only_owners", "admin_check_failed", "rate_limited",
+	"warn", "warn_usage", "warn_no_bots", "warn_no_admins", "warn_no_reason", "warn_save_failed",
+	"warn_details", "warn_banned", "warn_ban_failed", "warn_muted", "warn_mute_failed",
+	"warns_read_failed", "warns_empty", "warns_header", "warns_item", "warns_mute_policy", "warns_ban_policy",
+	"unwarn_usage", "unwarn_bad_number", "unwarn_not_found", "unwarn_delete_failed", "unwarn_done",
+	"reload_failed", "reload_done",
+	"captcha_greeting", "captcha_button_task", "captcha_not_for_you", "captcha_expired", "captcha_wrong", "captcha_passed",
+	"captcha_question_go", "captcha_question_defer", "captcha_question_mascot", "captcha_question_make", "captcha_question_len", "captcha_question_main",
+	"duration_days", "duration_hours", "duration_minutes", "duration_seconds",
+	"lang_choose", "lang_choose_chat", "lang_changed", "lang_admins_only",
+	"release", "release_file", "release_source", "release_security", "release_newer", "release_prerelease_available",
+	"release_status_latest", "release_status_supported", "release_status_unsupported", "release_status_prerelease",
+	"release_not_ready", "version_usage", "version_not_found", "version_bad_query",
+	"subscribe_usage", "subscribe_done", "subscribe_failed", "unsubscribe_done",
+	"subscribe_status_off", "subscribe_status_stable", "subscribe_status_all",
+	"announce_release", "announce_prerelease", "announce_security",
+	"doc_symbol", "doc_usage", "doc_not_ready", "doc_not_found", "doc_did_you_mean", "doc_also",
+	"fmt_usage", "fmt_result", "fmt_unchanged", "vet_usage", "vet_ok", "vet_problems",
+	"snippet_syntax", "snippet_too_large", "snippet_timeout", "snippet_busy", "snippet_failed",
+	"run_usage", "run_queued", "run_import_denied", "run_quota", "run_pending", "run_queue_full", "run_failed",
+	"run_build_failed", "run_result", "run_status_ok", "run_status_exit", "run_status_signal", "run_status_timeout", "run_truncated",
+	"quiz_usage", "quiz_question", "quiz_daily_question", "quiz_correct", "quiz_wrong", "quiz_already", "quiz_expired", "quiz_unknown", "quiz_failed",
+	"quiz_top_chat", "quiz_top_global", "quiz_top_item", "quiz_top_you", "quiz_top_empty",
+	"quizdaily_usage", "quizdaily_set", "quizdaily_off", "quizdaily_bad_time", "quizdaily_status_on", "quizdaily_status_off",
+	"roadmap_button", "roadmap_menu", "roadmap_section", "roadmap_resources", "roadmap_back", "roadmap_private_only", "roadmap_failed",
+	"start_welcome", "rules_custom", "setrules_prompt", "setrules_done", "setrules_reset", "setrules_too_long", "setrules_failed",
+	"callback_unknown", "callback_expired",
+	"dialog_cancel_hint", "dialog_text_only", "dialog_expired", "dialog_cancelled", "dialog_none", "dialog_failed",
+	"antispam_filter_links", "antispam_filter_newcomer", "antispam_filter_forward", "antispam_filter_duplicate", "antispam_filter_rule",
+	"antispam_action_delete", "antispam_action_warn", "antispam_action_ban", "antispam_audit",
+	"flood_slow_down", "flood_muted", "flood_mute_failed", "flood_action_muted", "flood_action_mute_failed", "flood_audit",
+	"slowmode_usage", "slowmode_status_on", "slowmode_status_off", "slowmode_set", "slowmode_off", "slowmode_bad_interval", "slowmode_failed", "slowmode_wait",
+	"mod_right_restrict", "mod_right_delete", "mod_right_pin", "mod_caller_no_right", "mod_bot_not_admin", "mod_bot_no_right",
+	"mod_no_target", "mod_user_unknown", "mod_target_bot", "mod_target_yourself", "mod_target_admin", "mod_bad_duration", "mod_failed",
+	"mod_ban_done", "mod_mute_done", "mod_kick_done", "mod_unban_done", "mod_unmute_done",
+	"mod_purge_usage", "mod_purge_too_many", "mod_purge_done", "mod_pin_usage", "mod_unpin_done",
+	"report_usage", "report_target_bot", "report_target_yourself", "report_target_admin", "report_already", "report_already_handled",
+	"report_failed", "report_no_admins", "report_sent", "report_card", "report_handled", "report_not_admin", "report_no_right",
+	"report_button_delete", "report_button_warn", "report_button_mute", "report_button_ban", "report_button_dismiss",
+	"report_action_delete", "report_action_warn", "report_action_mute", "report_action_ban", "report_action_dismiss",
+	"modlog_usage", "modlog_failed", "modlog_empty", "modlog_empty_user", "modlog_header", "modlog_header_user", "modlog_item", "modlog_post",
+	"modlog_channel_status_on", "modlog_channel_status_off", "modlog_channel_set", "modlog_channel_off", "modlog_channel_bad",
+	"modlog_channel_not_admin", "modlog_channel_test",
+	"modlog_action_ban", "modlog_action_unban", "modlog_action_mute", "modlog_action_unmute", "modlog_action_kick", "modlog_action_warn",
+	"modlog_action_unwarn", "modlog_action_purge", "modlog_action_pin", "modlog_action_unpin", "modlog_action_delete",
+	"modlog_source_antispam", "modlog_source_flood", "modlog_source_captcha", "modlog_source_warns", "modlog_source_report",
+}
+
+// translator bitta so'rov uchun tanlangan tilda matnlarni tayyorlaydi
+type translator struct {
+	texts  *content.Store
+	locale i18n.Locale
+	log    *logger.Logger
+}
+
+// translatorKey kontekstda translator saqlanadigan kalit
+type translatorKey struct{}
+
+// withTranslator kontekstga so'rov tilidagi translatorni qo'shadi
+func withTranslator(ctx context.Context, t *translator) context.Context {
+	return context.WithValue(ctx, translatorKey{}, t)
+}
+
+// translatorFrom kontekstdagi translatorni qaytaradi
+// GetCommandHandler qaytargan buyruqlar kontekstida translator doim mavjud
+func translatorFrom(ctx context.Context) *translator {
+	if t, ok := ctx.Value(translatorKey{}).(*translator); ok {
+		return t
+	}
+	return nil
+}
+
+// message kalit bo'yicha matnni tayyorlaydi
+// Shablonda xatolik bo'lsa u log qilinadi va foydalanuvchiga umumiy xabar qaytariladi
+func (t *translator) message(key string, vars content.Vars) content.Message {
+	if t == nil || t.texts == nil {
+		return content.Message{Text: key}
+	}
+	msg, err := t.texts.Render(t.locale, key, vars)
+	if err != nil {
+		t.log.Errorf("%q matnini tayyorlashda xatolik (%s): %v", key, t.locale, err)
+		return content.Message{Text: "Kechirasiz, xabarni tayyorlashda xatolik yuz berdi."}
+	}
+	return msg
+}
+
+// text faqat qo'shimcha qiymatlar bilan tayyorlangan matnni qaytaradi
+func (t *translator) text(key string, args map[string]string) string {
+	return t.message(key, content.Vars{Args: args}).Text
+}
+
+// duration vaqt oralig'ini foydalanuvchi tilida tushunarli ko'rinishda qaytaradi
+func (t *translator) duration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return t.text("duration_days", map[string]string{"n": strconv.Itoa(int(d / (24 * time.Hour)))})
+	case d >= time.Hour && d%time.Hour == 0:
+		return t.text("duration_hours", map[string]string{"n": strconv.Itoa(int(d / time.Hour))})
+	case d >= time.Minute && d%time.Minute == 0:
+		return t.text("duration_minutes", map[string]string{"n": strconv.Itoa(int(d / time.Minute))})
+	default:
+		return t.text("duration_seconds", map[string]string{"n": strconv.Itoa(int(d / time.Second))})
+	}
+}
+
+// uzText faqat lotin yozuvida saqlanadigan o'zbekcha ma'lumotlarni (viktorina savollari, yo'l xaritasi) foydalanuvchi yozuviga moslaydi
+// Shablon argumentlari avtomatik transliteratsiya qilinmaydi, shuning uchun ular shu orqali o'tkaziladi
+func (t *translator) uzText(text string) string {
+	if t.locale == i18n.UzCyrl {
+		return i18n.ToCyrillic(text)
+	}
+	return text
+}
+
+// reply xabarga javob tariqasida kalit bo'yicha tayyorlangan matnni yuboradi
+func (t *translator) reply(bot *tgbotapi.BotAPI, message *tgbotapi.Message, key string, vars content.Vars, log *logger.Logger) {
+	sendMessage(bot, message.Chat.ID, message.MessageID, t.message(key, vars), log)
+}
+
+// joinMessages bir nechta matnni bo'sh qator bilan birlashtiradi
+// Natija birinchi matnning formatlash rejimida bo'ladi, oddiy matnli qismlar shu rejimga mos ekranlanadi
+func joinMessages(parts ...content.Message) content.Message {
+	if len(parts) == 0 {
+		return content.Message{}
+	}
+	result := parts[0]
+	for _, part := range parts[1:] {
+		text := part.Text
+		if part.ParseMode == content.ParseModePlain {
+			text = content.Escape(result.ParseMode, text)
+		}
+		result.Text += "\n\n" + text
+	}
+	return result
+}
+
+// sendMessage tayyor matnni uning formatlash rejimi bilan yuboradi
+// replyTo nol bo'lmasa, xabar o'sha xabarga javob sifatida yuboriladi
+func sendMessage(bot *tgbotapi.BotAPI, chatID int64, replyTo int, text content.Message, log *logger.Logger) {
+	msg := tgbotapi.NewMessage(chatID, text.Text)
+	msg.ParseMode = text.ParseMode
+	msg.ReplyToMessageID = replyTo
+	if _, err := bot.Send(msg); err != nil {
+		log.Error("Xabar yuborishda xatolik yuz berdi:", err)
+	}
+}
+
+// contentVars xabar yuboriladigan chat va foydalanuvchi asosida shablon o'zgaruvchilarini tayyorlaydi
+func contentVars(bot *tgbotapi.BotAPI, chat *tgbotapi.Chat, user *tgbotapi.User) content.Vars {
+	vars := content.Vars{BotUsername: bot.Self.UserName}
+	if chat != nil {
+		vars.ChatTitle = chat.Title
+	}
+	if user != nil {
+		vars.UserID = user.ID
+		vars.UserName = user.UserName
+		vars.FirstName = user.FirstName
+	}
+	return vars
+}
+
+// contentCommand fayldagi matnni yuboruvchi oddiy buyruq funksiyasini yaratadi
+func (h *CommandHandler) contentCommand(key string) CommandFunction {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+		text := translatorFrom(ctx).message(key, contentVars(bot, message.Chat, message.From))
+		sendMessage(bot, message.Chat.ID, 0, text, log)
+	}
+}
+
+// UnknownCommand noma'lum buyruq yuborgan foydalanuvchiga yordam xabarini yuboradi
+func (h *CommandHandler) UnknownCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := h.translator(message.Chat, message.From)
+	sendMessage(bot, message.Chat.ID, 0, t.message("unknown_command", contentVars(bot, message.Chat, message.From)), log)
+}
+
+// WelcomeMember guruhga yangi qo'shilgan a'zoni eslatib, hamjamiyat haqida bilish uchun botga taklif qiladi
+func (h *CommandHandler) WelcomeMember(bot *tgbotapi.BotAPI, chatID int64, user tgbotapi.User, log *logger.Logger) {
+	chat := &tgbotapi.Chat{ID: chatID}
+	if saved, err := h.store.GetChat(chatID); err == nil {
+		chat.Title = saved.Title
+	}
+	t := h.translator(chat, &user)
+
+	text := t.message("welcome", contentVars(bot, chat, &user))
+	msg := tgbotapi.NewMessage(chatID, text.Text)
+	msg.ParseMode = text.ParseMode
+
+	// Botga o'tish uchun Start tugmasi, havolada guruh qoidalarini ko'rsatish uchun imzolangan guruh ID si bor
+	startButton := tgbotapi.NewInlineKeyboardButtonURL(t.text("welcome_button", nil), h.StartLink(bot, "welcome", strconv.FormatInt(chatID, 10)))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(startButton),
+	)
+
+	if _, err := bot.Send(msg); err != nil {
+		log.Error("Yangi a'zoni kutib olish xabarini yuborishda xatolik:", err)
+	}
+}
+
+// handleReload matnlarni, viktorina savollarini va yo'l xaritasini fayllardan qayta yuklaydi (faqat bot egalari uchun)
+// Fayllarda xatolik bo'lsa avvalgi matnlar saqlanib qoladi va xatolik bot egasiga ko'rsatiladi
+func (h *CommandHandler) handleReload(ctx context.Context, bot *tgbotapi.BotAPI, message *tgbotapi.Message, log *logger.Logger) {
+	t := translatorFrom(ctx)
+	if err := h.texts.Reload(); err != nil {
+		log.Errorf("Matnlarni qayta yuklashda xatolik: %v", err)
+		t.reply(bot, message, "reload_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+		return
+	}
+	if h.quiz != nil {
+		if err := h.quiz.Reload(); err != nil {
+			log.Errorf("Viktorina savollarini qayta yuklashda xatolik: %v", err)
+			t.reply(bot, message, "reload_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+			return
+		}
+	}
+	if h.roadmap != nil {
+		if err := h.roadmap.Reload(); err != nil {
+			log.Errorf("Yo'l xaritasini qayta yuklashda xatolik: %v", err)
+			t.reply(bot, message, "reload_failed", content.Vars{Args: map[string]string{"error": err.Error()}}, log)
+			return
+		}
+	}
+
+	log.Infof("Matnlar %d foydalanuvchi tomonidan qayta yuklandi", message.From.ID)
+	t.reply(bot, message, "reload_done", content.Vars{Args: map[string]string{"count": strconv.Itoa(h.texts.Len())}}, log)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+
+	"tg-bot/internal/content"
+	"tg-bot/internal/i18n"
+)
+
+// cyrillicKeep kirill yozuviga o'girilgan matnda o'zgarishsiz qolishi kerak bo'lgan bo'laklar
+// Transliteratsiya yangi matnni buzsa, latinWords ro'yxatini to'ldirish o'rniga matnda {{`...`}} ishlatib, shu yerga misol qo'shing
+var cyrillicKeep = []struct {
+	key, want string
+}{
+	{"subscribe_usage", "/subscribe releases all"},
+	{"subscribe_usage", "(beta, rc)"},
+	{"quizdaily_usage", "/quizdaily off"},
+	{"setrules_prompt", "/setrules reset"},
+	{"setrules_prompt", "/setrules <матн>"},
+	{"mod_pin_usage", "/pin silent"},
+	{"mod_purge_usage", "/purge N"},
+	{"modlog_usage", "/modlog [last N]"},
+	{"modlog_usage", "/modlog channel off"},
+	{"modlog_channel_bad", "/modlog channel -100"},
+	{"slowmode_off", "Slow mode"},
+	{"run_usage", "sandbox"},
+	{"captcha_question_main", "main"},
+	{"roadmap", "(int, float64, bool, string, rune)"},
+	{"roadmap", "Goroutine"},
+	{"roadmap", "mutex"},
+	{"roadmap", "Table-driven"},
+	{"useful", "The Go Programming Language"},
+	{"about", "haywan"},
+}
+
+// cyrillicWrong kirill matnida uchramasligi kerak bo'lgan, ilgari transliteratsiya buzgan so'zlar
+var cyrillicWrong = []string{
+	"го", "маин", "чаннел", "силент", "ресет", "ласт", "слов", "моде", "сандбох", "цаптча", "флоод",
+	"стринг", "струцт", "тҳреад", "тҳе", "ҳайван", "гопҳер", "релеасес", "стабле",
+}
+
+// cyrillicSample transliteratsiya tekshiruvida shablonlarga beriladigan qiymatlar
+var cyrillicSample = content.Vars{BotUsername: "gofer_bot", ChatTitle: "Gophers", UserID: 1, UserName: "gopher", FirstName: "Gopher"}
+
+// TestCyrillicTexts barcha matnlarning kirill variantida buzilgan so'zlar yo'qligini tekshiradi
+func TestCyrillicTexts(t *testing.T) {
+	texts, err := content.Load("../../content")
+	if err != nil {
+		t.Fatalf("content.Load: %v", err)
+	}
+	rendered := make(map[string]string, len(ContentKeys))
+	for _, key := range ContentKeys {
+		msg, err := texts.Render(i18n.UzCyrl, key, cyrillicSample)
+		if err != nil {
+			t.Fatalf("Render(%s): %v", key, err)
+		}
+		rendered[key] = msg.Text
+	}
+
+	for _, tt := range cyrillicKeep {
+		if !strings.Contains(rendered[tt.key], tt.want) {
+			t.Errorf("%s: %q o'zgarib ketgan:\n%s", tt.key, tt.want, rendered[tt.key])
+		}
+	}
+
+	wrong := make([]*regexp.Regexp, len(cyrillicWrong))
+	for i, w := range cyrillicWrong {
+		wrong[i] = regexp.MustCompile(`(?i)(^|[^\p{L}])` + w + `($|[^\p{L}])`)
+	}
+	word := regexp.MustCompile(`[\p{L}'’]+`)
+	for _, key := range ContentKeys {
+		text := rendered[key]
+		for i, re := range wrong {
+			if re.MatchString(text) {
+				t.Errorf("%s: noto'g'ri transliteratsiya %q:\n%s", key, cyrillicWrong[i], text)
+			}
+		}
+		// Lotin nomga qo'shilgan qo'shimcha tutuq belgisi bilan ajratiladi (Go'да), so'z ichida yozuvlar aralashmasligi kerak
+		for _, w := range word.FindAllString(text, -1) {
+			for _, part := range strings.FieldsFunc(w, func(r rune) bool { return r == '\'' || r == '’' }) {
+				if hasScript(part, unicode.Latin) && hasScript(part, unicode.Cyrillic) {
+					t.Errorf("%s: %q so'zida lotin va kirill harflari aralashgan", key, w)
+				}
+			}
+		}
+	}
+}
+
+// hasScript so'zda berilgan yozuv harfi borligini tekshiradi
+func hasScript(s string, script *unicode.RangeTable) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return unicode.Is(script, r) }) >= 0
+}
@@ -0,0 +1,77 @@
+// Package i18n bot qo'llab-quvvatlaydigan tillar va o'zbek tilining lotin va kirill yozuvlari orasida transliteratsiya uchun mo'ljallangan
+// Bu paket foydalanuvchi tilini aniqlash va tarjima topilmaganda qaysi tilga qaytishni belgilaydi
+package i18n
+
+import "strings"
+
+// Locale til va yozuv identifikatori (masalan, uz-latn yoki ru)
+type Locale string
+
+// Qo'llab-quvvatlanadigan tillar
+const (
+	UzLatn Locale = "uz-latn" // O'zbek tili, lotin yozuvi
+	UzCyrl Locale = "uz-cyrl" // O'zbek tili, kirill yozuvi
+	Ru     Locale = "ru"      // Rus tili
+	En     Locale = "en"      // Ingliz tili
+
+	Default = UzLatn // Boshqa til aniqlanmaganda ishlatiladigan asosiy til
+)
+
+// Supported barcha qo'llab-quvvatlanadigan tillar, tanlash tugmalarida ko'rsatiladigan tartibda
+var Supported = []Locale{UzLatn, UzCyrl, Ru, En}
+
+// Name tilning o'z tilidagi nomini qaytaradi
+func (l Locale) Name() string {
+	switch l {
+	case UzLatn:
+		return "🇺🇿 O'zbekcha"
+	case UzCyrl:
+		return "🇺🇿 Ўзбекча"
+	case Ru:
+		return "🇷🇺 Русский"
+	case En:
+		return "🇬🇧 English"
+	default:
+		return string(l)
+	}
+}
+
+// Fallbacks tarjima qidiriladigan tillar ketma-ketligini qaytaradi
+// Avval tilning o'zi, keyin unga eng yaqin til va oxirida asosiy til tekshiriladi
+func (l Locale) Fallbacks() []Locale {
+	chain := []Locale{l}
+	if l == UzCyrl {
+		chain = append(chain, UzLatn)
+	}
+	if chain[len(chain)-1] != Default {
+		chain = append(chain, Default)
+	}
+	return chain
+}
+
+// Parse til kodini (masalan, "uz", "uz-Cyrl", "ru-RU", "en_US") qo'llab-quvvatlanadigan tilga o'giradi
+// Telegram foydalanuvchisining language_code maydoni ham shu funksiya orqali o'giriladi
+func Parse(code string) (Locale, bool) {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	if code == "" {
+		return "", false
+	}
+
+	switch code {
+	case string(UzLatn), "uz", "uz-uz", "oz":
+		return UzLatn, true
+	case string(UzCyrl), "uz-cyrl-uz":
+		return UzCyrl, true
+	}
+
+	base, _, _ := strings.Cut(code, "-")
+	switch base {
+	case "uz":
+		return UzLatn, true
+	case "ru":
+		return Ru, true
+	case "en":
+		return En, true
+	}
+	return "", false
+}
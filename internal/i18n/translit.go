@@ -0,0 +1,276 @@
+package i18n
+
+import (
+	"strings"
+	"unicode"
+)
+
+// latinToCyrillic bitta lotin harfining kirill yozuvidagi mosligi
+var latinToCyrillic = map[rune]string{
+	'a': "а", 'b': "б", 'c': "ц", 'd': "д", 'e': "е", 'f': "ф", 'g': "г", 'h': "ҳ",
+	'i': "и", 'j': "ж", 'k': "к", 'l': "л", 'm': "м", 'n': "н", 'o': "о", 'p': "п",
+	'q': "қ", 'r': "р", 's': "с", 't': "т", 'u': "у", 'v': "в", 'w': "в", 'x': "х",
+	'y': "й", 'z': "з",
+}
+
+// cyrillicToLatin bitta kirill harfining lotin yozuvidagi mosligi
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "j",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "x", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "sh", 'ъ': "'", 'ы': "i", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'ў': "o'", 'қ': "q", 'ғ': "g'", 'ҳ': "h",
+}
+
+// latinWords kirill yozuviga o'girilmaydigan nomlar va atamalar
+// Bitta matnga xos so'zlar bu yerga emas, matnning o'zida {{`...`}} ichiga yoziladi
+var latinWords = map[string]bool{
+	"go": true, "golang": true, "gopher": true, "gophers": true, "gofer": true,
+	"telegram": true, "google": true, "reddit": true, "slack": true, "discord": true,
+	"stack": true, "overflow": true, "tour": true, "playground": true, "gofmt": true,
+	// Go kalit so'zlari, paketlar va atamalar
+	"goroutine": true, "goroutines": true, "map": true, "for": true, "switch": true, "select": true,
+	"defer": true, "panic": true, "recover": true, "sync": true, "context": true, "reflect": true,
+	"mutex": true, "table-driven": true,
+	// Buyruq argumentlari, masalan /subscribe releases all yoki /quizdaily off
+	"releases": true, "all": true, "stable": true, "latest": true, "beta": true, "rc": true,
+	"off": true, "global": true,
+}
+
+// isApostrophe o'zbek lotin yozuvida ishlatiladigan tutuq belgisi variantlarini aniqlaydi
+func isApostrophe(r rune) bool {
+	switch r {
+	case '\'', '’', 'ʼ', '‘', 'ʻ':
+		return true
+	}
+	return false
+}
+
+// ToCyrillic o'zbekcha lotin yozuvidagi matnni kirill yozuviga o'giradi
+// Shablon ifodalari ({{...}}), HTML teglari, kod bo'laklari, havolalar, buyruqlar, @username'lar
+// va Go atamalari o'zgarishsiz qoldiriladi
+func ToCyrillic(text string) string {
+	var b strings.Builder
+	b.Grow(len(text) * 2)
+
+	for len(text) > 0 {
+		// O'zgartirilmaydigan bo'laklar
+		if end := protectedPrefix(text); end > 0 {
+			b.WriteString(text[:end])
+			text = text[end:]
+			continue
+		}
+
+		// Bo'shliqgacha bo'lgan so'z yoki bo'shliqlar ketma-ketligi
+		end := strings.IndexFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == '{' || r == '<' || r == '`' })
+		if end == 0 {
+			// Bo'shliq yoki himoyalanmagan maxsus belgi
+			r := []rune(text)[0]
+			b.WriteRune(r)
+			text = text[len(string(r)):]
+			continue
+		}
+		if end < 0 {
+			end = len(text)
+		}
+		b.WriteString(cyrillicToken(text[:end]))
+		text = text[end:]
+	}
+	return b.String()
+}
+
+// protectedPrefix matn boshidagi o'zgartirilmaydigan bo'lak uzunligini qaytaradi (bo'lmasa 0)
+func protectedPrefix(text string) int {
+	switch {
+	case strings.HasPrefix(text, "{{"):
+		if end := strings.Index(text, "}}"); end >= 0 {
+			return end + 2
+		}
+	case strings.HasPrefix(text, "`"):
+		if end := strings.Index(text[1:], "`"); end >= 0 {
+			return end + 2
+		}
+	case strings.HasPrefix(text, "<") && htmlTags[tagName(text)]:
+		if end := strings.Index(text, ">"); end >= 0 {
+			return end + 1
+		}
+	}
+	return 0
+}
+
+// htmlTags Telegram HTML rejimida qo'llab-quvvatlanadigan teglar
+// Boshqa burchakli qavslar (masalan, "/setrules <matn>") oddiy matn sifatida o'giriladi
+var htmlTags = map[string]bool{
+	"a": true, "b": true, "strong": true, "i": true, "em": true, "u": true, "ins": true,
+	"s": true, "strike": true, "del": true, "code": true, "pre": true, "span": true,
+	"tg-spoiler": true, "tg-emoji": true, "blockquote": true,
+}
+
+// tagName "<" bilan boshlanuvchi matndagi teg nomini qaytaradi ("</b>" uchun "b")
+func tagName(text string) string {
+	name := strings.TrimPrefix(text[1:], "/")
+	end := strings.IndexFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && r != '-' })
+	if end < 0 {
+		return ""
+	}
+	return strings.ToLower(name[:end])
+}
+
+// cyrillicToken bitta so'zni kirill yozuviga o'giradi, texnik so'zlarni esa o'zgarishsiz qoldiradi
+func cyrillicToken(token string) string {
+	if isTechnical(token) {
+		return token
+	}
+
+	// So'z boshidagi va oxiridagi tinish belgilarini ajratib, lotincha nom bo'lsa o'zgartirmaymiz
+	core := strings.TrimFunc(token, func(r rune) bool { return !unicode.IsLetter(r) && !isApostrophe(r) })
+	if core != "" {
+		name := core
+		suffix := ""
+		if i := strings.IndexFunc(core, isApostrophe); i > 0 {
+			name, suffix = core[:i], core[i:]
+		}
+		if latinWords[strings.ToLower(name)] {
+			start := strings.Index(token, core)
+			return token[:start] + name + cyrillicWord(suffix) + cyrillicWord(token[start+len(core):])
+		}
+	}
+
+	return cyrillicWord(token)
+}
+
+// isTechnical so'z havola, buyruq, username, fayl yo'li yoki kod ekanligini aniqlaydi
+func isTechnical(token string) bool {
+	if strings.Contains(token, "://") || strings.ContainsAny(token, "/@#_=") {
+		return true
+	}
+
+	letters := 0
+	upper := 0
+	mixed := false
+	prev := rune(0)
+	for i, r := range token {
+		if (unicode.IsDigit(r) && unicode.IsLetter(prev)) || (unicode.IsLetter(r) && unicode.IsDigit(prev)) {
+			mixed = true
+		}
+		prev = r
+		if r > unicode.MaxASCII && unicode.IsLetter(r) {
+			// Allaqachon kirill yoki boshqa yozuvdagi so'z
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+				// GitHub, GoferUz, NoSQL kabi o'rtasida bosh harf bor nomlar
+				if i > 0 && letters > 1 {
+					return true
+				}
+			}
+		}
+		// fmt.Println, go.dev kabi nuqta bilan ajratilgan identifikatorlar
+		if r == '.' && i+1 < len(token) && unicode.IsLetter(rune(token[i+1])) {
+			return true
+		}
+	}
+	// go1.21rc2, amd64 kabi harf va raqam yonma-yon kelgan so'zlar
+	if mixed {
+		return true
+	}
+	// HTTP, CLI, PGO kabi qisqartmalar
+	return letters > 1 && upper == letters
+}
+
+// cyrillicWord so'zni harfma-harf kirill yozuviga o'giradi
+func cyrillicWord(word string) string {
+	runes := []rune(word)
+	var b strings.Builder
+
+	at := func(i int) rune {
+		if i < len(runes) {
+			return unicode.ToLower(runes[i])
+		}
+		return 0
+	}
+	emit := func(s string, upper bool) {
+		if upper {
+			s = strings.ToUpper(s)
+		}
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		lower := unicode.ToLower(r)
+		upper := unicode.IsUpper(r)
+		next := at(i + 1)
+
+		switch {
+		case lower == 's' && next == 'h':
+			emit("ш", upper)
+			i++
+		case lower == 'c' && next == 'h':
+			emit("ч", upper)
+			i++
+		case lower == 'o' && isApostrophe(next):
+			emit("ў", upper)
+			i++
+		case lower == 'g' && isApostrophe(next):
+			emit("ғ", upper)
+			i++
+		case lower == 'y' && next == 'o' && isApostrophe(at(i+2)):
+			// yo'q -> йўқ
+			emit("й", upper)
+		case lower == 'y' && (next == 'o' || next == 'u' || next == 'a' || next == 'e'):
+			emit(map[rune]string{'o': "ё", 'u': "ю", 'a': "я", 'e': "е"}[next], upper)
+			i++
+		case lower == 'e' && (i == 0 || !unicode.IsLetter(runes[i-1])):
+			emit("э", upper)
+		case isApostrophe(r) && i > 0 && unicode.IsLetter(runes[i-1]) && unicode.IsLetter(next):
+			b.WriteString("ъ")
+		default:
+			if s, ok := latinToCyrillic[lower]; ok {
+				emit(s, upper)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// ToLatin o'zbekcha kirill yozuvidagi matnni lotin yozuviga o'giradi
+// Kirill harfi bo'lmagan belgilar (shu jumladan shablon ifodalari) o'zgarishsiz qoladi
+func ToLatin(text string) string {
+	runes := []rune(text)
+	var b strings.Builder
+	b.Grow(len(text))
+
+	isVowel := func(r rune) bool {
+		return strings.ContainsRune("аеёиоуэюяўъь", unicode.ToLower(r))
+	}
+
+	for i, r := range runes {
+		lower := unicode.ToLower(r)
+		s, ok := cyrillicToLatin[lower]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		// е so'z boshida va unlidan keyin "ye" deb o'qiladi
+		if lower == 'е' && (i == 0 || !unicode.IsLetter(runes[i-1]) || isVowel(runes[i-1])) {
+			s = "ye"
+		}
+
+		if unicode.IsUpper(r) && s != "" {
+			// Butun so'z bosh harflarda bo'lsa ikki harfli moslik ham bosh harf bilan yoziladi
+			if i+1 < len(runes) && unicode.IsUpper(runes[i+1]) {
+				s = strings.ToUpper(s)
+			} else {
+				s = strings.ToUpper(s[:1]) + s[1:]
+			}
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
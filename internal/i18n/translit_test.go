@@ -0,0 +1,65 @@
+package i18n
+
+import "testing"
+
+func TestToCyrillic(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Salom, dunyo!", "Салом, дунё!"},
+		{"yo'q", "йўқ"},
+		{"O'zbekiston", "Ўзбекистон"},
+		{"G'alaba", "Ғалаба"},
+		{"Shahar choy", "Шаҳар чой"},
+		{"eshik", "эшик"},
+		{"Yangi yil", "Янги йил"},
+		{"ma'no", "маъно"},
+		// Nomlar, atamalar va ularga qo'shilgan qo'shimchalar
+		{"Go tili", "Go тили"},
+		{"Go'da", "Go'да"},
+		{"Telegram'da", "Telegram'да"},
+		// Shablonlar, teglar va kod o'zgarmaydi
+		{"{{.UserMention}} guruhga xush kelibsiz", "{{.UserMention}} гуруҳга хуш келибсиз"},
+		{"<b>Qoidalar</b>", "<b>Қоидалар</b>"},
+		{"/setrules <matn>", "/setrules <матн>"},
+		{"{{`slow mode`}} yoqildi", "{{`slow mode`}} ёқилди"},
+		{"`go run` buyrug'i", "`go run` буйруғи"},
+		{"/help buyrug'i", "/help буйруғи"},
+		{"@gofer_uz", "@gofer_uz"},
+		{"https://go.dev", "https://go.dev"},
+		{"go1.22rc1", "go1.22rc1"},
+		{"fmt.Println", "fmt.Println"},
+		{"GitHub", "GitHub"},
+		{"HTTP so'rov", "HTTP сўров"},
+		// Kirill matni qayta o'girilmaydi
+		{"Ёқ", "Ёқ"},
+	}
+	for _, tt := range tests {
+		if got := ToCyrillic(tt.in); got != tt.want {
+			t.Errorf("ToCyrillic(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToLatin(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Салом, дунё!", "Salom, dunyo!"},
+		{"йўқ", "yo'q"},
+		{"Ўзбекистон", "O'zbekiston"},
+		{"Ғалаба", "G'alaba"},
+		{"шаҳар чой", "shahar choy"},
+		{"маъно", "ma'no"},
+		{"ЮРТ", "YURT"},
+		{"Цех", "Tsex"},
+		{"ел", "yel"},
+		{"мева", "meva"},
+		{"{{.Args.count}} та", "{{.Args.count}} ta"},
+	}
+	for _, tt := range tests {
+		if got := ToLatin(tt.in); got != tt.want {
+			t.Errorf("ToLatin(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
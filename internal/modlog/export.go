@@ -0,0 +1,130 @@
+package modlog
+
+import (
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader CSV eksportidagi ustunlar
+var csvHeader = []string{
+	"id", "chat_id", "created_at", "action", "source", "actor_id", "actor_name",
+	"target_id", "target_name", "duration", "reason", "message_id", "count",
+}
+
+// ExportHandler guruh jurnalini JSON yoki CSV ko'rinishida beruvchi HTTP handler
+// So'rov parametrlari: chat (majburiy), user, since (RFC3339 yoki 2006-01-02), limit va format (json yoki csv).
+// Kalit faqat "Authorization: Bearer <kalit>" sarlavhasida qabul qilinadi (URL dagi kalit loglarga tushadi), token bo'sh bo'lsa eksport o'chirilgan
+func (l *Log) ExportHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			http.Error(w, "Faqat GET so'rovlari qabul qilinadi", http.StatusMethodNotAllowed)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, "Ruxsat berilmagan", http.StatusUnauthorized)
+			return
+		}
+
+		chatID, filter, err := parseExportQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entries, err := l.Find(chatID, filter)
+		if err != nil {
+			http.Error(w, "Jurnalni o'qib bo'lmadi", http.StatusInternalServerError)
+			return
+		}
+
+		switch format := r.URL.Query().Get("format"); format {
+		case "", "json":
+			w.Header().Set("Content-Type", "application/json")
+			if entries == nil {
+				entries = []Entry{}
+			}
+			_ = json.NewEncoder(w).Encode(entries)
+		case "csv":
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"modlog-%d.csv\"", chatID))
+			_ = writeCSV(w, entries)
+		default:
+			http.Error(w, "format json yoki csv bo'lishi kerak", http.StatusBadRequest)
+		}
+	})
+}
+
+// parseExportQuery so'rov parametrlaridan guruh ID si va filtrni o'qiydi
+func parseExportQuery(r *http.Request) (int64, Filter, error) {
+	query := r.URL.Query()
+	var filter Filter
+
+	chatID, err := strconv.ParseInt(query.Get("chat"), 10, 64)
+	if err != nil || chatID == 0 {
+		return 0, filter, errors.New("chat parametri guruh ID si bo'lishi kerak")
+	}
+	if user := query.Get("user"); user != "" {
+		if filter.TargetID, err = strconv.ParseInt(user, 10, 64); err != nil {
+			return 0, filter, errors.New("user parametri foydalanuvchi ID si bo'lishi kerak")
+		}
+	}
+	if since := query.Get("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			if filter.Since, err = time.Parse(time.DateOnly, since); err != nil {
+				return 0, filter, errors.New("since parametri RFC3339 yoki YYYY-MM-DD ko'rinishida bo'lishi kerak")
+			}
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return 0, filter, errors.New("limit parametri musbat son bo'lishi kerak")
+		}
+	}
+	return chatID, filter, nil
+}
+
+// writeCSV yozuvlarni sarlavha qatori bilan CSV ko'rinishida yozadi
+func writeCSV(w io.Writer, entries []Entry) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		var duration string
+		if e.Duration > 0 {
+			duration = e.Duration.String()
+		}
+		record := []string{
+			strconv.FormatInt(e.ID, 10),
+			strconv.FormatInt(e.ChatID, 10),
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			e.Action,
+			e.Source,
+			strconv.FormatInt(e.ActorID, 10),
+			e.ActorName,
+			strconv.FormatInt(e.TargetID, 10),
+			e.TargetName,
+			duration,
+			e.Reason,
+			strconv.Itoa(e.MessageID),
+			strconv.Itoa(e.Count),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,108 @@
+package modlog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const exportToken = "export-secret"
+
+// export eksport handleriga so'rov yuboradi
+func export(h http.Handler, query, auth string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/modlog/export?"+query, nil)
+	if auth != "" {
+		r.Header.Set("Authorization", auth)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestExportAuth(t *testing.T) {
+	l := fillLog(t)
+	bearer := "Bearer " + exportToken
+	tests := []struct {
+		name  string
+		token string
+		query string
+		auth  string
+		want  int
+	}{
+		{name: "export disabled", token: "", query: "chat=-100", auth: "Bearer ", want: http.StatusNotFound},
+		{name: "no header", token: exportToken, query: "chat=-100", want: http.StatusUnauthorized},
+		{name: "wrong token", token: exportToken, query: "chat=-100", auth: "Bearer wrong", want: http.StatusUnauthorized},
+		{name: "token without Bearer", token: exportToken, query: "chat=-100", auth: exportToken, want: http.StatusUnauthorized},
+		{name: "token in query", token: exportToken, query: "chat=-100&token=" + exportToken, want: http.StatusUnauthorized},
+		{name: "missing chat", token: exportToken, query: "", auth: bearer, want: http.StatusBadRequest},
+		{name: "bad user", token: exportToken, query: "chat=-100&user=x", auth: bearer, want: http.StatusBadRequest},
+		{name: "bad since", token: exportToken, query: "chat=-100&since=yesterday", auth: bearer, want: http.StatusBadRequest},
+		{name: "bad limit", token: exportToken, query: "chat=-100&limit=-1", auth: bearer, want: http.StatusBadRequest},
+		{name: "bad format", token: exportToken, query: "chat=-100&format=xml", auth: bearer, want: http.StatusBadRequest},
+		{name: "ok", token: exportToken, query: "chat=-100", auth: bearer, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := export(l.ExportHandler(tt.token), tt.query, tt.auth); w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.want, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	h := fillLog(t).ExportHandler(exportToken)
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{"chat=-100", []int64{1, 2, 3}},
+		{"chat=-100&format=json&user=1", []int64{1, 3}},
+		{"chat=-100&since=2024-03-02", []int64{2, 3}},
+		{"chat=-100&since=2024-03-03T12:00:00Z", []int64{3}},
+		{"chat=-100&limit=1", []int64{3}},
+		{"chat=-1001", []int64{1, 2}},
+		{"chat=100", []int64{}},
+	}
+	for _, tt := range tests {
+		w := export(h, tt.query, "Bearer "+exportToken)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+			t.Fatalf("%s: status %d, content type %q", tt.query, w.Code, w.Header().Get("Content-Type"))
+		}
+		var entries []Entry
+		if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil || entries == nil {
+			t.Fatalf("%s: bad JSON %q: %v", tt.query, w.Body.String(), err)
+		}
+		if len(entries) != len(tt.want) {
+			t.Fatalf("%s: %d entries, want %d", tt.query, len(entries), len(tt.want))
+		}
+		for i, e := range entries {
+			if e.ID != tt.want[i] {
+				t.Errorf("%s: entry %d id = %d, want %d", tt.query, i, e.ID, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	h := fillLog(t).ExportHandler(exportToken)
+	w := export(h, "chat=-100&format=csv&user=1", "Bearer "+exportToken)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("status %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("records = %q, want header and 2 rows", records)
+	}
+	// Vergulli sabab bitta ustunda qoladi
+	last := records[2]
+	if last[0] != "3" || last[1] != "-100" || last[2] != "2024-03-03T12:00:00Z" || last[3] != Ban || last[10] != "qayta, spam" {
+		t.Fatalf("row = %q", last)
+	}
+}
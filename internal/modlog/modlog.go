@@ -0,0 +1,155 @@
+// Package modlog guruhlarda ko'rilgan moderatsiya choralari jurnalini saqlaydi
+// Har bir yozuv qaysi admin, qachon, kimga nisbatan va nima sababdan chora ko'rganini bildiradi
+package modlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"tg-bot/internal/storage"
+)
+
+// Jurnal saqlanadigan bucket'lar
+const (
+	entriesBucket = "modlog"     // "<chat ID>:<yozuv ID>" kaliti bo'yicha Entry
+	seqBucket     = "modlog_seq" // Chat bo'yicha oxirgi berilgan yozuv ID si
+)
+
+// Moderatsiya choralari
+const (
+	Ban    = "ban"
+	Unban  = "unban"
+	Mute   = "mute"
+	Unmute = "unmute"
+	Kick   = "kick"
+	Warn   = "warn"
+	Unwarn = "unwarn"
+	Purge  = "purge"
+	Delete = "delete"
+	Pin    = "pin"
+	Unpin  = "unpin"
+)
+
+// Avtomatik choralar manbalari, admin buyrug'i bilan ko'rilgan choralarda manba bo'sh bo'ladi
+const (
+	SourceAntispam = "antispam" // Spam filtrlari
+	SourceFlood    = "flood"    // Flood nazorati
+	SourceCaptcha  = "captcha"  // Yangi a'zolar tekshiruvi
+	SourceWarns    = "warns"    // Ogohlantirishlar siyosati
+	SourceReport   = "report"   // A'zolar shikoyati bo'yicha admin ko'rgan chora
+)
+
+// Entry jurnaldagi bitta yozuv
+type Entry struct {
+	ID         int64         `json:"id"`                    // Chat ichidagi tartib raqami
+	ChatID     int64         `json:"chat_id"`               // Chora ko'rilgan guruh
+	Action     string        `json:"action"`                // Chora turi (Ban, Mute, ...)
+	Source     string        `json:"source,omitempty"`      // Chora manbasi (Source...), admin buyrug'ida bo'sh
+	ActorID    int64         `json:"actor_id,omitempty"`    // Chorani ko'rgan admin (avtomatik choralarda 0)
+	ActorName  string        `json:"actor_name,omitempty"`  // Admin ismi yoki @username
+	TargetID   int64         `json:"target_id,omitempty"`   // Chora ko'rilgan foydalanuvchi (purge, pin uchun bo'sh)
+	TargetName string        `json:"target_name,omitempty"` // Foydalanuvchi ismi yoki @username
+	Reason     string        `json:"reason,omitempty"`      // Admin ko'rsatgan sabab
+	Duration   time.Duration `json:"duration,omitempty"`    // Vaqtinchalik cheklov muddati (0 - muddatsiz)
+	MessageID  int           `json:"message_id,omitempty"`  // Tegishli xabar (pin, purge boshlangan xabar)
+	Count      int           `json:"count,omitempty"`       // O'chirilgan xabarlar soni (purge)
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// Automated chora admin emas, balki botning o'zi tomonidan ko'rilganini bildiradi
+func (e Entry) Automated() bool {
+	return e.ActorID == 0
+}
+
+// Filter jurnaldan yozuvlarni tanlash shartlari
+type Filter struct {
+	TargetID int64     // Faqat shu foydalanuvchiga nisbatan ko'rilgan choralar (0 - barchasi)
+	Since    time.Time // Shu vaqtdan keyin ko'rilgan choralar (nol - barchasi)
+	Limit    int       // Shartlarga mos oxirgi Limit ta yozuv (0 - barchasi)
+}
+
+// Log moderatsiya jurnalini omborda yuritadi
+type Log struct {
+	store storage.Store
+	mu    sync.Mutex // Yozuv ID larini ketma-ket berish uchun
+}
+
+// New moderatsiya jurnalini yaratadi
+func New(store storage.Store) *Log {
+	return &Log{store: store}
+}
+
+// entryKey yozuv kaliti, ID nol bilan to'ldiriladi, shunda yozuvlar vaqt tartibida saqlanadi
+func entryKey(chatID, id int64) string {
+	return fmt.Sprintf("%d:%020d", chatID, id)
+}
+
+// Add yozuvni jurnalga qo'shadi va unga berilgan ID bilan qaytaradi
+func (l *Log) Add(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seqKey := strconv.FormatInt(e.ChatID, 10)
+	var last int64
+	data, err := l.store.Get(seqBucket, seqKey)
+	switch {
+	case err == nil:
+		if last, err = strconv.ParseInt(string(data), 10, 64); err != nil {
+			return Entry{}, fmt.Errorf("modlog: noto'g'ri tartib raqami: %w", err)
+		}
+	case !errors.Is(err, storage.ErrNotFound):
+		return Entry{}, err
+	}
+
+	e.ID = last + 1
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	if err := storage.PutJSON(l.store, entriesBucket, entryKey(e.ChatID, e.ID), e); err != nil {
+		return Entry{}, err
+	}
+	if err := l.store.Put(seqBucket, seqKey, []byte(strconv.FormatInt(e.ID, 10))); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// List guruh jurnalidagi barcha yozuvlarni vaqt tartibida qaytaradi
+func (l *Log) List(chatID int64) ([]Entry, error) {
+	var entries []Entry
+	err := l.store.Scan(entriesBucket, strconv.FormatInt(chatID, 10)+":", func(_ string, value []byte) error {
+		var e Entry
+		if err := json.Unmarshal(value, &e); err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+// Find guruh jurnalidan filtrga mos yozuvlarni vaqt tartibida qaytaradi
+func (l *Log) Find(chatID int64, filter Filter) ([]Entry, error) {
+	entries, err := l.List(chatID)
+	if err != nil {
+		return nil, err
+	}
+	matched := entries[:0]
+	for _, e := range entries {
+		if filter.TargetID != 0 && e.TargetID != filter.TargetID {
+			continue
+		}
+		if !filter.Since.IsZero() && e.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[len(matched)-filter.Limit:]
+	}
+	return matched, nil
+}
@@ -0,0 +1,89 @@
+package modlog
+
+import (
+	"testing"
+	"time"
+
+	"tg-bot/internal/storage"
+)
+
+// Bir-birining prefiksi bo'lgan guruh ID lari
+const (
+	chatA int64 = -100
+	chatB int64 = -1001
+)
+
+// fillLog test jurnalini ikki guruh yozuvlari bilan to'ldiradi
+func fillLog(t *testing.T) *Log {
+	t.Helper()
+	l := New(storage.NewMemoryStore())
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{ChatID: chatA, Action: Warn, TargetID: 1, Reason: "spam", CreatedAt: start},
+		{ChatID: chatB, Action: Ban, TargetID: 1, CreatedAt: start},
+		{ChatID: chatA, Action: Mute, TargetID: 2, ActorID: 9, Duration: time.Hour, CreatedAt: start.Add(24 * time.Hour)},
+		{ChatID: chatA, Action: Ban, TargetID: 1, ActorID: 9, Reason: "qayta, spam", CreatedAt: start.Add(48 * time.Hour)},
+		{ChatID: chatB, Action: Kick, TargetID: 3, CreatedAt: start.Add(48 * time.Hour)},
+	}
+	for _, e := range entries {
+		if _, err := l.Add(e); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	return l
+}
+
+func TestAddSequencePerChat(t *testing.T) {
+	l := fillLog(t)
+	for chatID, want := range map[int64][]int64{chatA: {1, 2, 3}, chatB: {1, 2}, 100: nil} {
+		entries, err := l.List(chatID)
+		if err != nil {
+			t.Fatalf("List(%d): %v", chatID, err)
+		}
+		if len(entries) != len(want) {
+			t.Fatalf("List(%d) returned %d entries, want %d", chatID, len(entries), len(want))
+		}
+		for i, e := range entries {
+			// "-100:" prefiksi "-1001:" yozuvlarini qamrab olmasligi kerak
+			if e.ChatID != chatID || e.ID != want[i] {
+				t.Errorf("List(%d)[%d] = chat %d id %d, want chat %d id %d", chatID, i, e.ChatID, e.ID, chatID, want[i])
+			}
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	l := fillLog(t)
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{"all", Filter{}, []int64{1, 2, 3}},
+		{"user", Filter{TargetID: 1}, []int64{1, 3}},
+		{"since", Filter{Since: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)}, []int64{2, 3}},
+		{"limit keeps the latest", Filter{Limit: 2}, []int64{2, 3}},
+		{"user and limit", Filter{TargetID: 1, Limit: 1}, []int64{3}},
+		{"nothing", Filter{TargetID: 3}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := l.Find(chatA, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, e := range entries {
+				got = append(got, e.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Find = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Find = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package quiz Go bo'yicha viktorina savollari banki va ishtirokchilar natijalari
+// Savollar YAML faylda yo'l xaritasining to'qqiz bo'limi bo'yicha guruhlangan holda saqlanadi,
+// natijalar (to'g'ri javoblar va ketma-ketliklar) esa bot omborida har bir chat va umumiy reyting uchun alohida yuritiladi
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Telegram chegaralari
+const (
+	maxOptionLength      = 64  // Tugma matni uzunligi (qisqa bo'lgani qulay)
+	maxExplanationLength = 150 // Izoh to'g'ri javob bilan birga callback oynasiga (200 belgi) sig'ishi kerak
+	minOptions           = 2
+	maxOptions           = 6
+)
+
+// ErrNoQuestions bankda so'ralgan bo'limda savol yo'qligini bildiradi
+var ErrNoQuestions = errors.New("quiz: savollar topilmadi")
+
+// idPattern savol identifikatori callback ma'lumotiga sig'ishi va xavfsiz bo'lishi uchun
+var idPattern = regexp.MustCompile(`^[a-z0-9-]{1,40}$`)
+
+// Section yo'l xaritasi bo'limi
+type Section struct {
+	ID    int    `yaml:"id"`
+	Title string `yaml:"title"`
+}
+
+// Question bitta viktorina savoli
+type Question struct {
+	ID          string   `yaml:"id"`          // O'zgarmas identifikator, natijalar va tugmalar shu orqali bog'lanadi
+	Section     int      `yaml:"section"`     // Yo'l xaritasi bo'limi
+	Text        string   `yaml:"text"`        // Savol matni
+	Code        string   `yaml:"code"`        // Savolga ilova qilingan kod (ixtiyoriy)
+	Options     []string `yaml:"options"`     // Javob variantlari
+	Answer      int      `yaml:"answer"`      // To'g'ri variantning tartib raqami (0 dan)
+	Explanation string   `yaml:"explanation"` // Javobdan keyin ko'rsatiladigan izoh
+}
+
+// bankFile savollar faylining tuzilishi
+type bankFile struct {
+	Sections  []Section  `yaml:"sections"`
+	Questions []Question `yaml:"questions"`
+}
+
+// Bank savollar to'plami
+// Reload orqali fayldan qayta yuklanishi mumkin, xatolik bo'lsa avvalgi savollar saqlanib qoladi
+type Bank struct {
+	path string
+
+	mu        sync.RWMutex
+	sections  []Section
+	questions []Question
+	byID      map[string]int
+}
+
+// Load savollar bankini fayldan yuklaydi va tekshiradi
+func Load(path string) (*Bank, error) {
+	b := &Bank{path: path}
+	if err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Reload savollarni fayldan qaytadan yuklaydi
+func (b *Bank) Reload() error {
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return fmt.Errorf("savollar faylini o'qib bo'lmadi: %w", err)
+	}
+
+	var file bankFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("%s: %w", b.path, err)
+	}
+	byID, err := validate(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", b.path, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sections = file.Sections
+	b.questions = file.Questions
+	b.byID = byID
+	return nil
+}
+
+// validate savollar faylidagi xatolarni aniqlaydi
+func validate(file bankFile) (map[string]int, error) {
+	sections := make(map[int]bool, len(file.Sections))
+	for _, s := range file.Sections {
+		if s.ID <= 0 || s.Title == "" {
+			return nil, fmt.Errorf("bo'lim %d: raqami va nomi bo'lishi kerak", s.ID)
+		}
+		if sections[s.ID] {
+			return nil, fmt.Errorf("bo'lim %d ikki marta e'lon qilingan", s.ID)
+		}
+		sections[s.ID] = true
+	}
+	if len(file.Questions) == 0 {
+		return nil, ErrNoQuestions
+	}
+
+	byID := make(map[string]int, len(file.Questions))
+	for i, q := range file.Questions {
+		if !idPattern.MatchString(q.ID) {
+			return nil, fmt.Errorf("savol %q: identifikator faqat kichik lotin harflari, raqamlar va chiziqchadan iborat bo'lishi kerak", q.ID)
+		}
+		if _, exists := byID[q.ID]; exists {
+			return nil, fmt.Errorf("savol %q ikki marta uchraydi", q.ID)
+		}
+		if !sections[q.Section] {
+			return nil, fmt.Errorf("savol %q: %d-bo'lim mavjud emas", q.ID, q.Section)
+		}
+		if q.Text == "" {
+			return nil, fmt.Errorf("savol %q: matni bo'sh", q.ID)
+		}
+		if len(q.Options) < minOptions || len(q.Options) > maxOptions {
+			return nil, fmt.Errorf("savol %q: %d tadan %d tagacha variant bo'lishi kerak", q.ID, minOptions, maxOptions)
+		}
+		for _, option := range q.Options {
+			if option == "" || utf8.RuneCountInString(option) > maxOptionLength {
+				return nil, fmt.Errorf("savol %q: variant bo'sh yoki %d belgidan uzun", q.ID, maxOptionLength)
+			}
+		}
+		if q.Answer < 0 || q.Answer >= len(q.Options) {
+			return nil, fmt.Errorf("savol %q: to'g'ri javob raqami noto'g'ri", q.ID)
+		}
+		if utf8.RuneCountInString(q.Explanation) > maxExplanationLength {
+			return nil, fmt.Errorf("savol %q: izoh %d belgidan uzun", q.ID, maxExplanationLength)
+		}
+		byID[q.ID] = i
+	}
+	return byID, nil
+}
+
+// Len bankdagi savollar soni
+func (b *Bank) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.questions)
+}
+
+// Sections yo'l xaritasi bo'limlarini qaytaradi
+func (b *Bank) Sections() []Section {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]Section(nil), b.sections...)
+}
+
+// Section bo'limni raqami bo'yicha qaytaradi
+func (b *Bank) Section(id int) (Section, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, s := range b.sections {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+// Question savolni identifikatori bo'yicha qaytaradi
+func (b *Bank) Question(id string) (Question, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	i, ok := b.byID[id]
+	if !ok {
+		return Question{}, false
+	}
+	return b.questions[i], true
+}
+
+// Random bo'limdan tasodifiy savol tanlaydi (section 0 bo'lsa barcha bo'limlardan)
+// skip ro'yxatidagi savollar, iloji bo'lsa, tanlanmaydi, shunda bir savol ketma-ket takrorlanmaydi
+func (b *Bank) Random(section int, skip ...string) (Question, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	skipped := make(map[string]bool, len(skip))
+	for _, id := range skip {
+		skipped[id] = true
+	}
+
+	var all, fresh []int
+	for i, q := range b.questions {
+		if section != 0 && q.Section != section {
+			continue
+		}
+		all = append(all, i)
+		if !skipped[q.ID] {
+			fresh = append(fresh, i)
+		}
+	}
+	if len(all) == 0 {
+		return Question{}, ErrNoQuestions
+	}
+	if len(fresh) == 0 {
+		fresh = all
+	}
+	return b.questions[fresh[rand.Intn(len(fresh))]], nil
+}
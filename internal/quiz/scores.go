@@ -0,0 +1,183 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-bot/internal/storage"
+)
+
+// Natijalar saqlanadigan bucket'lar
+const (
+	scoresBucket  = "quiz_scores"  // "<chat ID yoki global>:<foydalanuvchi ID>" kaliti bo'yicha Score
+	answersBucket = "quiz_answers" // "<chat ID>:<xabar ID>:<foydalanuvchi ID>" kaliti bo'yicha javob vaqti
+)
+
+// Global umumiy reyting uchun chat ID o'rniga ishlatiladigan qiymat
+// Telegram'da 0 identifikatorli chat bo'lmaydi
+const Global int64 = 0
+
+// AnswerWindow savolga javob berish mumkin bo'lgan muddat
+// Javoblar yozuvlari shu muddatdan keyin o'chiriladi, shuning uchun eski savollarga javob qabul qilinmaydi
+const AnswerWindow = 7 * 24 * time.Hour
+
+// ErrAlreadyAnswered foydalanuvchi bu savolga allaqachon javob berganini bildiradi
+var ErrAlreadyAnswered = errors.New("quiz: savolga allaqachon javob berilgan")
+
+// Score ishtirokchining bitta chatdagi yoki umumiy natijasi
+type Score struct {
+	UserID     int64     `json:"user_id"`
+	Correct    int       `json:"correct"`     // To'g'ri javoblar soni
+	Answered   int       `json:"answered"`    // Jami javoblar soni
+	Streak     int       `json:"streak"`      // Hozirgi ketma-ket to'g'ri javoblar
+	BestStreak int       `json:"best_streak"` // Eng uzun ketma-ketlik
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Scores ishtirokchilar natijalarini omborda yuritadi
+type Scores struct {
+	store storage.Store
+	mu    sync.Mutex // O'qish-o'zgartirish-yozish amallarini ketma-ket bajarish uchun
+}
+
+// NewScores natijalar omborini yaratadi
+func NewScores(store storage.Store) *Scores {
+	return &Scores{store: store}
+}
+
+// scoreKey natija kaliti
+func scoreKey(chatID, userID int64) string {
+	if chatID == Global {
+		return fmt.Sprintf("global:%d", userID)
+	}
+	return fmt.Sprintf("%d:%d", chatID, userID)
+}
+
+// scopePrefix chat yoki umumiy reyting kalitlari prefiksi
+func scopePrefix(chatID int64) string {
+	if chatID == Global {
+		return "global:"
+	}
+	return strconv.FormatInt(chatID, 10) + ":"
+}
+
+// Record foydalanuvchining savolga bergan javobini chat va umumiy reytingga yozadi
+// Savol xabari va foydalanuvchi bo'yicha javob faqat bir marta hisoblanadi, takroriy javobda ErrAlreadyAnswered qaytadi.
+// Chatdagi yangilangan natija qaytariladi
+func (s *Scores) Record(chatID int64, messageID int, userID int64, correct bool) (Score, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	answerKey := fmt.Sprintf("%d:%d:%d", chatID, messageID, userID)
+	if _, err := s.store.Get(answersBucket, answerKey); err == nil {
+		return Score{}, ErrAlreadyAnswered
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return Score{}, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.store.Put(answersBucket, answerKey, []byte(now.Format(time.RFC3339))); err != nil {
+		return Score{}, err
+	}
+
+	var chatScore Score
+	for _, scope := range []int64{chatID, Global} {
+		score, err := s.Get(scope, userID)
+		if err != nil {
+			return Score{}, err
+		}
+		score.UserID = userID
+		score.Answered++
+		score.UpdatedAt = now
+		if correct {
+			score.Correct++
+			score.Streak++
+			score.BestStreak = max(score.BestStreak, score.Streak)
+		} else {
+			score.Streak = 0
+		}
+		if err := storage.PutJSON(s.store, scoresBucket, scoreKey(scope, userID), score); err != nil {
+			return Score{}, err
+		}
+		if scope == chatID {
+			chatScore = score
+		}
+	}
+	return chatScore, nil
+}
+
+// Get foydalanuvchining chatdagi (yoki Global bo'lsa umumiy) natijasini qaytaradi
+// Hali javob bermagan foydalanuvchi uchun bo'sh natija qaytariladi
+func (s *Scores) Get(chatID, userID int64) (Score, error) {
+	var score Score
+	err := storage.GetJSON(s.store, scoresBucket, scoreKey(chatID, userID), &score)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return Score{}, err
+	}
+	return score, nil
+}
+
+// Top chat (yoki Global bo'lsa umumiy) reytingining eng yaxshi ishtirokchilarini qaytaradi
+// Tartib: to'g'ri javoblar soni, eng uzun ketma-ketlik, keyin kamroq urinish
+func (s *Scores) Top(chatID int64, limit int) ([]Score, error) {
+	var scores []Score
+	err := s.store.Scan(scoresBucket, scopePrefix(chatID), func(_ string, value []byte) error {
+		var score Score
+		if err := json.Unmarshal(value, &score); err != nil {
+			return err
+		}
+		if score.Correct > 0 {
+			scores = append(scores, score)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.Correct != b.Correct {
+			return a.Correct > b.Correct
+		}
+		if a.BestStreak != b.BestStreak {
+			return a.BestStreak > b.BestStreak
+		}
+		if a.Answered != b.Answered {
+			return a.Answered < b.Answered
+		}
+		return a.UserID < b.UserID
+	})
+	if len(scores) > limit {
+		scores = scores[:limit]
+	}
+	return scores, nil
+}
+
+// Prune AnswerWindow dan eski javob yozuvlarini o'chiradi
+func (s *Scores) Prune(now time.Time) (int, error) {
+	var stale []string
+	err := s.store.Scan(answersBucket, "", func(key string, value []byte) error {
+		at, err := time.Parse(time.RFC3339, strings.TrimSpace(string(value)))
+		if err != nil || now.Sub(at) > AnswerWindow {
+			stale = append(stale, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range stale {
+		if err := s.store.Delete(answersBucket, key); err != nil {
+			return 0, err
+		}
+	}
+	return len(stale), nil
+}
@@ -0,0 +1,152 @@
+package releases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxSourceSize manbadan o'qiladigan ma'lumotlarning eng katta hajmi
+const maxSourceSize = 32 << 20
+
+// open manbani ochadi: http(s) manzil so'rov orqali, qolganlari mahalliy fayl sifatida o'qiladi
+func (d *DB) open(ctx context.Context, src string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.Open(strings.TrimPrefix(src, "file://"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: kutilmagan javob %s", src, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// read manbaning butun mazmunini o'qiydi
+func (d *DB) read(ctx context.Context, src string) ([]byte, error) {
+	r, err := d.open(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, maxSourceSize))
+}
+
+// indexRelease go.dev/dl/?mode=json formatidagi bitta yozuv
+type indexRelease struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+	Files   []File `json:"files"`
+}
+
+// fetchIndex relizlar ro'yxatini manbadan o'qiydi va tartiblaydi
+func (d *DB) fetchIndex(ctx context.Context) ([]Release, error) {
+	data, err := d.read(ctx, d.source)
+	if err != nil {
+		return nil, fmt.Errorf("relizlar ro'yxatini olib bo'lmadi: %w", err)
+	}
+	list, err := parseIndex(data)
+	if err != nil {
+		return nil, fmt.Errorf("relizlar ro'yxatini o'qib bo'lmadi: %w", err)
+	}
+	return list, nil
+}
+
+// parseIndex go.dev JSON formatini relizlar ro'yxatiga o'giradi
+// Nomi tanilmagan yozuvlar (masalan, juda eski "go1") tashlab ketiladi
+func parseIndex(data []byte) ([]Release, error) {
+	var raw []indexRelease
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	list := make([]Release, 0, len(raw))
+	for _, r := range raw {
+		v, _, err := ParseVersion(r.Version)
+		if err != nil {
+			continue
+		}
+		list = append(list, Release{Name: r.Version, Version: v, Stable: r.Stable, Files: r.Files})
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("manbada birorta ham reliz topilmadi")
+	}
+	sortReleases(list)
+	return list, nil
+}
+
+// historyEntry reliz tarixi sahifasidagi bitta reliz haqidagi yozuv
+type historyEntry struct {
+	date     time.Time
+	security bool
+	fixes    string
+}
+
+var (
+	// releasedPattern "go1.22.1 (released 2024-03-05)" ko'rinishidagi sarlavhalarni topadi
+	releasedPattern = regexp.MustCompile(`go(\d+\.\d+(?:\.\d+)?(?:(?:beta|rc)\d+)?) \(released (\d{4}-\d{2}-\d{2})\)`)
+	// securityPattern "includes security fixes to the crypto/x509 and net/http packages" jumlasidan paketlarni ajratadi
+	securityPattern = regexp.MustCompile(`security fix(?:es)? to (?:the )?(.+?)(?:, as well as |\. |\.$)`)
+	tagPattern      = regexp.MustCompile(`<[^>]*>`)
+	spacePattern    = regexp.MustCompile(`\s+`)
+)
+
+// fetchHistory reliz tarixi sahifasini o'qiydi
+func (d *DB) fetchHistory(ctx context.Context) (map[string]historyEntry, error) {
+	data, err := d.read(ctx, d.history)
+	if err != nil {
+		return nil, fmt.Errorf("reliz tarixini olib bo'lmadi: %w", err)
+	}
+	return parseHistory(string(data)), nil
+}
+
+// parseHistory go.dev/doc/devel/release sahifasidan relizlar sanasi va xavfsizlik tuzatishlarini ajratadi
+// Natija versiyaning String() ko'rinishi bo'yicha kalitlanadi
+func parseHistory(page string) map[string]historyEntry {
+	text := html.UnescapeString(tagPattern.ReplaceAllString(page, " "))
+	text = spacePattern.ReplaceAllString(text, " ")
+	// Teglar o'rniga qo'yilgan bo'shliqlar tinish belgilaridan oldin qolmasligi kerak
+	text = strings.NewReplacer(" ,", ",", " .", ".").Replace(text)
+
+	entries := make(map[string]historyEntry)
+	matches := releasedPattern.FindAllStringSubmatchIndex(text, -1)
+	for i, m := range matches {
+		v, _, err := ParseVersion(text[m[2]:m[3]])
+		if err != nil {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, text[m[4]:m[5]])
+		if err != nil {
+			continue
+		}
+
+		// Relizga tegishli matn keyingi reliz sarlavhasigacha davom etadi
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := text[m[1]:end]
+
+		entry := historyEntry{date: date, security: strings.Contains(body, "security fix")}
+		if sm := securityPattern.FindStringSubmatch(body); sm != nil {
+			entry.fixes = strings.TrimSpace(sm[1])
+		}
+		entries[v.String()] = entry
+	}
+	return entries
+}
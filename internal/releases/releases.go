@@ -0,0 +1,463 @@
+// Package releases Go relizlari haqidagi ma'lumotlar bazasi
+// Ma'lumotlar go.dev/dl JSON formatidagi manbadan va reliz tarixi sahifasidan olinadi, omborda keshlanadi
+// va belgilangan oraliqda yangilanadi, shuning uchun /latest va /version doim dolzarb javob beradi
+package releases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-bot/internal/storage"
+	"tg-bot/pkg/logger"
+)
+
+// Keshlash uchun ombordagi bucket va kalit
+const (
+	cacheBucket = "releases"
+	cacheKey    = "index"
+)
+
+// supportedSeries bir vaqtda qo'llab-quvvatlanadigan asosiy relizlar soni
+// Go jamoasi oxirgi ikki qator uchun xavfsizlik tuzatishlarini chiqaradi
+const supportedSeries = 2
+
+var (
+	// ErrNotReady ma'lumotlar hali yuklanmaganini bildiradi
+	ErrNotReady = errors.New("releases: ma'lumotlar hali yuklanmagan")
+	// ErrNotFound so'ralgan versiya topilmaganini bildiradi
+	ErrNotFound = errors.New("releases: versiya topilmadi")
+	// ErrBadQuery so'rov versiya raqamiga o'xshamasligini bildiradi
+	ErrBadQuery = errors.New("releases: noto'g'ri so'rov")
+)
+
+// File relizning bitta yuklab olinadigan fayli
+type File struct {
+	Filename string `json:"filename"` // Fayl nomi, masalan go1.22.1.linux-amd64.tar.gz
+	OS       string `json:"os"`       // Operatsion tizim, manba kodi uchun bo'sh
+	Arch     string `json:"arch"`     // Arxitektura, manba kodi uchun bo'sh
+	Kind     string `json:"kind"`     // source, archive yoki installer
+	SHA256   string `json:"sha256"`   // Nazorat yig'indisi
+	Size     int64  `json:"size"`     // Hajmi baytlarda
+}
+
+// URL faylni yuklab olish manzili
+func (f File) URL() string {
+	return "https://go.dev/dl/" + f.Filename
+}
+
+// Release bitta Go relizi haqidagi ma'lumot
+type Release struct {
+	Name          string    `json:"name"`           // go.dev dagi nomi, masalan go1.22.1
+	Version       Version   `json:"version"`        // Tahlil qilingan versiya
+	Stable        bool      `json:"stable"`         // Barqaror relizmi
+	Date          time.Time `json:"date"`           // Chiqarilgan sana, noma'lum bo'lsa nol
+	Security      bool      `json:"security"`       // Xavfsizlik tuzatishlarini o'z ichiga oladimi
+	SecurityFixes string    `json:"security_fixes"` // Xavfsizlik tuzatishlari tegishli paketlar (reliz tarixidagi matn)
+	Files         []File    `json:"files"`          // Yuklab olinadigan fayllar
+	SeenAt        time.Time `json:"seen_at"`        // Bot relizni birinchi marta ko'rgan vaqt, birinchi yuklashdagi relizlar uchun nol
+}
+
+// NotesURL reliz haqidagi rasmiy hujjat manzili
+func (r Release) NotesURL() string {
+	switch {
+	case r.Version.Prerelease():
+		return "https://tip.golang.org/doc/go" + r.Version.Series()
+	case r.Version.Patch == 0:
+		return "https://go.dev/doc/go" + r.Version.Series()
+	default:
+		return "https://go.dev/doc/devel/release#" + r.Name
+	}
+}
+
+// primaryPlatforms qisqa javobda ko'rsatiladigan platformalar va ular uchun afzal fayl turi
+var primaryPlatforms = []struct{ os, arch, kind string }{
+	{"linux", "amd64", "archive"},
+	{"linux", "arm64", "archive"},
+	{"darwin", "arm64", "installer"},
+	{"darwin", "amd64", "installer"},
+	{"windows", "amd64", "installer"},
+	{"windows", "arm64", "installer"},
+}
+
+// Downloads asosiy platformalar uchun fayllarni va manba kodini qaytaradi
+// Afzal turdagi fayl bo'lmasa platformaning arxivi olinadi
+func (r Release) Downloads() []File {
+	var files []File
+	for _, f := range r.Files {
+		if f.Kind == "source" {
+			files = append(files, f)
+			break
+		}
+	}
+
+	for _, p := range primaryPlatforms {
+		var fallback *File
+		found := false
+		for i, f := range r.Files {
+			if f.OS != p.os || f.Arch != p.arch {
+				continue
+			}
+			if f.Kind == p.kind {
+				files = append(files, f)
+				found = true
+				break
+			}
+			if f.Kind == "archive" && fallback == nil {
+				fallback = &r.Files[i]
+			}
+		}
+		if !found && fallback != nil {
+			files = append(files, *fallback)
+		}
+	}
+	return files
+}
+
+// cache omborda saqlanadigan ma'lumotlar
+type cache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Releases  []Release `json:"releases"`
+}
+
+// DB relizlar bazasi
+// Barcha metodlar bir vaqtda bir nechta goroutine'dan chaqirilishi mumkin
+type DB struct {
+	store   storage.Store
+	source  string
+	history string
+	client  *http.Client
+
+	mu        sync.RWMutex
+	releases  []Release // Eng yangisidan eskisiga qarab tartiblangan
+	fetchedAt time.Time
+	onRefresh func() // Har bir yangilash urinishidan keyin chaqiriladi
+}
+
+// New yangi relizlar bazasini yaratadi
+// source go.dev/dl JSON formatidagi manba, history reliz tarixi sahifasi (bo'sh bo'lsa sanalar va xavfsizlik belgilari olinmaydi)
+// Ikkalasi ham http(s) manzil yoki mahalliy fayl yo'li bo'lishi mumkin
+func New(store storage.Store, source, history string, timeout time.Duration) *DB {
+	return &DB{
+		store:   store,
+		source:  source,
+		history: history,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Load omborda keshlangan ma'lumotlarni yuklaydi
+// Kesh bo'lmasa xatolik qaytarilmaydi, ma'lumotlar birinchi yangilanishda paydo bo'ladi
+func (d *DB) Load() error {
+	var c cache
+	if err := storage.GetJSON(d.store, cacheBucket, cacheKey, &c); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("relizlar keshini o'qib bo'lmadi: %w", err)
+	}
+
+	d.mu.Lock()
+	d.releases = c.Releases
+	d.fetchedAt = c.FetchedAt
+	d.mu.Unlock()
+	return nil
+}
+
+// Refresh manbadan relizlar ro'yxatini qayta yuklaydi va omborga yozadi
+// Ro'yxatni olib bo'lmasa avvalgi ma'lumotlar o'zgarmaydi. Reliz tarixini olib bo'lmasa ro'yxat baribir yangilanadi,
+// sanalar va xavfsizlik belgilari esa avvalgi ma'lumotlardan olinadi va xatolik qaytariladi
+func (d *DB) Refresh(ctx context.Context) error {
+	list, err := d.fetchIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	var historyErr error
+	var notes map[string]historyEntry
+	if d.history != "" {
+		notes, historyErr = d.fetchHistory(ctx)
+	}
+
+	d.mu.Lock()
+	now := time.Now()
+	previous := make(map[string]Release, len(d.releases))
+	for _, r := range d.releases {
+		previous[r.Version.String()] = r
+	}
+	for i := range list {
+		key := list[i].Version.String()
+		// Birinchi yuklashda barcha relizlar tarix hisoblanadi va yangi deb belgilanmaydi
+		if old, ok := previous[key]; ok {
+			list[i].SeenAt = old.SeenAt
+		} else if len(previous) > 0 {
+			list[i].SeenAt = now
+		}
+		if entry, ok := notes[key]; ok {
+			list[i].Date = entry.date
+			list[i].Security = entry.security
+			list[i].SecurityFixes = entry.fixes
+		} else if old, ok := previous[key]; ok {
+			list[i].Date = old.Date
+			list[i].Security = old.Security
+			list[i].SecurityFixes = old.SecurityFixes
+		}
+	}
+	d.releases = list
+	d.fetchedAt = now
+	c := cache{FetchedAt: d.fetchedAt, Releases: list}
+	d.mu.Unlock()
+
+	if err := storage.PutJSON(d.store, cacheBucket, cacheKey, c); err != nil {
+		return fmt.Errorf("relizlar keshini saqlab bo'lmadi: %w", err)
+	}
+	return historyErr
+}
+
+// OnRefresh har bir yangilash urinishidan keyin chaqiriladigan funksiyani o'rnatadi
+// Masalan, yangi relizlar haqida e'lon yuborish uchun ishlatiladi. Run ishga tushishidan oldin chaqirilishi kerak
+func (d *DB) OnRefresh(fn func()) {
+	d.onRefresh = fn
+}
+
+// Run ma'lumotlarni interval oralig'ida yangilab turadi
+// Kesh bo'sh yoki eskirgan bo'lsa birinchi yangilanish darhol bajariladi
+// ctx bekor qilinguncha ishlaydi, interval nol bo'lsa faqat bir marta yangilaydi
+func (d *DB) Run(ctx context.Context, interval time.Duration, log *logger.Logger) {
+	refresh := func() {
+		defer func() {
+			if d.onRefresh != nil {
+				d.onRefresh()
+			}
+		}()
+		if err := d.Refresh(ctx); err != nil {
+			log.Warnf("Relizlar ma'lumotlarini yangilashda xatolik: %v", err)
+			return
+		}
+		if latest, err := d.Latest(); err == nil {
+			log.Infof("Relizlar ma'lumotlari yangilandi: %d ta, oxirgisi %s", d.Len(), latest.Name)
+		}
+	}
+
+	d.mu.RLock()
+	stale := len(d.releases) == 0 || interval <= 0 || time.Since(d.fetchedAt) >= interval
+	d.mu.RUnlock()
+	if stale {
+		refresh()
+	} else if d.onRefresh != nil {
+		// Kesh yangi bo'lsa ham kutilayotgan e'lonlar tekshirilishi kerak
+		d.onRefresh()
+	}
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// HasHistory reliz tarixi manbasi sozlanganini bildiradi
+// Tarixsiz relizlar sanasi va xavfsizlik belgilari hech qachon ma'lum bo'lmaydi
+func (d *DB) HasHistory() bool {
+	return d.history != ""
+}
+
+// Releases barcha ma'lum relizlarni eng yangisidan boshlab qaytaradi
+func (d *DB) Releases() []Release {
+	list, _ := d.snapshot()
+	return list
+}
+
+// Len ma'lum relizlar soni
+func (d *DB) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.releases)
+}
+
+// snapshot joriy ro'yxatni qaytaradi, ro'yxat bo'sh bo'lsa ErrNotReady
+func (d *DB) snapshot() ([]Release, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.releases) == 0 {
+		return nil, ErrNotReady
+	}
+	return d.releases, nil
+}
+
+// Latest eng so'nggi barqaror relizni qaytaradi
+func (d *DB) Latest() (Release, error) {
+	list, err := d.snapshot()
+	if err != nil {
+		return Release{}, err
+	}
+	for _, r := range list {
+		if r.Stable {
+			return r, nil
+		}
+	}
+	return Release{}, ErrNotFound
+}
+
+// Prerelease eng so'nggi barqaror relizdan yangiroq sinov versiyasini qaytaradi (agar mavjud bo'lsa)
+func (d *DB) Prerelease() (Release, bool) {
+	list, err := d.snapshot()
+	if err != nil || len(list) == 0 || list[0].Stable {
+		return Release{}, false
+	}
+	return list[0], true
+}
+
+// Find so'rov bo'yicha relizni topadi
+// Qo'llab-quvvatlanadigan so'rovlar:
+//   - "" yoki "latest" - eng so'nggi barqaror reliz
+//   - "latest-N" - eng so'nggi qatordan N ta oldingi qatorning oxirgi barqaror relizi
+//   - "1.22" yoki "go1.22" - qatorning oxirgi barqaror relizi (barqarori bo'lmasa oxirgi sinov versiyasi)
+//   - "1.22.1", "go1.21rc2" - aniq versiya
+func (d *DB) Find(query string) (Release, error) {
+	list, err := d.snapshot()
+	if err != nil {
+		return Release{}, err
+	}
+
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" || q == "latest" {
+		return d.Latest()
+	}
+
+	if rest, ok := strings.CutPrefix(q, "latest-"); ok {
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 0 {
+			return Release{}, fmt.Errorf("%w: %s", ErrBadQuery, query)
+		}
+		heads := seriesHeads(list)
+		if n >= len(heads) {
+			return Release{}, ErrNotFound
+		}
+		return heads[n], nil
+	}
+
+	v, patchSet, err := ParseVersion(q)
+	if err != nil {
+		return Release{}, fmt.Errorf("%w: %s", ErrBadQuery, query)
+	}
+
+	if !patchSet && !v.Prerelease() {
+		var newest *Release
+		for i, r := range list {
+			if !r.Version.SameSeries(v) {
+				continue
+			}
+			if r.Stable {
+				return r, nil
+			}
+			if newest == nil {
+				newest = &list[i]
+			}
+		}
+		if newest != nil {
+			return *newest, nil
+		}
+		return Release{}, ErrNotFound
+	}
+
+	for _, r := range list {
+		if r.Version.Compare(v) == 0 {
+			return r, nil
+		}
+	}
+	return Release{}, ErrNotFound
+}
+
+// Newest relizning qatoridagi eng so'nggi barqaror versiyani qaytaradi
+func (d *DB) Newest(r Release) (Release, bool) {
+	list, err := d.snapshot()
+	if err != nil {
+		return Release{}, false
+	}
+	for _, other := range list {
+		if other.Stable && other.Version.SameSeries(r.Version) {
+			return other, true
+		}
+	}
+	return Release{}, false
+}
+
+// Supported reliz qatori hali xavfsizlik tuzatishlarini olishini tekshiradi
+func (d *DB) Supported(r Release) bool {
+	list, err := d.snapshot()
+	if err != nil {
+		return false
+	}
+	heads := seriesHeads(list)
+	for i := 0; i < len(heads) && i < supportedSeries; i++ {
+		if heads[i].Version.SameSeries(r.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggestions topilmagan so'rov uchun yaqin versiyalar nomlarini qaytaradi
+// So'rov qatori ma'lum bo'lsa shu qator versiyalari, aks holda oxirgi qatorlarning so'nggi versiyalari taklif qilinadi
+func (d *DB) Suggestions(query string, limit int) []string {
+	list, err := d.snapshot()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	if v, _, err := ParseVersion(query); err == nil {
+		for _, r := range list {
+			if r.Version.SameSeries(v) && len(names) < limit {
+				names = append(names, r.Version.String())
+			}
+		}
+	}
+	if len(names) == 0 {
+		for _, r := range seriesHeads(list) {
+			if len(names) == limit {
+				break
+			}
+			names = append(names, r.Version.String())
+		}
+	}
+	return names
+}
+
+// seriesHeads har bir qatorning oxirgi barqaror relizini eng yangisidan boshlab qaytaradi
+func seriesHeads(list []Release) []Release {
+	var heads []Release
+	for _, r := range list {
+		if !r.Stable {
+			continue
+		}
+		if len(heads) > 0 && heads[len(heads)-1].Version.SameSeries(r.Version) {
+			continue
+		}
+		heads = append(heads, r)
+	}
+	return heads
+}
+
+// sortReleases ro'yxatni eng yangisidan eskisiga qarab tartiblaydi
+func sortReleases(list []Release) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Version.Compare(list[j].Version) > 0
+	})
+}
@@ -0,0 +1,118 @@
+package releases
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version Go versiyasining tahlil qilingan ko'rinishi
+// go1.21.0, go1.20 (1.21 dan oldingi yangi qator nomlanishi), go1.21rc2 va go1.22beta1 ko'rinishlari qo'llab-quvvatlanadi
+type Version struct {
+	Major int    // Asosiy raqam, hozircha doim 1
+	Minor int    // Qator raqami, masalan 1.22 uchun 22
+	Patch int    // Tuzatish raqami, ko'rsatilmagan bo'lsa 0
+	Pre   string // Sinov versiyasi belgisi: "beta1", "rc2" yoki bo'sh
+}
+
+// versionPattern go1.22.1, 1.22, go1.21rc2 kabi satrlarni ajratadi
+var versionPattern = regexp.MustCompile(`^(?:go)?(\d+)\.(\d+)(?:\.(\d+))?((?:beta|rc)\d+)?$`)
+
+// ParseVersion satrni Go versiyasiga o'giradi
+// patchSet false bo'lsa tuzatish raqami ko'rsatilmagan (masalan, "1.22" - butun qator so'ralgan)
+func ParseVersion(s string) (v Version, patchSet bool, err error) {
+	m := versionPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return Version{}, false, fmt.Errorf("noto'g'ri versiya: %q", s)
+	}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+		patchSet = true
+	}
+	v.Pre = m[4]
+	return v, patchSet, nil
+}
+
+// String versiyani go.dev dagi nomlanish bo'yicha qaytaradi (go prefiksisiz)
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if v.Pre != "" {
+		return s + v.Pre
+	}
+	// 1.21 dan oldin qatorning birinchi relizi .0 siz nomlangan
+	if v.Patch != 0 || v.Major > 1 || v.Minor >= 21 {
+		s += fmt.Sprintf(".%d", v.Patch)
+	}
+	return s
+}
+
+// Series versiya tegishli qator nomi, masalan "1.22"
+func (v Version) Series() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Prerelease versiya beta yoki rc ekanligini bildiradi
+func (v Version) Prerelease() bool {
+	return v.Pre != ""
+}
+
+// SameSeries ikki versiya bitta qatorga tegishli ekanligini tekshiradi
+func (v Version) SameSeries(o Version) bool {
+	return v.Major == o.Major && v.Minor == o.Minor
+}
+
+// Compare versiyalarni solishtiradi: v < o bo'lsa -1, teng bo'lsa 0, katta bo'lsa 1
+// Qatorning sinov versiyalari (beta, rc) shu qatorning birinchi relizidan oldin turadi
+func (v Version) Compare(o Version) int {
+	if c := cmpInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePre(v.Pre, o.Pre)
+}
+
+// comparePre sinov belgilarini solishtiradi: beta < rc < reliz
+func comparePre(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+	rank := func(pre string) (int, int) {
+		kind := 0
+		if strings.HasPrefix(pre, "rc") {
+			kind = 1
+		}
+		n, _ := strconv.Atoi(strings.TrimLeft(pre, "betarc"))
+		return kind, n
+	}
+	ak, an := rank(a)
+	bk, bn := rank(b)
+	if c := cmpInt(ak, bk); c != 0 {
+		return c
+	}
+	return cmpInt(an, bn)
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
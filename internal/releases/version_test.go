@@ -0,0 +1,68 @@
+package releases
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     Version
+		patchSet bool
+		wantErr  bool
+	}{
+		{in: "go1.22.1", want: Version{Major: 1, Minor: 22, Patch: 1}, patchSet: true},
+		{in: "1.22", want: Version{Major: 1, Minor: 22}},
+		{in: " GO1.21.0 ", want: Version{Major: 1, Minor: 21}, patchSet: true},
+		{in: "go1.21rc2", want: Version{Major: 1, Minor: 21, Pre: "rc2"}},
+		{in: "go1.22beta1", want: Version{Major: 1, Minor: 22, Pre: "beta1"}},
+		{in: "go1.20", want: Version{Major: 1, Minor: 20}},
+		{in: "", wantErr: true},
+		{in: "go1", wantErr: true},
+		{in: "1.22.x", wantErr: true},
+		{in: "go1.22alpha1", wantErr: true},
+		{in: "v1.22.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, patchSet, err := ParseVersion(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want || patchSet != tt.patchSet {
+			t.Errorf("ParseVersion(%q) = %+v, %v; want %+v, %v", tt.in, got, patchSet, tt.want, tt.patchSet)
+		}
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	tests := []struct {
+		v    Version
+		want string
+	}{
+		{Version{Major: 1, Minor: 20}, "1.20"},
+		{Version{Major: 1, Minor: 20, Patch: 3}, "1.20.3"},
+		{Version{Major: 1, Minor: 21}, "1.21.0"},
+		{Version{Major: 1, Minor: 22, Pre: "rc1"}, "1.22rc1"},
+	}
+	for _, tt := range tests {
+		if got := tt.v.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	order := []string{"1.21beta1", "1.21rc1", "1.21rc2", "1.21.0", "1.21.1", "1.22beta2", "1.22.0", "1.22.10"}
+	for i, a := range order {
+		va, _, err := ParseVersion(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, b := range order {
+			vb, _, _ := ParseVersion(b)
+			want := cmpInt(i, j)
+			if got := va.Compare(vb); got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package reports guruh a'zolari adminlarga yuborgan shikoyatlarni saqlaydi
+// Bitta xabar bo'yicha faqat bitta shikoyat ochiladi, keyingi shikoyatchilar unga qo'shiladi,
+// shikoyatni adminlardan faqat bittasi ko'rib chiqa oladi
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"tg-bot/internal/storage"
+)
+
+// bucket shikoyatlar saqlanadigan bucket ("<chat ID>:<xabar ID>" kaliti bo'yicha Report)
+const bucket = "reports"
+
+// Shikoyat ochishda va ko'rib chiqishda qaytadigan xatoliklar
+var (
+	ErrAlreadyReported = errors.New("reports: foydalanuvchi bu xabar haqida allaqachon xabar bergan")
+	ErrHandled         = errors.New("reports: shikoyat allaqachon ko'rib chiqilgan")
+)
+
+// Delivery adminlarga yuborilgan shikoyat xabari
+type Delivery struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+}
+
+// Report bitta xabar bo'yicha shikoyat
+type Report struct {
+	ChatID         int64      `json:"chat_id"`
+	ChatTitle      string     `json:"chat_title"`
+	ChatUserName   string     `json:"chat_username,omitempty"`
+	MessageID      int        `json:"message_id"`
+	Text           string     `json:"text,omitempty"` // Xabar matnidan parcha
+	AuthorID       int64      `json:"author_id"`
+	AuthorName     string     `json:"author_name"`
+	AuthorUserName string     `json:"author_username,omitempty"`
+	Reporters      []int64    `json:"reporters"`              // Shikoyat qilganlar, birinchisi shikoyatni ochgan
+	ReporterName   string     `json:"reporter_name"`          // Birinchi shikoyatchi ismi
+	Reason         string     `json:"reason,omitempty"`       // Birinchi shikoyatchi ko'rsatgan sabab
+	Deliveries     []Delivery `json:"deliveries,omitempty"`   // Adminlarga yuborilgan shikoyat xabarlari
+	CreatedAt      time.Time  `json:"created_at"`             // Shikoyat ochilgan vaqt
+	Action         string     `json:"action,omitempty"`       // Ko'rilgan chora, bo'sh bo'lsa shikoyat hali ko'rib chiqilmagan
+	HandlerID      int64      `json:"handler_id,omitempty"`   // Shikoyatni ko'rib chiqqan admin
+	HandlerName    string     `json:"handler_name,omitempty"` // Admin ismi
+	HandledAt      time.Time  `json:"handled_at,omitempty"`   // Shikoyat ko'rib chiqilgan vaqt
+}
+
+// Handled shikoyat ko'rib chiqilganini bildiradi
+func (r Report) Handled() bool {
+	return r.Action != ""
+}
+
+// Store shikoyatlarni omborda yuritadi
+type Store struct {
+	store storage.Store
+	mu    sync.Mutex // O'qish-o'zgartirish-yozish amallarini ketma-ket bajarish uchun
+}
+
+// New shikoyatlar omborini yaratadi
+func New(store storage.Store) *Store {
+	return &Store{store: store}
+}
+
+// key shikoyat kaliti
+func key(chatID int64, messageID int) string {
+	return fmt.Sprintf("%d:%d", chatID, messageID)
+}
+
+// Get xabar bo'yicha shikoyatni qaytaradi, topilmasa storage.ErrNotFound
+func (s *Store) Get(chatID int64, messageID int) (Report, error) {
+	var r Report
+	err := storage.GetJSON(s.store, bucket, key(chatID, messageID), &r)
+	return r, err
+}
+
+// File xabar haqida shikoyat qiladi
+// Xabar bo'yicha shikoyat bo'lmasa report saqlanadi va created true bo'ladi,
+// aks holda shikoyatchi mavjud shikoyatga qo'shiladi. Foydalanuvchi qayta shikoyat qilsa ErrAlreadyReported,
+// shikoyat ko'rib chiqilgan bo'lsa ErrHandled qaytadi
+func (s *Store) File(report Report, reporterID int64) (result Report, created bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.Get(report.ChatID, report.MessageID)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		report.Reporters = []int64{reporterID}
+		if report.CreatedAt.IsZero() {
+			report.CreatedAt = time.Now()
+		}
+		return report, true, storage.PutJSON(s.store, bucket, key(report.ChatID, report.MessageID), report)
+	case err != nil:
+		return Report{}, false, err
+	case existing.Handled():
+		return existing, false, ErrHandled
+	case slices.Contains(existing.Reporters, reporterID):
+		return existing, false, ErrAlreadyReported
+	}
+	existing.Reporters = append(existing.Reporters, reporterID)
+	return existing, false, storage.PutJSON(s.store, bucket, key(existing.ChatID, existing.MessageID), existing)
+}
+
+// SetDeliveries adminlarga yuborilgan shikoyat xabarlarini saqlaydi
+func (s *Store) SetDeliveries(chatID int64, messageID int, deliveries []Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.Get(chatID, messageID)
+	if err != nil {
+		return err
+	}
+	r.Deliveries = deliveries
+	return storage.PutJSON(s.store, bucket, key(chatID, messageID), r)
+}
+
+// Claim shikoyatni admin nomiga ko'rib chiqilgan deb belgilaydi
+// Shikoyatni boshqa admin allaqachon ko'rib chiqqan bo'lsa ErrHandled va o'sha shikoyat qaytadi
+func (s *Store) Claim(chatID int64, messageID int, action string, handlerID int64, handlerName string) (Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.Get(chatID, messageID)
+	if err != nil {
+		return Report{}, err
+	}
+	if r.Handled() {
+		return r, ErrHandled
+	}
+	r.Action, r.HandlerID, r.HandlerName, r.HandledAt = action, handlerID, handlerName, time.Now()
+	return r, storage.PutJSON(s.store, bucket, key(chatID, messageID), r)
+}
+
+// Release chorani bajarib bo'lmaganda shikoyatni qayta ochadi
+func (s *Store) Release(chatID int64, messageID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.Get(chatID, messageID)
+	if err != nil {
+		return err
+	}
+	r.Action, r.HandlerID, r.HandlerName, r.HandledAt = "", 0, "", time.Time{}
+	return storage.PutJSON(s.store, bucket, key(chatID, messageID), r)
+}
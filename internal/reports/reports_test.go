@@ -0,0 +1,138 @@
+package reports
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"tg-bot/internal/storage"
+)
+
+func TestStore(t *testing.T) {
+	const chatID, messageID = -100, 42
+
+	type step struct {
+		name      string
+		op        string // file, claim yoki release
+		userID    int64  // Shikoyatchi yoki admin
+		want      error
+		created   bool
+		reporters []int64
+		handled   bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "reporters are merged",
+			steps: []step{
+				{name: "first report", op: "file", userID: 1, created: true, reporters: []int64{1}},
+				{name: "repeat report", op: "file", userID: 1, want: ErrAlreadyReported, reporters: []int64{1}},
+				{name: "second reporter", op: "file", userID: 2, reporters: []int64{1, 2}},
+				{name: "third reporter", op: "file", userID: 3, reporters: []int64{1, 2, 3}},
+			},
+		},
+		{
+			name: "only one admin handles a report",
+			steps: []step{
+				{name: "report", op: "file", userID: 1, created: true, reporters: []int64{1}},
+				{name: "first claim", op: "claim", userID: 10, handled: true},
+				{name: "second claim", op: "claim", userID: 11, want: ErrHandled, handled: true},
+				{name: "report after claim", op: "file", userID: 2, want: ErrHandled, reporters: []int64{1}, handled: true},
+			},
+		},
+		{
+			name: "release reopens the report",
+			steps: []step{
+				{name: "report", op: "file", userID: 1, created: true, reporters: []int64{1}},
+				{name: "claim", op: "claim", userID: 10, handled: true},
+				{name: "release", op: "release"},
+				{name: "claim again", op: "claim", userID: 11, handled: true},
+			},
+		},
+		{
+			name: "missing report",
+			steps: []step{
+				{name: "claim", op: "claim", userID: 10, want: storage.ErrNotFound},
+				{name: "release", op: "release", want: storage.ErrNotFound},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(storage.NewMemoryStore())
+			for _, st := range tt.steps {
+				var (
+					got     Report
+					created bool
+					err     error
+				)
+				switch st.op {
+				case "file":
+					got, created, err = s.File(Report{ChatID: chatID, MessageID: messageID, Text: "spam"}, st.userID)
+				case "claim":
+					got, err = s.Claim(chatID, messageID, "ban", st.userID, "admin")
+				case "release":
+					err = s.Release(chatID, messageID)
+				}
+				if !errors.Is(err, st.want) {
+					t.Fatalf("%s: error = %v, want %v", st.name, err, st.want)
+				}
+				if created != st.created {
+					t.Fatalf("%s: created = %v, want %v", st.name, created, st.created)
+				}
+				if errors.Is(err, storage.ErrNotFound) {
+					continue
+				}
+
+				saved, err := s.Get(chatID, messageID)
+				if err != nil {
+					t.Fatalf("%s: Get: %v", st.name, err)
+				}
+				if st.op == "file" && !slices.Equal(got.Reporters, st.reporters) {
+					t.Fatalf("%s: reporters = %v, want %v", st.name, got.Reporters, st.reporters)
+				}
+				if st.op == "file" && !slices.Equal(saved.Reporters, st.reporters) {
+					t.Fatalf("%s: saved reporters = %v, want %v", st.name, saved.Reporters, st.reporters)
+				}
+				if saved.Handled() != st.handled {
+					t.Fatalf("%s: handled = %v, want %v", st.name, saved.Handled(), st.handled)
+				}
+				// Ikkinchi admin birinchisining chorasini ko'radi, shikoyat egasi o'zgarmaydi
+				if st.op == "claim" && got.HandlerID != saved.HandlerID {
+					t.Fatalf("%s: claim returned handler %d, saved %d", st.name, got.HandlerID, saved.HandlerID)
+				}
+			}
+		})
+	}
+}
+
+func TestClaimConcurrent(t *testing.T) {
+	s := New(storage.NewMemoryStore())
+	if _, _, err := s.File(Report{ChatID: -100, MessageID: 1}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	claimed := 0
+	for admin := int64(10); admin < 20; admin++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Claim(-100, 1, "delete", admin, "admin"); err == nil {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrHandled) {
+				t.Errorf("Claim: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if claimed != 1 {
+		t.Fatalf("%d admins claimed the report, want 1", claimed)
+	}
+}
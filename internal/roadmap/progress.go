@@ -0,0 +1,89 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"tg-bot/internal/storage"
+)
+
+// progressBucket foydalanuvchilar belgilagan mavzular saqlanadigan bucket (kalit - foydalanuvchi ID si)
+const progressBucket = "roadmap_progress"
+
+// Progress foydalanuvchi o'zlashtirgan mavzular
+// Done kaliti "<bo'lim raqami>/<mavzu ID si>", qiymati belgilangan vaqt
+type Progress struct {
+	Done map[string]time.Time `json:"done"`
+}
+
+// itemKey mavzuning Progress.Done dagi kaliti
+func itemKey(section int, item string) string {
+	return fmt.Sprintf("%d/%s", section, item)
+}
+
+// Has mavzu belgilanganligini tekshiradi
+func (p Progress) Has(section int, item string) bool {
+	_, ok := p.Done[itemKey(section, item)]
+	return ok
+}
+
+// Count bo'limda belgilangan mavzular soni
+// Yo'l xaritasidan olib tashlangan mavzular hisobga olinmaydi
+func (p Progress) Count(section Section) int {
+	n := 0
+	for _, item := range section.Items {
+		if p.Has(section.ID, item.ID) {
+			n++
+		}
+	}
+	return n
+}
+
+// Tracker foydalanuvchilar yutuqlarini omborda yuritadi
+type Tracker struct {
+	store storage.Store
+	mu    sync.Mutex // O'qish-o'zgartirish-yozish amallarini ketma-ket bajarish uchun
+}
+
+// NewTracker yutuqlar omborini yaratadi
+func NewTracker(store storage.Store) *Tracker {
+	return &Tracker{store: store}
+}
+
+// Get foydalanuvchining belgilagan mavzularini qaytaradi
+func (t *Tracker) Get(userID int64) (Progress, error) {
+	var p Progress
+	err := storage.GetJSON(t.store, progressBucket, strconv.FormatInt(userID, 10), &p)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return Progress{}, err
+	}
+	if p.Done == nil {
+		p.Done = make(map[string]time.Time)
+	}
+	return p, nil
+}
+
+// Toggle mavzuni belgilaydi yoki belgini olib tashlaydi va yangilangan holatni qaytaradi
+func (t *Tracker) Toggle(userID int64, section int, item string) (Progress, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, err := t.Get(userID)
+	if err != nil {
+		return Progress{}, err
+	}
+	key := itemKey(section, item)
+	if _, ok := p.Done[key]; ok {
+		delete(p.Done, key)
+	} else {
+		p.Done[key] = time.Now().UTC()
+	}
+
+	if err := storage.PutJSON(t.store, progressBucket, strconv.FormatInt(userID, 10), p); err != nil {
+		return Progress{}, err
+	}
+	return p, nil
+}
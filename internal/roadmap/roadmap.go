@@ -0,0 +1,164 @@
+// Package roadmap Go o'rganish yo'l xaritasi va foydalanuvchilar o'zlashtirgan mavzular
+// Yo'l xaritasi YAML faylda bo'limlar, mavzular va ularga tanlangan manbalar ko'rinishida saqlanadi,
+// har bir foydalanuvchi belgilagan mavzular esa bot omborida yuritiladi
+package roadmap
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxTitleLength mavzu nomi uzunligi, nom inline tugmaga sig'ishi kerak
+const maxTitleLength = 48
+
+// idPattern mavzu identifikatori callback ma'lumotiga sig'ishi va xavfsiz bo'lishi uchun
+var idPattern = regexp.MustCompile(`^[a-z0-9-]{1,20}$`)
+
+// Resource mavzu bo'yicha tanlangan manba
+type Resource struct {
+	Title string `yaml:"title"`
+	URL   string `yaml:"url"`
+}
+
+// Item bo'limdagi bitta mavzu
+type Item struct {
+	ID        string     `yaml:"id"`        // Bo'lim ichidagi o'zgarmas identifikator
+	Title     string     `yaml:"title"`     // Mavzu nomi
+	Resources []Resource `yaml:"resources"` // Tanlangan manbalar
+}
+
+// Section yo'l xaritasi bo'limi
+type Section struct {
+	ID    int    `yaml:"id"`
+	Title string `yaml:"title"`
+	Items []Item `yaml:"items"`
+}
+
+// Item bo'limdagi mavzuni identifikatori bo'yicha qaytaradi
+func (s Section) Item(id string) (Item, bool) {
+	for _, item := range s.Items {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// Roadmap yo'l xaritasi
+// Reload orqali fayldan qayta yuklanishi mumkin, xatolik bo'lsa avvalgi ma'lumotlar saqlanib qoladi
+type Roadmap struct {
+	path string
+
+	mu       sync.RWMutex
+	sections []Section
+}
+
+// Load yo'l xaritasini fayldan yuklaydi va tekshiradi
+func Load(path string) (*Roadmap, error) {
+	r := &Roadmap{path: path}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload yo'l xaritasini fayldan qaytadan yuklaydi
+func (r *Roadmap) Reload() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("yo'l xaritasi faylini o'qib bo'lmadi: %w", err)
+	}
+
+	var file struct {
+		Sections []Section `yaml:"sections"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("%s: %w", r.path, err)
+	}
+	if err := validate(file.Sections); err != nil {
+		return fmt.Errorf("%s: %w", r.path, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sections = file.Sections
+	return nil
+}
+
+// validate yo'l xaritasidagi xatolarni aniqlaydi
+func validate(sections []Section) error {
+	if len(sections) == 0 {
+		return fmt.Errorf("bo'limlar topilmadi")
+	}
+
+	seen := make(map[int]bool, len(sections))
+	for _, s := range sections {
+		if s.ID <= 0 || s.Title == "" {
+			return fmt.Errorf("bo'lim %d: raqami va nomi bo'lishi kerak", s.ID)
+		}
+		if seen[s.ID] {
+			return fmt.Errorf("bo'lim %d ikki marta e'lon qilingan", s.ID)
+		}
+		seen[s.ID] = true
+		if len(s.Items) == 0 {
+			return fmt.Errorf("bo'lim %d: mavzular yo'q", s.ID)
+		}
+
+		items := make(map[string]bool, len(s.Items))
+		for _, item := range s.Items {
+			if !idPattern.MatchString(item.ID) {
+				return fmt.Errorf("bo'lim %d, mavzu %q: identifikator faqat kichik lotin harflari, raqamlar va chiziqchadan iborat bo'lishi kerak", s.ID, item.ID)
+			}
+			if items[item.ID] {
+				return fmt.Errorf("bo'lim %d, mavzu %q ikki marta uchraydi", s.ID, item.ID)
+			}
+			items[item.ID] = true
+			if item.Title == "" || utf8.RuneCountInString(item.Title) > maxTitleLength {
+				return fmt.Errorf("bo'lim %d, mavzu %q: nomi bo'sh yoki %d belgidan uzun", s.ID, item.ID, maxTitleLength)
+			}
+			for _, res := range item.Resources {
+				u, err := url.Parse(res.URL)
+				if res.Title == "" || err != nil || u.Scheme != "https" || u.Host == "" {
+					return fmt.Errorf("bo'lim %d, mavzu %q: manba nomi bo'lishi va havolasi https bilan boshlanishi kerak", s.ID, item.ID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Sections yo'l xaritasi bo'limlarini qaytaradi
+func (r *Roadmap) Sections() []Section {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Section(nil), r.sections...)
+}
+
+// Section bo'limni raqami bo'yicha qaytaradi
+func (r *Roadmap) Section(id int) (Section, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.sections {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+// Total yo'l xaritasidagi barcha mavzular soni
+func (r *Roadmap) Total() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	total := 0
+	for _, s := range r.sections {
+		total += len(s.Items)
+	}
+	return total
+}
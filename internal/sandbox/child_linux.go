@@ -0,0 +1,138 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// childArg bot sandbox yordamchisi sifatida qayta chaqirilganini bildiruvchi argument
+const childArg = "__sandbox_child"
+
+// childFailed yordamchi foydalanuvchi dasturini ishga tushira olmaganda qaytaradigan chiqish kodi
+const childFailed = 125
+
+// Yordamchi o'rnatadigan qo'shimcha chegaralar
+const (
+	maxFileSize = 1 << 20 // Dastur yarata oladigan faylning eng katta hajmi
+	maxOpenFile = 64      // Ochiq fayl deskriptorlari soni
+)
+
+// prctl amallari va securebits (linux/prctl.h, linux/securebits.h)
+const (
+	prSetSecurebits    = 28
+	prSetNoNewPrivs    = 38
+	secbitNoroot       = 1 << 0
+	secbitNorootLocked = 1 << 1
+)
+
+// IsChild jarayon sandbox yordamchisi sifatida ishga tushirilganini tekshiradi
+// main funksiyasining eng boshida chaqirilishi kerak
+func IsChild() bool {
+	return len(os.Args) > 1 && os.Args[1] == childArg
+}
+
+// ChildMain sandbox yordamchisining asosiy funksiyasi, hech qachon qaytmaydi
+// Yordamchi yangi namespace'lar ichida ishga tushadi: dastur katalogiga chroot qiladi,
+// rlimit'larni o'rnatadi, imtiyozlardan voz kechadi va foydalanuvchi dasturini exec qiladi
+func ChildMain() {
+	if err := child(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(childFailed)
+	}
+	// Argumentlarsiz chaqiruv faqat namespace'lar yaratilishini tekshirish uchun
+	os.Exit(0)
+}
+
+// child yordamchi amallarini bajaradi
+func child(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if len(args) != 3 {
+		return errors.New("sandbox: yordamchi argumentlari noto'g'ri")
+	}
+	dir := args[0]
+	cpu, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return err
+	}
+	memory, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if err := syscall.Chroot(dir); err != nil {
+		return fmt.Errorf("chroot: %w", err)
+	}
+	if err := syscall.Chdir("/"); err != nil {
+		return fmt.Errorf("chdir: %w", err)
+	}
+
+	limits := []struct {
+		resource int
+		value    uint64
+	}{
+		{syscall.RLIMIT_CPU, cpu},
+		{syscall.RLIMIT_DATA, memory},
+		{syscall.RLIMIT_FSIZE, maxFileSize},
+		{syscall.RLIMIT_NOFILE, maxOpenFile},
+		{syscall.RLIMIT_CORE, 0},
+	}
+	for _, l := range limits {
+		if l.value == 0 && l.resource != syscall.RLIMIT_CORE {
+			continue
+		}
+		if err := syscall.Setrlimit(l.resource, &syscall.Rlimit{Cur: l.value, Max: l.value}); err != nil {
+			return fmt.Errorf("setrlimit %d: %w", l.resource, err)
+		}
+	}
+
+	// Namespace ichidagi root exec'dan keyin imtiyozlarni qayta olmasligi uchun
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetSecurebits, secbitNoroot|secbitNorootLocked, 0); errno != 0 {
+		return fmt.Errorf("securebits: %w", errno)
+	}
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0); errno != 0 {
+		return fmt.Errorf("no_new_privs: %w", errno)
+	}
+
+	return syscall.Exec("/prog", []string{"prog"}, []string{"GOMAXPROCS=2"})
+}
+
+// command yordamchini yangi user, tarmoq, PID, mount, IPC va UTS namespace'larida ishga tushiradigan buyruq
+// Tarmoq namespace'ida faqat o'chirilgan loopback bor, shuning uchun dastur tashqariga ulana olmaydi.
+// dir bo'sh bo'lsa yordamchi hech narsa bajarmasdan chiqadi (tekshiruv uchun)
+func (r *Runner) command(ctx context.Context, dir string) (*exec.Cmd, error) {
+	args := []string{childArg}
+	if dir != "" {
+		cpu := uint64(math.Ceil(r.cfg.RunTimeout.Seconds()))
+		args = append(args, dir, strconv.FormatUint(cpu, 10), strconv.FormatInt(r.cfg.MemoryLimit, 10))
+	}
+
+	cmd := exec.CommandContext(ctx, r.exe, args...)
+	cmd.Env = []string{}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET | syscall.CLONE_NEWPID |
+			syscall.CLONE_NEWNS | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+		Pdeathsig:   syscall.SIGKILL,
+	}
+	return cmd, nil
+}
+
+// signalName dasturni to'xtatgan signal nomini qaytaradi
+func signalName(err *exec.ExitError) string {
+	status, ok := err.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+	return status.Signal().String()
+}
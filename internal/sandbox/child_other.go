@@ -0,0 +1,29 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+)
+
+// childFailed yordamchi foydalanuvchi dasturini ishga tushira olmaganda qaytaradigan chiqish kodi
+const childFailed = 125
+
+// IsChild Linux'dan boshqa tizimlarda sandbox yordamchisi yo'q
+func IsChild() bool {
+	return false
+}
+
+// ChildMain Linux'dan boshqa tizimlarda hech narsa qilmaydi
+func ChildMain() {}
+
+// command namespace'lar faqat Linux'da mavjud, shuning uchun sandbox ishlamaydi
+func (r *Runner) command(ctx context.Context, dir string) (*exec.Cmd, error) {
+	return nil, ErrUnsupported
+}
+
+// signalName Linux'dan boshqa tizimlarda signal aniqlanmaydi
+func signalName(err *exec.ExitError) string {
+	return ""
+}
@@ -0,0 +1,444 @@
+// Package sandbox foydalanuvchi yuborgan Go dasturlarini cheklangan muhitda kompilyatsiya qilib ishga tushiradi
+// Dastur bot kompyuteridagi Go toolchain bilan vaqtinchalik modul katalogida yig'iladi va alohida
+// namespace'larda (tarmoqsiz), chroot ichida, CPU/xotira/vaqt chegaralari bilan bajariladi.
+// Ishlar cheklangan navbat orqali o'tadi, har bir foydalanuvchi uchun kvota belgilanadi
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tg-bot/pkg/logger"
+)
+
+// Xatoliklar
+var (
+	ErrUnsupported = errors.New("sandbox: bu tizimda qo'llab-quvvatlanmaydi")
+	ErrQueueFull   = errors.New("sandbox: navbat to'la")
+	ErrPending     = errors.New("sandbox: foydalanuvchining avvalgi dasturi hali bajarilmoqda")
+)
+
+// DefaultImports ruxsat etilgan importlarning standart ro'yxati
+// Fayl tizimi, tarmoq, jarayonlar va unsafe bilan ishlaydigan paketlar ataylab kiritilmagan
+var DefaultImports = []string{
+	"bufio", "bytes", "cmp", "container/heap", "container/list", "container/ring", "context",
+	"encoding/base64", "encoding/binary", "encoding/csv", "encoding/hex", "encoding/json",
+	"errors", "fmt", "hash/crc32", "hash/fnv", "iter", "maps", "math", "math/big", "math/bits",
+	"math/cmplx", "math/rand", "math/rand/v2", "regexp", "slices", "sort", "strconv", "strings",
+	"sync", "sync/atomic", "text/tabwriter", "text/template", "time", "unicode", "unicode/utf16",
+	"unicode/utf8",
+}
+
+// Config sandbox sozlamalari
+type Config struct {
+	GoBinary       string        // go buyrug'i (PATH dagi nomi yoki to'liq yo'li)
+	WorkDir        string        // Vaqtinchalik kataloglar va kompilyatsiya keshi joyi
+	BuildTimeout   time.Duration // Kompilyatsiya uchun eng ko'p vaqt
+	RunTimeout     time.Duration // Dastur ishlashi uchun eng ko'p vaqt (devor soati bo'yicha)
+	MemoryLimit    int64         // Dastur xotirasi chegarasi, RLIMIT_DATA (baytlarda)
+	MaxOutput      int           // Qaytariladigan chiqishning eng katta hajmi (baytlarda)
+	AllowedImports []string      // Ruxsat etilgan importlar (bo'sh - DefaultImports)
+	Workers        int           // Bir vaqtda bajariladigan dasturlar soni
+	QueueSize      int           // Navbatda kutishi mumkin bo'lgan dasturlar soni
+	UserQuota      int           // Bitta foydalanuvchi QuotaWindow ichida ishga tushira oladigan dasturlar soni
+	QuotaWindow    time.Duration // Kvota hisoblanadigan oraliq
+}
+
+// ImportError dasturda ruxsat etilmagan paketlar import qilinganini bildiradi
+type ImportError struct {
+	Denied []string
+}
+
+func (e *ImportError) Error() string {
+	return "sandbox: ruxsat etilmagan importlar: " + strings.Join(e.Denied, ", ")
+}
+
+// QuotaError foydalanuvchi kvotasi tugaganini bildiradi
+type QuotaError struct {
+	RetryAfter time.Duration // Keyingi dasturni qachon yuborish mumkin
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("sandbox: kvota tugadi, %s dan keyin qayta urinish mumkin", e.RetryAfter)
+}
+
+// Stage dastur qaysi bosqichda to'xtaganini bildiradi
+type Stage string
+
+// Bajarish bosqichlari
+const (
+	StageBuild Stage = "build" // Kompilyatsiya
+	StageRun   Stage = "run"   // Bajarish
+)
+
+// Result dasturni bajarish natijasi
+type Result struct {
+	Stage     Stage         // Natija qaysi bosqichga tegishli
+	Output    string        // stdout va stderr birgalikda (yoki kompilyatsiya xatolari)
+	Truncated bool          // Chiqish MaxOutput dan uzun bo'lib qisqartirilgan
+	ExitCode  int           // Dasturning chiqish kodi (signal bilan to'xtatilsa -1)
+	Signal    string        // Dasturni to'xtatgan signal (masalan, CPU chegarasida "CPU time limit exceeded")
+	TimedOut  bool          // Dastur vaqt chegarasiga sig'madi
+	Elapsed   time.Duration // Bajarish davomiyligi
+	Err       error         // Sandbox'ning o'zidagi xatolik (dastur xatosi emas)
+}
+
+// job navbatdagi bitta dastur
+type job struct {
+	userID  int64
+	program string
+	done    func(Result)
+}
+
+// Runner dasturlar navbati va ularni bajaruvchi ishchilar
+type Runner struct {
+	cfg     Config
+	exe     string          // Bot fayli, sandbox ichida qayta chaqiriladi
+	goBin   string          // go buyrug'ining to'liq yo'li
+	allowed map[string]bool // Ruxsat etilgan importlar
+	jobs    chan job
+	log     *logger.Logger
+
+	mu      sync.Mutex
+	busy    int                   // Hozir dastur bajarayotgan ishchilar soni
+	pending map[int64]bool        // Navbatda yoki bajarilayotgan dasturi bor foydalanuvchilar
+	history map[int64][]time.Time // Kvota uchun foydalanuvchi dasturlari vaqtlari
+}
+
+// New sandbox'ni tayyorlaydi va uning bu tizimda ishlashini tekshiradi
+// Go toolchain topilmasa yoki namespace'larni yaratib bo'lmasa xatolik qaytariladi
+func New(cfg Config, log *logger.Logger) (*Runner, error) {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize < 0 {
+		cfg.QueueSize = 0
+	}
+	if len(cfg.AllowedImports) == 0 {
+		cfg.AllowedImports = DefaultImports
+	}
+	if cfg.WorkDir == "" {
+		cfg.WorkDir = filepath.Join(os.TempDir(), "tg-bot-sandbox")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("bot fayli aniqlanmadi: %w", err)
+	}
+	goBin, err := exec.LookPath(cfg.GoBinary)
+	if err != nil {
+		return nil, fmt.Errorf("go toolchain topilmadi: %w", err)
+	}
+	if err := os.MkdirAll(cfg.WorkDir, 0o700); err != nil {
+		return nil, fmt.Errorf("sandbox katalogini yaratib bo'lmadi: %w", err)
+	}
+
+	r := &Runner{
+		cfg:     cfg,
+		exe:     exe,
+		goBin:   goBin,
+		allowed: make(map[string]bool, len(cfg.AllowedImports)),
+		jobs:    make(chan job, cfg.QueueSize),
+		log:     log,
+		pending: make(map[int64]bool),
+		history: make(map[int64][]time.Time),
+	}
+	for _, path := range cfg.AllowedImports {
+		r.allowed[path] = true
+	}
+
+	if err := r.probe(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Run ishchilarni ishga tushiradi va ctx bekor qilinguncha kutadi
+// Navbatda qolgan dasturlar to'xtatishda bajarilmaydi
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range r.cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-r.jobs:
+					r.mu.Lock()
+					r.busy++
+					r.mu.Unlock()
+
+					result := r.execute(ctx, j.program)
+					r.finish(j.userID)
+					if result.Err != nil {
+						r.log.Errorf("%d foydalanuvchisining dasturini bajarishda sandbox xatoligi: %v", j.userID, result.Err)
+					} else {
+						r.log.Debugf("%d foydalanuvchisining dasturi bajarildi: %s, chiqish kodi %d, %s", j.userID, result.Stage, result.ExitCode, result.Elapsed)
+					}
+					if ctx.Err() == nil {
+						j.done(result)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Submit dasturni navbatga qo'yadi va uning navbatdagi o'rnini qaytaradi (0 - darhol bajariladi)
+// done dastur bajarilgandan keyin ishchi goroutine'dan chaqiriladi.
+// Importlar, foydalanuvchi kvotasi va navbat hajmi shu yerda tekshiriladi
+func (r *Runner) Submit(userID int64, program string, done func(Result)) (int, error) {
+	if err := r.checkImports(program); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pending[userID] {
+		return 0, ErrPending
+	}
+
+	now := time.Now()
+	recent := r.history[userID][:0]
+	for _, at := range r.history[userID] {
+		if now.Sub(at) < r.cfg.QuotaWindow {
+			recent = append(recent, at)
+		}
+	}
+	r.history[userID] = recent
+	if r.cfg.UserQuota > 0 && len(recent) >= r.cfg.UserQuota {
+		return 0, &QuotaError{RetryAfter: r.cfg.QuotaWindow - now.Sub(recent[0])}
+	}
+
+	select {
+	case r.jobs <- job{userID: userID, program: program, done: done}:
+	default:
+		return 0, ErrQueueFull
+	}
+	r.pending[userID] = true
+	r.history[userID] = append(recent, now)
+
+	// Bo'sh ishchi bo'lsa dastur darhol bajariladi, aks holda navbatdagi o'rni qaytariladi
+	if r.busy < r.cfg.Workers {
+		return 0, nil
+	}
+	return len(r.jobs), nil
+}
+
+// finish foydalanuvchining navbatdagi dasturi tugaganini qayd etadi
+func (r *Runner) finish(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.busy--
+	delete(r.pending, userID)
+}
+
+// checkImports dasturdagi barcha importlar ruxsat etilganini tekshiradi
+func (r *Runner) checkImports(program string) error {
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", program, parser.ImportsOnly)
+	if err != nil {
+		return err
+	}
+
+	var denied []string
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || !r.allowed[path] {
+			denied = append(denied, strings.Trim(spec.Path.Value, `"`))
+		}
+	}
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return &ImportError{Denied: denied}
+	}
+	return nil
+}
+
+// execute dasturni vaqtinchalik modul katalogida kompilyatsiya qiladi va sandbox ichida bajaradi
+func (r *Runner) execute(ctx context.Context, program string) Result {
+	dir, err := os.MkdirTemp(r.cfg.WorkDir, "run-")
+	if err != nil {
+		return Result{Stage: StageBuild, Err: err}
+	}
+	defer os.RemoveAll(dir)
+
+	if err := r.build(ctx, dir, program); err != nil {
+		var build *buildError
+		if errors.As(err, &build) {
+			return Result{Stage: StageBuild, Output: build.output, Truncated: build.truncated, ExitCode: 1}
+		}
+		return Result{Stage: StageBuild, Err: err}
+	}
+	return r.run(ctx, dir)
+}
+
+// buildError kompilyator xatolarini bildiradi
+type buildError struct {
+	output    string
+	truncated bool
+}
+
+func (e *buildError) Error() string {
+	return "sandbox: kompilyatsiya xatosi"
+}
+
+// build dasturni statik fayl sifatida kompilyatsiya qiladi
+// Kompilyatsiya keshi ishlar orasida saqlanadi, modul yuklash va cgo o'chirilgan
+func (r *Runner) build(ctx context.Context, dir, program string) error {
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module sandbox\n"), 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0o600); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.BuildTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, r.goBin, "build", "-trimpath", "-ldflags=-s -w", "-o", "prog", ".")
+	cmd.Dir = dir
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + dir,
+		"GOCACHE=" + filepath.Join(r.cfg.WorkDir, "cache"),
+		"GOPATH=" + filepath.Join(r.cfg.WorkDir, "gopath"),
+		"GOPROXY=off",
+		"GOFLAGS=-mod=mod",
+		"GOTOOLCHAIN=local",
+		"GOWORK=off",
+		"CGO_ENABLED=0",
+	}
+	output := newLimitedBuffer(r.cfg.MaxOutput)
+	cmd.Stdout, cmd.Stderr = output, output
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("kompilyatsiya %s ichida tugamadi", r.cfg.BuildTimeout)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &buildError{output: cleanBuildOutput(output.String()), truncated: output.truncated}
+		}
+		return err
+	}
+	return nil
+}
+
+// cleanBuildOutput kompilyator chiqishidan vaqtinchalik yo'llar va paket sarlavhasini olib tashlaydi
+func cleanBuildOutput(output string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if strings.HasPrefix(line, "# ") {
+			continue
+		}
+		lines = append(lines, strings.TrimPrefix(line, "./"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// run kompilyatsiya qilingan dasturni sandbox ichida bajaradi
+func (r *Runner) run(ctx context.Context, dir string) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.RunTimeout)
+	defer cancel()
+
+	cmd, err := r.command(ctx, dir)
+	if err != nil {
+		return Result{Stage: StageRun, Err: err}
+	}
+	output := newLimitedBuffer(r.cfg.MaxOutput)
+	cmd.Stdout, cmd.Stderr = output, output
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	result := Result{
+		Stage:     StageRun,
+		Elapsed:   time.Since(start),
+		Output:    output.String(),
+		Truncated: output.truncated,
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case ctx.Err() == context.DeadlineExceeded:
+		result.TimedOut = true
+		result.ExitCode = -1
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		if exitErr.ExitCode() == childFailed {
+			// Sandbox'ning o'zi ishga tushmadi, foydalanuvchi dasturi bajarilmagan
+			result.Err = fmt.Errorf("sandbox ishga tushmadi: %s", strings.TrimSpace(result.Output))
+			result.Output = ""
+		} else if sig := signalName(exitErr); sig != "" {
+			result.Signal = sig
+		}
+	default:
+		result.Err = err
+	}
+	return result
+}
+
+// probe namespace'lar yaratilishi mumkinligini bo'sh ishga tushirish bilan tekshiradi
+func (r *Runner) probe() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd, err := r.command(ctx, "")
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sandbox namespace'larini yaratib bo'lmadi: %w %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// limitedBuffer ko'pi bilan limit bayt saqlaydigan, qolganini tashlab yuboradigan io.Writer
+// Dastur chiqishni to'xtatmasa ham yozish xatosiz davom etadi, aks holda dastur SIGPIPE bilan tugardi
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func newLimitedBuffer(limit int) *limitedBuffer {
+	return &limitedBuffer{limit: limit}
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - b.buf.Len(); b.limit > 0 && len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+// String yig'ilgan chiqishni yaroqli UTF-8 matn sifatida qaytaradi
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.ToValidUTF8(b.buf.String(), "�")
+}
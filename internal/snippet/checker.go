@@ -0,0 +1,242 @@
+package snippet
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Checker kod parchalarini formatlaydi va standart kutubxonaga nisbatan turlar bo'yicha tekshiradi
+// Import qilingan paketlar GOROOT manbasidan bir marta tahlil qilinib keshlanadi.
+// Kesh bir vaqtda ishlatib bo'lmaydigani uchun tekshiruvlar navbat bilan bajariladi
+type Checker struct {
+	limits Limits
+	slot   chan struct{}      // Bir vaqtda faqat bitta tekshiruv
+	fset   *token.FileSet     // Import qilingan paketlar pozitsiyalari
+	imp    types.ImporterFrom // Standart kutubxona paketlarini yuklovchi
+}
+
+// NewChecker berilgan chegaralar bilan yangi Checker yaratadi
+func NewChecker(limits Limits) *Checker {
+	fset := token.NewFileSet()
+	return &Checker{
+		limits: limits,
+		slot:   make(chan struct{}, 1),
+		fset:   fset,
+		imp:    importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+	}
+}
+
+// Limits amaldagi chegaralarni qaytaradi
+func (c *Checker) Limits() Limits {
+	return c.limits
+}
+
+// Format kod parchasini gofmt qoidalari bo'yicha formatlaydi
+// Natijadan qo'shilgan o'ram olib tashlanadi, ya'ni foydalanuvchi yuborgan ko'rinishdagi kod qaytariladi
+func (c *Checker) Format(code string) (string, error) {
+	if err := checkSize(code, c.limits); err != nil {
+		return "", err
+	}
+	return withTimeout(c.limits.Timeout, func() (string, error) {
+		u, err := parse(code)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := format.Node(&buf, u.fset, u.file); err != nil {
+			return "", err
+		}
+		return unwrap(buf.String(), u.mode), nil
+	})
+}
+
+// unwrap formatlangan fayldan parse qo'shgan o'ramni olib tashlaydi
+func unwrap(formatted string, mode wrapMode) string {
+	switch mode {
+	case wrapPackage:
+		_, rest, _ := strings.Cut(formatted, "\n")
+		return strings.TrimLeft(rest, "\n")
+	case wrapMain:
+		_, body, _ := strings.Cut(formatted, "func main() {\n")
+		body = strings.TrimRight(strings.TrimSuffix(body, "}\n"), "\n")
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimPrefix(line, "\t")
+		}
+		return strings.Join(lines, "\n") + "\n"
+	default:
+		return formatted
+	}
+}
+
+// Vet kod parchasini tahlil qiladi va go/types yordamida tekshiradi
+// Sintaksis va kompilyatsiya xatolari qator raqamlari bilan qaytariladi, xato bo'lmasa ro'yxat bo'sh bo'ladi.
+// Boshqa tekshiruv ishlayotgan bo'lsa, u tugashi timeout davomida kutiladi, keyin ErrBusy qaytariladi
+func (c *Checker) Vet(code string) ([]Problem, error) {
+	_, problems, err := c.check(code)
+	return problems, err
+}
+
+// Program kod parchasini ishga tushirishga tayyor to'liq dasturga aylantiradi
+// Parcha avval Vet kabi tekshiriladi: muammolar topilsa dastur o'rniga ular qaytariladi.
+// Natijada "package main", func main o'rami va unutilgan importlar qo'shilgan bo'ladi
+func (c *Checker) Program(code string) (string, []Problem, error) {
+	u, problems, err := c.check(code)
+	if err != nil || len(problems) > 0 {
+		return "", problems, err
+	}
+	// Kompilyator bularni faqat bog'lash bosqichida aniqlaydi, go/types esa umuman tekshirmaydi
+	if u.file.Name.Name != "main" {
+		return "", []Problem{{Message: "package " + u.file.Name.Name + " is not a main package"}}, nil
+	}
+	if !hasMain(u.file) {
+		return "", []Problem{{Message: "function main is undeclared in the main package"}}, nil
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, u.fset, u.file); err != nil {
+		return "", nil, err
+	}
+	return buf.String(), nil, nil
+}
+
+// hasMain faylda func main e'lon qilinganini tekshiradi
+func hasMain(file *ast.File) bool {
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// check kod parchasini tahlil qiladi va turlar bo'yicha tekshiradi
+// Tekshiruv navbat bilan va vaqt chegarasi ichida bajariladi
+func (c *Checker) check(code string) (*unit, []Problem, error) {
+	if err := checkSize(code, c.limits); err != nil {
+		return nil, nil, err
+	}
+
+	start := time.Now()
+	if !c.acquire() {
+		return nil, nil, ErrBusy
+	}
+	// Navbatda kutilgan vaqt ham umumiy chegaraga kiradi
+	timeout := c.limits.Timeout
+	if timeout > 0 {
+		timeout = max(timeout-time.Since(start), time.Millisecond)
+	}
+
+	type result struct {
+		unit     *unit
+		problems []Problem
+	}
+	r, err := withTimeout(timeout, func() (result, error) {
+		defer c.release()
+
+		u, err := parse(code)
+		if err != nil {
+			if syntax, ok := err.(*SyntaxError); ok {
+				return result{problems: syntax.Problems}, nil
+			}
+			return result{}, err
+		}
+		// To'liq fayl yuborilgan bo'lsa, unutilgan import haqiqiy xato hisoblanadi
+		if u.mode != wrapNone {
+			addMissingImports(u.file)
+		}
+
+		var problems []Problem
+		conf := types.Config{
+			Importer: stdImporter{c.imp},
+			Error: func(err error) {
+				if len(problems) == maxProblems {
+					return
+				}
+				if terr, ok := err.(types.Error); ok {
+					problems = append(problems, u.problem(terr.Fset.Position(terr.Pos), terr.Msg))
+					return
+				}
+				problems = append(problems, Problem{Message: err.Error()})
+			},
+		}
+		// Xatolar Error orqali yig'iladi, shuning uchun qaytarilgan birinchi xato kerak emas
+		_, _ = conf.Check("main", u.fset, []*ast.File{u.file}, nil)
+		sort.SliceStable(problems, func(i, j int) bool {
+			if problems[i].Line != problems[j].Line {
+				return problems[i].Line < problems[j].Line
+			}
+			return problems[i].Column < problems[j].Column
+		})
+		return result{unit: u, problems: problems}, nil
+	})
+	return r.unit, r.problems, err
+}
+
+// acquire tekshiruv navbatini egallaydi, timeout ichida bo'shamasa false qaytaradi
+func (c *Checker) acquire() bool {
+	select {
+	case c.slot <- struct{}{}:
+		return true
+	default:
+	}
+	if c.limits.Timeout <= 0 {
+		c.slot <- struct{}{}
+		return true
+	}
+
+	timer := time.NewTimer(c.limits.Timeout)
+	defer timer.Stop()
+	select {
+	case c.slot <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// release tekshiruv navbatini bo'shatadi
+func (c *Checker) release() {
+	<-c.slot
+}
+
+// stdImporter faqat standart kutubxona paketlarini import qilishga ruxsat beradi
+// Aks holda foydalanuvchi kodi bot kompyuteridagi ixtiyoriy katalogni tahlil qildirishi mumkin edi
+type stdImporter struct {
+	types.ImporterFrom
+}
+
+func (i stdImporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, "", 0)
+}
+
+func (i stdImporter) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	if !isStd(path) {
+		return nil, fmt.Errorf("package %s is not in std", path)
+	}
+	return i.ImporterFrom.ImportFrom(path, "", mode)
+}
+
+// isStd import yo'li standart kutubxonaning ommaviy paketi ekanini tekshiradi
+func isStd(path string) bool {
+	if path == "" || path == "C" || strings.HasPrefix(path, ".") || strings.HasPrefix(path, "/") {
+		return false
+	}
+	first, _, _ := strings.Cut(path, "/")
+	if strings.Contains(first, ".") {
+		return false
+	}
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "internal" || elem == "vendor" || elem == "testdata" || elem == ".." {
+			return false
+		}
+	}
+	return true
+}
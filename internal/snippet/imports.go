@@ -0,0 +1,84 @@
+package snippet
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+)
+
+// knownPackages chatdagi qisqa kod parchalarida import qilinmasdan ishlatiladigan paketlar
+// Kalit kodda ishlatiladigan nom, qiymat esa import yo'li
+var knownPackages = map[string]string{
+	"bufio":    "bufio",
+	"bytes":    "bytes",
+	"context":  "context",
+	"errors":   "errors",
+	"fmt":      "fmt",
+	"http":     "net/http",
+	"io":       "io",
+	"json":     "encoding/json",
+	"log":      "log",
+	"maps":     "maps",
+	"math":     "math",
+	"os":       "os",
+	"rand":     "math/rand",
+	"reflect":  "reflect",
+	"regexp":   "regexp",
+	"runtime":  "runtime",
+	"slices":   "slices",
+	"sort":     "sort",
+	"strconv":  "strconv",
+	"strings":  "strings",
+	"sync":     "sync",
+	"atomic":   "sync/atomic",
+	"time":     "time",
+	"unicode":  "unicode",
+	"utf8":     "unicode/utf8",
+	"filepath": "path/filepath",
+}
+
+// addMissingImports e'lon qilinmagan, lekin paket sifatida ishlatilgan nomlar uchun import qo'shadi
+// Shunda "fmt.Println(x)" kabi parchalar har safar "undefined: fmt" xatosini bermaydi
+func addMissingImports(file *ast.File) {
+	imported := make(map[string]bool)
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		imported[path] = true
+	}
+	unresolved := make(map[*ast.Ident]bool, len(file.Unresolved))
+	for _, ident := range file.Unresolved {
+		unresolved[ident] = true
+	}
+
+	missing := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && unresolved[x] {
+			if path, ok := knownPackages[x.Name]; ok && !imported[path] {
+				missing[path] = true
+			}
+		}
+		return true
+	})
+	if len(missing) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(missing))
+	for path := range missing {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	decl := &ast.GenDecl{Tok: token.IMPORT}
+	for _, path := range paths {
+		spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}
+		decl.Specs = append(decl.Specs, spec)
+		file.Imports = append(file.Imports, spec)
+	}
+	file.Decls = append([]ast.Decl{decl}, file.Decls...)
+}
@@ -0,0 +1,194 @@
+// Package snippet chatga yuborilgan Go kod parchalarini formatlash va tekshirish
+// Hamma ish bot jarayonining ichida go/format, go/parser va go/types yordamida bajariladi,
+// tashqi dastur ishga tushirilmaydi. Hajm va vaqt chegaralari suiiste'mol qilishning oldini oladi
+package snippet
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"strings"
+	"time"
+)
+
+// Xatoliklar
+var (
+	ErrEmpty    = errors.New("snippet: kod bo'sh")
+	ErrTooLarge = errors.New("snippet: kod hajmi chegaradan katta")
+	ErrTimeout  = errors.New("snippet: kutish muddati tugadi")
+	ErrBusy     = errors.New("snippet: tekshiruvchi band")
+)
+
+// maxProblems bitta javobda ko'rsatiladigan muammolar soni
+const maxProblems = 10
+
+// Limits kod parchalarini qayta ishlash chegaralari
+type Limits struct {
+	MaxBytes int           // Kodning eng katta hajmi (baytlarda)
+	Timeout  time.Duration // Bitta amal uchun eng ko'p vaqt
+}
+
+// Problem kodda topilgan bitta muammo
+// Qator raqamlari foydalanuvchi yuborgan kod bo'yicha hisoblanadi, qo'shilgan o'ram hisobga olinmaydi
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// String muammoni "qator:ustun: xabar" ko'rinishida qaytaradi
+func (p Problem) String() string {
+	if p.Line <= 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+// SyntaxError kodni tahlil qilib bo'lmaganini bildiradi
+type SyntaxError struct {
+	Problems []Problem
+}
+
+func (e *SyntaxError) Error() string {
+	if len(e.Problems) == 0 {
+		return "snippet: sintaksis xatosi"
+	}
+	return "snippet: " + e.Problems[0].String()
+}
+
+// wrapMode kod parchasi to'liq faylga aylantirilishi uchun qanday o'ralgani
+type wrapMode int
+
+const (
+	wrapNone    wrapMode = iota // Kod o'zi to'liq fayl
+	wrapPackage                 // Boshiga "package main" qo'shilgan
+	wrapMain                    // "func main() { ... }" ichiga joylangan
+)
+
+// Har bir o'ram turi uchun kod oldidan qo'shiladigan matn
+var wrapPrefix = map[wrapMode]string{
+	wrapNone:    "",
+	wrapPackage: "package main\n\n",
+	wrapMain:    "package main\n\nfunc main() {\n",
+}
+
+// wrapSuffix wrapMain rejimida kod oxiriga qo'shiladigan matn
+const wrapSuffix = "\n}\n"
+
+// unit tahlil qilingan kod parchasi
+type unit struct {
+	fset   *token.FileSet
+	file   *ast.File
+	source string   // O'ralgan to'liq manba
+	mode   wrapMode // Qanday o'ralgani
+	lines  int      // Foydalanuvchi kodidagi qatorlar soni
+}
+
+// offset o'ram qo'shgan qatorlar soni
+func (u *unit) offset() int {
+	return strings.Count(wrapPrefix[u.mode], "\n")
+}
+
+// problem pozitsiyani foydalanuvchi kodidagi qatorga o'girib muammo yaratadi
+func (u *unit) problem(pos token.Position, msg string) Problem {
+	line := pos.Line - u.offset()
+	column := pos.Column
+	// O'ramning o'ziga tushgan pozitsiyalar (masalan, yopilmagan qavs) qatorsiz ko'rsatiladi
+	if line < 1 || line > u.lines {
+		line, column = 0, 0
+	}
+	return Problem{Line: line, Column: column, Message: msg}
+}
+
+// checkSize kod bo'sh emasligini va hajm chegarasidan oshmasligini tekshiradi
+func checkSize(code string, limits Limits) error {
+	if strings.TrimSpace(code) == "" {
+		return ErrEmpty
+	}
+	if limits.MaxBytes > 0 && len(code) > limits.MaxBytes {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// parse kod parchasini to'liq Go fayli sifatida tahlil qiladi
+// Kodda paket e'loni bo'lmasa, avval "package main" qo'shib, keyin esa func main ichiga joylab ko'riladi.
+// Hech biri to'g'ri kelmasa, xatosi kodning eng uzoq qismida uchragan urinish natijasi qaytariladi
+func parse(code string) (*unit, error) {
+	modes := []wrapMode{wrapNone}
+	if !hasPackageClause(code) {
+		modes = []wrapMode{wrapPackage, wrapMain}
+	}
+
+	var best *unit
+	var bestErr scanner.ErrorList
+	for _, mode := range modes {
+		u := &unit{fset: token.NewFileSet(), mode: mode, source: wrapPrefix[mode] + code, lines: strings.Count(code, "\n") + 1}
+		if mode == wrapMain {
+			u.source += wrapSuffix
+		}
+		file, err := parser.ParseFile(u.fset, "main.go", u.source, parser.ParseComments|parser.AllErrors)
+		if err == nil {
+			u.file = file
+			return u, nil
+		}
+
+		var list scanner.ErrorList
+		if !errors.As(err, &list) || len(list) == 0 {
+			return nil, err
+		}
+		// Xato qanchalik uzoqda bo'lsa, o'ram shunchalik to'g'ri tanlangan deb hisoblaymiz
+		if best == nil || list[0].Pos.Line-u.offset() > bestErr[0].Pos.Line-best.offset() {
+			best, bestErr = u, list
+		}
+	}
+
+	syntax := &SyntaxError{}
+	for _, e := range bestErr {
+		if len(syntax.Problems) == maxProblems {
+			break
+		}
+		syntax.Problems = append(syntax.Problems, best.problem(e.Pos, e.Msg))
+	}
+	return nil, syntax
+}
+
+// hasPackageClause kod "package" kalit so'zi bilan boshlanishini tekshiradi (izohlardan keyin)
+func hasPackageClause(code string) bool {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", -1, len(code)), []byte(code), nil, 0)
+	_, tok, _ := s.Scan()
+	return tok == token.PACKAGE
+}
+
+// withTimeout fn ni alohida goroutine'da bajaradi va timeout o'tgach kutishni to'xtatadi
+// go/types bekor qilishni qo'llab-quvvatlamaydi, shuning uchun kechikkan ish fonda yakunlanadi
+func withTimeout[T any](timeout time.Duration, fn func() (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-timer.C:
+		var zero T
+		return zero, ErrTimeout
+	}
+}
@@ -0,0 +1,72 @@
+package snippet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		mode     wrapMode
+		wantLine int // Sintaksis xatosi kutilgan qator (0 - xato yo'q)
+	}{
+		{name: "full file", code: "package foo\n\nfunc F() {}", mode: wrapNone},
+		{name: "comment before package", code: "// Package foo\npackage foo", mode: wrapNone},
+		{name: "declarations", code: "import \"fmt\"\n\nfunc main() { fmt.Println(1) }", mode: wrapPackage},
+		{name: "statements", code: "x := 1\nfmt.Println(x)", mode: wrapMain},
+		{name: "statement error line", code: "x := 1\ny := )\nfmt.Println(x)", wantLine: 2},
+		{name: "declaration error line", code: "func f() {\n\treturn 1 +\n}", wantLine: 3},
+		{name: "package file error", code: "package foo\n\nfunc (", wantLine: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := parse(tt.code)
+			if tt.wantLine == 0 {
+				if err != nil {
+					t.Fatalf("parse error: %v", err)
+				}
+				if u.mode != tt.mode {
+					t.Fatalf("mode = %d, want %d", u.mode, tt.mode)
+				}
+				return
+			}
+			var syntax *SyntaxError
+			if !errors.As(err, &syntax) || len(syntax.Problems) == 0 {
+				t.Fatalf("parse error = %v, want SyntaxError", err)
+			}
+			if got := syntax.Problems[0].Line; got != tt.wantLine {
+				t.Fatalf("error line = %d (%v), want %d", got, syntax.Problems[0], tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestParseLimitsProblems(t *testing.T) {
+	code := strings.Repeat("x := \n", 3*maxProblems)
+	_, err := parse(code)
+	var syntax *SyntaxError
+	if !errors.As(err, &syntax) || len(syntax.Problems) > maxProblems {
+		t.Fatalf("parse error = %v, want at most %d problems", err, maxProblems)
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	limits := Limits{MaxBytes: 10}
+	tests := []struct {
+		code string
+		want error
+	}{
+		{"", ErrEmpty},
+		{" \n\t", ErrEmpty},
+		{"x := 1", nil},
+		{"x := 100000", ErrTooLarge},
+	}
+	for _, tt := range tests {
+		if err := checkSize(tt.code, limits); !errors.Is(err, tt.want) {
+			t.Errorf("checkSize(%q) = %v, want %v", tt.code, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltDB ichidagi asosiy bucket nomlari
+var (
+	bucketUsers    = []byte("users")
+	bucketChats    = []byte("chats")
+	bucketWarnings = []byte("warnings")
+	bucketSettings = []byte("settings")
+	bucketKV       = []byte("kv")
+)
+
+// BoltStore ma'lumotlarni bitta BoltDB faylida saqlovchi ombor
+// Tashqi ma'lumotlar bazasi serverini talab qilmaydi va bot bilan bir jarayonda ishlaydi
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore ko'rsatilgan yo'ldagi BoltDB faylini ochadi yoki yaratadi
+func NewBoltStore(path string) (*BoltStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("ma'lumotlar papkasini yaratishda xatolik: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("ma'lumotlar bazasini ochishda xatolik: %w", err)
+	}
+
+	// Asosiy bucketlarni oldindan yaratib qo'yamiz
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketUsers, bucketChats, bucketWarnings, bucketSettings, bucketKV} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ma'lumotlar bazasini tayyorlashda xatolik: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// idKey int64 identifikatorni kalit sifatida ishlatish uchun matnga aylantiradi
+func idKey(id int64) []byte {
+	return []byte(strconv.FormatInt(id, 10))
+}
+
+// warningPrefix guruh va foydalanuvchi ogohlantirishlari uchun umumiy kalit prefiksi
+func warningPrefix(chatID, userID int64) []byte {
+	return []byte(fmt.Sprintf("%d:%d:", chatID, userID))
+}
+
+// putJSON bucket ichiga qiymatni JSON ko'rinishida yozadi
+func putJSON(b *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+// getJSON bucket ichidagi JSON qiymatni o'qiydi
+func getJSON(b *bolt.Bucket, key []byte, v interface{}) error {
+	data := b.Get(key)
+	if data == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(data, v)
+}
+
+// SaveUser foydalanuvchi ma'lumotlarini saqlaydi, birinchi ko'rilgan vaqt saqlanib qoladi
+func (s *BoltStore) SaveUser(user User) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketUsers)
+		var existing User
+		if err := getJSON(b, idKey(user.ID), &existing); err == nil && !existing.FirstSeen.IsZero() {
+			user.FirstSeen = existing.FirstSeen
+		}
+		if user.FirstSeen.IsZero() {
+			user.FirstSeen = time.Now()
+		}
+		return putJSON(b, idKey(user.ID), user)
+	})
+}
+
+// GetUser foydalanuvchini ID bo'yicha qaytaradi
+func (s *BoltStore) GetUser(id int64) (User, error) {
+	var user User
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(bucketUsers), idKey(id), &user)
+	})
+	return user, err
+}
+
+// FindUser foydalanuvchini username bo'yicha qidiradi
+// Username bo'yicha indeks yo'q, shuning uchun barcha foydalanuvchilar ko'rib chiqiladi
+func (s *BoltStore) FindUser(username string) (User, error) {
+	username = strings.TrimPrefix(username, "@")
+	if username == "" {
+		return User{}, ErrNotFound
+	}
+	var found User
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketUsers).ForEach(func(k, v []byte) error {
+			var user User
+			if err := json.Unmarshal(v, &user); err != nil {
+				return err
+			}
+			if strings.EqualFold(user.UserName, username) && (found.ID == 0 || user.LastSeen.After(found.LastSeen)) {
+				found = user
+			}
+			return nil
+		})
+	})
+	if err == nil && found.ID == 0 {
+		err = ErrNotFound
+	}
+	return found, err
+}
+
+// SaveChat chat ma'lumotlarini saqlaydi, birinchi ko'rilgan vaqt saqlanib qoladi
+func (s *BoltStore) SaveChat(chat Chat) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketChats)
+		var existing Chat
+		if err := getJSON(b, idKey(chat.ID), &existing); err == nil && !existing.FirstSeen.IsZero() {
+			chat.FirstSeen = existing.FirstSeen
+		}
+		if chat.FirstSeen.IsZero() {
+			chat.FirstSeen = time.Now()
+		}
+		return putJSON(b, idKey(chat.ID), chat)
+	})
+}
+
+// GetChat chatni ID bo'yicha qaytaradi
+func (s *BoltStore) GetChat(id int64) (Chat, error) {
+	var chat Chat
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(bucketChats), idKey(id), &chat)
+	})
+	return chat, err
+}
+
+// ListChats barcha ma'lum chatlarni qaytaradi
+func (s *BoltStore) ListChats() ([]Chat, error) {
+	var chats []Chat
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketChats).ForEach(func(k, v []byte) error {
+			var chat Chat
+			if err := json.Unmarshal(v, &chat); err != nil {
+				return err
+			}
+			chats = append(chats, chat)
+			return nil
+		})
+	})
+	return chats, err
+}
+
+// AddWarning yangi ogohlantirishni saqlaydi
+// Kalit "chat:user:id" ko'rinishida bo'lib, id nol bilan to'ldiriladi, shunda yozuvlar vaqt tartibida saqlanadi
+func (s *BoltStore) AddWarning(w Warning) (Warning, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketWarnings)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		w.ID = int64(seq)
+		if w.CreatedAt.IsZero() {
+			w.CreatedAt = time.Now()
+		}
+		key := append(warningPrefix(w.ChatID, w.UserID), []byte(fmt.Sprintf("%020d", w.ID))...)
+		return putJSON(b, key, w)
+	})
+	return w, err
+}
+
+// ListWarnings foydalanuvchining guruhdagi ogohlantirishlarini qaytaradi
+func (s *BoltStore) ListWarnings(chatID, userID int64) ([]Warning, error) {
+	var warnings []Warning
+	prefix := warningPrefix(chatID, userID)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketWarnings).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var w Warning
+			if err := json.Unmarshal(v, &w); err != nil {
+				return err
+			}
+			warnings = append(warnings, w)
+		}
+		return nil
+	})
+	return warnings, err
+}
+
+// DeleteWarning ogohlantirishni o'chiradi
+func (s *BoltStore) DeleteWarning(chatID, userID, id int64) error {
+	key := append(warningPrefix(chatID, userID), []byte(fmt.Sprintf("%020d", id))...)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketWarnings)
+		if b.Get(key) == nil {
+			return ErrNotFound
+		}
+		return b.Delete(key)
+	})
+}
+
+// GetSetting chat sozlamasini qaytaradi
+func (s *BoltStore) GetSetting(chatID int64, key string) (string, error) {
+	var value string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucketSettings).Get([]byte(fmt.Sprintf("%d:%s", chatID, key)))
+		if data == nil {
+			return ErrNotFound
+		}
+		value = string(data)
+		return nil
+	})
+	return value, err
+}
+
+// SetSetting chat sozlamasini saqlaydi
+func (s *BoltStore) SetSetting(chatID int64, key, value string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSettings).Put([]byte(fmt.Sprintf("%d:%s", chatID, key)), []byte(value))
+	})
+}
+
+// Get kv ichidagi bucketdan qiymatni qaytaradi
+func (s *BoltStore) Get(bucket, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketKV).Bucket([]byte(bucket))
+		if b == nil {
+			return ErrNotFound
+		}
+		data := b.Get([]byte(key))
+		if data == nil {
+			return ErrNotFound
+		}
+		// BoltDB qaytargan bayt massivi faqat tranzaksiya davomida amal qiladi
+		value = append([]byte(nil), data...)
+		return nil
+	})
+	return value, err
+}
+
+// Put kv ichidagi bucketga qiymat yozadi, bucket mavjud bo'lmasa yaratiladi
+func (s *BoltStore) Put(bucket, key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(bucketKV).CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), value)
+	})
+}
+
+// Delete kv ichidagi bucketdan kalitni o'chiradi
+func (s *BoltStore) Delete(bucket, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketKV).Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// Scan kv ichidagi bucketning prefiks bilan boshlanuvchi kalitlarini aylanib chiqadi
+// fn tranzaksiya yopilgandan keyin chaqiriladi, shuning uchun uning ichida omborga yozish xavfsiz
+func (s *BoltStore) Scan(bucket, prefix string, fn func(key string, value []byte) error) error {
+	var keys []string
+	var values [][]byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketKV).Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		p := []byte(prefix)
+		c := b.Cursor()
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			keys = append(keys, string(k))
+			values = append(values, append([]byte(nil), v...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, k := range keys {
+		if err := fn(k, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close ma'lumotlar bazasi faylini yopadi
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
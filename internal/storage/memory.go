@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore barcha ma'lumotlarni xotirada saqlovchi ombor
+// Bot qayta ishga tushganda ma'lumotlar yo'qoladi, shuning uchun u asosan testlar va mahalliy sinovlar uchun mo'ljallangan
+type MemoryStore struct {
+	mu       sync.RWMutex
+	users    map[int64]User
+	chats    map[int64]Chat
+	warnings []Warning
+	nextWarn int64
+	settings map[int64]map[string]string
+	kv       map[string]map[string][]byte
+}
+
+// NewMemoryStore bo'sh xotiradagi ombor yaratadi
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:    make(map[int64]User),
+		chats:    make(map[int64]Chat),
+		nextWarn: 1,
+		settings: make(map[int64]map[string]string),
+		kv:       make(map[string]map[string][]byte),
+	}
+}
+
+// SaveUser foydalanuvchi ma'lumotlarini saqlaydi
+func (s *MemoryStore) SaveUser(user User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.users[user.ID]; ok && !existing.FirstSeen.IsZero() {
+		user.FirstSeen = existing.FirstSeen
+	}
+	if user.FirstSeen.IsZero() {
+		user.FirstSeen = time.Now()
+	}
+	s.users[user.ID] = user
+	return nil
+}
+
+// GetUser foydalanuvchini ID bo'yicha qaytaradi
+func (s *MemoryStore) GetUser(id int64) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+// FindUser foydalanuvchini username bo'yicha qidiradi
+func (s *MemoryStore) FindUser(username string) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	username = strings.TrimPrefix(username, "@")
+	if username == "" {
+		return User{}, ErrNotFound
+	}
+	var found User
+	for _, user := range s.users {
+		if strings.EqualFold(user.UserName, username) && (found.ID == 0 || user.LastSeen.After(found.LastSeen)) {
+			found = user
+		}
+	}
+	if found.ID == 0 {
+		return User{}, ErrNotFound
+	}
+	return found, nil
+}
+
+// SaveChat chat ma'lumotlarini saqlaydi
+func (s *MemoryStore) SaveChat(chat Chat) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.chats[chat.ID]; ok && !existing.FirstSeen.IsZero() {
+		chat.FirstSeen = existing.FirstSeen
+	}
+	if chat.FirstSeen.IsZero() {
+		chat.FirstSeen = time.Now()
+	}
+	s.chats[chat.ID] = chat
+	return nil
+}
+
+// GetChat chatni ID bo'yicha qaytaradi
+func (s *MemoryStore) GetChat(id int64) (Chat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chat, ok := s.chats[id]
+	if !ok {
+		return Chat{}, ErrNotFound
+	}
+	return chat, nil
+}
+
+// ListChats barcha ma'lum chatlarni ID bo'yicha tartiblab qaytaradi
+func (s *MemoryStore) ListChats() ([]Chat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chats := make([]Chat, 0, len(s.chats))
+	for _, chat := range s.chats {
+		chats = append(chats, chat)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i].ID < chats[j].ID })
+	return chats, nil
+}
+
+// AddWarning yangi ogohlantirishni saqlaydi
+func (s *MemoryStore) AddWarning(w Warning) (Warning, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.ID = s.nextWarn
+	s.nextWarn++
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = time.Now()
+	}
+	s.warnings = append(s.warnings, w)
+	return w, nil
+}
+
+// ListWarnings foydalanuvchining guruhdagi ogohlantirishlarini qaytaradi
+func (s *MemoryStore) ListWarnings(chatID, userID int64) ([]Warning, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Warning
+	for _, w := range s.warnings {
+		if w.ChatID == chatID && w.UserID == userID {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+// DeleteWarning ogohlantirishni o'chiradi
+func (s *MemoryStore) DeleteWarning(chatID, userID, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, w := range s.warnings {
+		if w.ID == id && w.ChatID == chatID && w.UserID == userID {
+			s.warnings = append(s.warnings[:i], s.warnings[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// GetSetting chat sozlamasini qaytaradi
+func (s *MemoryStore) GetSetting(chatID int64, key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.settings[chatID][key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// SetSetting chat sozlamasini saqlaydi
+func (s *MemoryStore) SetSetting(chatID int64, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.settings[chatID] == nil {
+		s.settings[chatID] = make(map[string]string)
+	}
+	s.settings[chatID][key] = value
+	return nil
+}
+
+// Get bucket ichidagi qiymatni qaytaradi
+func (s *MemoryStore) Get(bucket, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.kv[bucket][key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Put bucket ichiga qiymat yozadi
+func (s *MemoryStore) Put(bucket, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.kv[bucket] == nil {
+		s.kv[bucket] = make(map[string][]byte)
+	}
+	s.kv[bucket][key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete bucket ichidagi kalitni o'chiradi
+func (s *MemoryStore) Delete(bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.kv[bucket], key)
+	return nil
+}
+
+// Scan bucket ichidagi prefiks bilan boshlanuvchi kalitlarni tartib bilan aylanib chiqadi
+func (s *MemoryStore) Scan(bucket, prefix string, fn func(key string, value []byte) error) error {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.kv[bucket]))
+	values := make(map[string][]byte, len(s.kv[bucket]))
+	for k, v := range s.kv[bucket] {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+			values[k] = append([]byte(nil), v...)
+		}
+	}
+	s.mu.RUnlock()
+
+	// fn ichida omborga yozish mumkin bo'lishi uchun qulf ochilgandan keyin chaqiramiz
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn(k, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close xotiradagi ombor uchun hech narsa qilmaydi
+func (s *MemoryStore) Close() error {
+	return nil
+}
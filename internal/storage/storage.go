@@ -0,0 +1,121 @@
+// Package storage bot holatini doimiy saqlash uchun mo'ljallangan
+// Bu paket foydalanuvchilar, guruhlar, ogohlantirishlar, sozlamalar va kalit/qiymat ma'lumotlari uchun
+// yagona Store interfeysini va uning fayl hamda xotiradagi implementatsiyalarini taqdim etadi
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotFound so'ralgan yozuv omborda mavjud emasligini bildiradi
+var ErrNotFound = errors.New("storage: yozuv topilmadi")
+
+// User bot bilan muloqot qilgan foydalanuvchi haqidagi ma'lumot
+type User struct {
+	ID           int64     `json:"id"`            // Telegram foydalanuvchi identifikatori
+	UserName     string    `json:"username"`      // Foydalanuvchi nomi (@ belgisisiz)
+	FirstName    string    `json:"first_name"`    // Ismi
+	LastName     string    `json:"last_name"`     // Familiyasi
+	LanguageCode string    `json:"language_code"` // Telegram mijozidagi til kodi
+	FirstSeen    time.Time `json:"first_seen"`    // Bot foydalanuvchini birinchi ko'rgan vaqt
+	LastSeen     time.Time `json:"last_seen"`     // Oxirgi faollik vaqti
+}
+
+// Chat bot a'zo bo'lgan yoki bot bilan muloqot qilingan chat haqidagi ma'lumot
+type Chat struct {
+	ID        int64     `json:"id"`         // Telegram chat identifikatori
+	Type      string    `json:"type"`       // private, group, supergroup yoki channel
+	Title     string    `json:"title"`      // Guruh nomi
+	UserName  string    `json:"username"`   // Ommaviy guruhlar uchun @username
+	FirstSeen time.Time `json:"first_seen"` // Bot chatni birinchi ko'rgan vaqt
+	LastSeen  time.Time `json:"last_seen"`  // Oxirgi faollik vaqti
+}
+
+// Warning guruh a'zosiga berilgan bitta ogohlantirish haqidagi yozuv
+// Har bir yozuv kim, qachon, qaysi guruhda va nima sababdan ogohlantirilganini saqlaydi
+type Warning struct {
+	ID        int64     `json:"id"`         // Ogohlantirishning noyob identifikatori
+	ChatID    int64     `json:"chat_id"`    // Ogohlantirish berilgan guruh
+	UserID    int64     `json:"user_id"`    // Ogohlantirilgan foydalanuvchi
+	IssuerID  int64     `json:"issuer_id"`  // Ogohlantirishni bergan admin
+	Reason    string    `json:"reason"`     // Ogohlantirish sababi
+	CreatedAt time.Time `json:"created_at"` // Ogohlantirish berilgan vaqt
+}
+
+// Store bot holatini saqlash uchun umumiy interfeys
+// Handlerlar va bot logikasi faqat shu interfeys orqali ishlaydi, shuning uchun implementatsiyani sozlamalar orqali almashtirish mumkin
+type Store interface {
+	// SaveUser foydalanuvchi ma'lumotlarini saqlaydi yoki yangilaydi
+	SaveUser(user User) error
+	// GetUser foydalanuvchini ID bo'yicha qaytaradi, topilmasa ErrNotFound
+	GetUser(id int64) (User, error)
+	// FindUser foydalanuvchini @username bo'yicha (katta-kichik harflarni farqlamasdan) qaytaradi, topilmasa ErrNotFound
+	// Bir nechta yozuv mos kelsa eng oxirgi faol bo'lgani olinadi
+	FindUser(username string) (User, error)
+
+	// SaveChat chat ma'lumotlarini saqlaydi yoki yangilaydi
+	SaveChat(chat Chat) error
+	// GetChat chatni ID bo'yicha qaytaradi, topilmasa ErrNotFound
+	GetChat(id int64) (Chat, error)
+	// ListChats barcha ma'lum chatlarni qaytaradi
+	ListChats() ([]Chat, error)
+
+	// AddWarning yangi ogohlantirishni saqlaydi va unga berilgan ID bilan qaytaradi
+	AddWarning(w Warning) (Warning, error)
+	// ListWarnings foydalanuvchining guruhdagi ogohlantirishlarini vaqt bo'yicha tartibda qaytaradi
+	ListWarnings(chatID, userID int64) ([]Warning, error)
+	// DeleteWarning ogohlantirishni ID bo'yicha o'chiradi
+	DeleteWarning(chatID, userID, id int64) error
+
+	// GetSetting chat sozlamasini qaytaradi, topilmasa ErrNotFound
+	GetSetting(chatID int64, key string) (string, error)
+	// SetSetting chat sozlamasini saqlaydi
+	SetSetting(chatID int64, key, value string) error
+
+	// Get bucket ichidagi kalit qiymatini qaytaradi, topilmasa ErrNotFound
+	Get(bucket, key string) ([]byte, error)
+	// Put bucket ichiga kalit/qiymat juftligini yozadi
+	Put(bucket, key string, value []byte) error
+	// Delete bucket ichidagi kalitni o'chiradi
+	Delete(bucket, key string) error
+	// Scan bucket ichidagi prefiks bilan boshlanuvchi barcha kalitlarni tartib bilan aylanib chiqadi
+	Scan(bucket, prefix string, fn func(key string, value []byte) error) error
+
+	// Close omborni yopadi va resurslarni bo'shatadi
+	Close() error
+}
+
+// Open sozlamalarda ko'rsatilgan drayver asosida omborni ochadi
+// Qo'llab-quvvatlanadigan drayverlar: "bolt" (fayl) va "memory" (xotira, testlar uchun)
+func Open(driver, path string) (Store, error) {
+	switch strings.ToLower(driver) {
+	case "", "bolt":
+		return NewBoltStore(path)
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("noma'lum storage drayveri: %s", driver)
+	}
+}
+
+// GetJSON bucket ichidagi qiymatni JSON sifatida o'qib v ga yozadi
+func GetJSON(s Store, bucket, key string, v interface{}) error {
+	data, err := s.Get(bucket, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// PutJSON v ni JSON ko'rinishida bucket ichiga yozadi
+func PutJSON(s Store, bucket, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Put(bucket, key, data)
+}
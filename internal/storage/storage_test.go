@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Har ikki implementatsiya bir xil shartnomaga bo'ysunishi kerak
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemoryStore() },
+		"bolt": func(t *testing.T) Store {
+			s, err := NewBoltStore(filepath.Join(t.TempDir(), "bot.db"))
+			if err != nil {
+				t.Fatalf("NewBoltStore: %v", err)
+			}
+			return s
+		},
+	}
+	tests := map[string]func(t *testing.T, s Store){
+		"KeyValue": testKeyValue,
+		"Scan":     testScan,
+		"Warnings": testWarnings,
+		"Users":    testUsers,
+		"Settings": testSettings,
+	}
+	for storeName, open := range stores {
+		for testName, fn := range tests {
+			t.Run(storeName+"/"+testName, func(t *testing.T) {
+				s := open(t)
+				defer s.Close()
+				fn(t, s)
+			})
+		}
+	}
+}
+
+func testKeyValue(t *testing.T, s Store) {
+	if _, err := s.Get("b", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(missing) = %v, want ErrNotFound", err)
+	}
+	value := []byte("one")
+	if err := s.Put("b", "k", value); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// Ombor chaqiruvchining massivini saqlab qolmasligi kerak
+	value[0] = 'X'
+	got, err := s.Get("b", "k")
+	if err != nil || string(got) != "one" {
+		t.Fatalf("Get = %q, %v; want \"one\"", got, err)
+	}
+	if _, err := s.Get("other", "k"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get from other bucket = %v, want ErrNotFound", err)
+	}
+	if err := s.Put("b", "k", []byte("two")); err != nil {
+		t.Fatalf("Put overwrite: %v", err)
+	}
+	if got, _ := s.Get("b", "k"); string(got) != "two" {
+		t.Fatalf("Get after overwrite = %q, want \"two\"", got)
+	}
+	if err := s.Delete("b", "k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("b", "k"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("nobucket", "k"); err != nil {
+		t.Fatalf("Delete in missing bucket: %v", err)
+	}
+
+	var v struct{ N int }
+	if err := PutJSON(s, "b", "json", struct{ N int }{7}); err != nil {
+		t.Fatalf("PutJSON: %v", err)
+	}
+	if err := GetJSON(s, "b", "json", &v); err != nil || v.N != 7 {
+		t.Fatalf("GetJSON = %+v, %v; want N=7", v, err)
+	}
+}
+
+func testScan(t *testing.T, s Store) {
+	for _, k := range []string{"a:3", "b:1", "a:1", "a:2", "a"} {
+		if err := s.Put("scan", k, []byte(k)); err != nil {
+			t.Fatalf("Put(%s): %v", k, err)
+		}
+	}
+	var keys []string
+	err := s.Scan("scan", "a:", func(key string, value []byte) error {
+		if key != string(value) {
+			t.Errorf("Scan value for %s = %q", key, value)
+		}
+		keys = append(keys, key)
+		// Aylanish paytida yozish qulflanib qolmasligi kerak
+		return s.Put("scan", "copy:"+key, value)
+	})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if want := []string{"a:1", "a:2", "a:3"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("Scan keys = %v, want %v", keys, want)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = s.Scan("scan", "", func(string, []byte) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("Scan with error = %v after %d calls, want stop after 1", err, calls)
+	}
+	if err := s.Scan("empty", "", func(string, []byte) error {
+		t.Fatal("callback called for missing bucket")
+		return nil
+	}); err != nil {
+		t.Fatalf("Scan missing bucket: %v", err)
+	}
+}
+
+func testWarnings(t *testing.T, s Store) {
+	const chat, user = -100, 42
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ids []int64
+	for i, reason := range []string{"first", "second", "third"} {
+		w, err := s.AddWarning(Warning{ChatID: chat, UserID: user, IssuerID: 1, Reason: reason, CreatedAt: base.Add(time.Duration(i) * time.Minute)})
+		if err != nil {
+			t.Fatalf("AddWarning: %v", err)
+		}
+		if w.ID == 0 {
+			t.Fatal("AddWarning returned zero ID")
+		}
+		ids = append(ids, w.ID)
+	}
+	if _, err := s.AddWarning(Warning{ChatID: chat, UserID: user + 1, Reason: "other user"}); err != nil {
+		t.Fatalf("AddWarning: %v", err)
+	}
+	other, err := s.AddWarning(Warning{ChatID: chat - 1, UserID: user, Reason: "other chat"})
+	if err != nil {
+		t.Fatalf("AddWarning: %v", err)
+	}
+	if other.CreatedAt.IsZero() {
+		t.Error("AddWarning did not set CreatedAt")
+	}
+
+	list, err := s.ListWarnings(chat, user)
+	if err != nil {
+		t.Fatalf("ListWarnings: %v", err)
+	}
+	var reasons []string
+	for _, w := range list {
+		reasons = append(reasons, w.Reason)
+	}
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(reasons, want) {
+		t.Fatalf("ListWarnings reasons = %v, want %v", reasons, want)
+	}
+
+	if err := s.DeleteWarning(chat, user, ids[1]); err != nil {
+		t.Fatalf("DeleteWarning: %v", err)
+	}
+	if err := s.DeleteWarning(chat, user, ids[1]); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("DeleteWarning twice = %v, want ErrNotFound", err)
+	}
+	if err := s.DeleteWarning(chat, user+1, ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("DeleteWarning for wrong user = %v, want ErrNotFound", err)
+	}
+	list, _ = s.ListWarnings(chat, user)
+	if len(list) != 2 || list[0].ID != ids[0] || list[1].ID != ids[2] {
+		t.Fatalf("ListWarnings after delete = %+v", list)
+	}
+	if list, _ := s.ListWarnings(chat, 999); len(list) != 0 {
+		t.Fatalf("ListWarnings for unknown user = %+v", list)
+	}
+}
+
+func testUsers(t *testing.T, s Store) {
+	if _, err := s.GetUser(1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetUser(missing) = %v, want ErrNotFound", err)
+	}
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := []User{
+		{ID: 1, UserName: "Gopher", FirstSeen: first, LastSeen: first},
+		{ID: 2, UserName: "gopher", LastSeen: first.Add(time.Hour)},
+		{ID: 3, UserName: "rustacean", LastSeen: first.Add(2 * time.Hour)},
+		{ID: 4, LastSeen: first.Add(3 * time.Hour)},
+	}
+	for _, u := range users {
+		if err := s.SaveUser(u); err != nil {
+			t.Fatalf("SaveUser: %v", err)
+		}
+	}
+
+	// Qayta saqlashda birinchi ko'rilgan vaqt o'zgarmaydi
+	if err := s.SaveUser(User{ID: 1, UserName: "Gopher", FirstName: "Go", LastSeen: first.Add(time.Minute)}); err != nil {
+		t.Fatalf("SaveUser: %v", err)
+	}
+	got, err := s.GetUser(1)
+	if err != nil || got.FirstName != "Go" || !got.FirstSeen.Equal(first) {
+		t.Fatalf("GetUser(1) = %+v, %v", got, err)
+	}
+
+	tests := []struct {
+		username string
+		wantID   int64
+	}{
+		{"@GOPHER", 2}, // Eng oxirgi faol foydalanuvchi olinadi
+		{"rustacean", 3},
+		{"@rustacean", 3},
+		{"@nobody", 0},
+		{"@", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		got, err := s.FindUser(tt.username)
+		if tt.wantID == 0 {
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("FindUser(%q) = %+v, %v; want ErrNotFound", tt.username, got, err)
+			}
+			continue
+		}
+		if err != nil || got.ID != tt.wantID {
+			t.Errorf("FindUser(%q) = %d, %v; want %d", tt.username, got.ID, err, tt.wantID)
+		}
+	}
+
+	if err := s.SaveChat(Chat{ID: 5, Title: "b"}); err != nil {
+		t.Fatalf("SaveChat: %v", err)
+	}
+	if err := s.SaveChat(Chat{ID: -5, Title: "a"}); err != nil {
+		t.Fatalf("SaveChat: %v", err)
+	}
+	if chat, err := s.GetChat(5); err != nil || chat.Title != "b" || chat.FirstSeen.IsZero() {
+		t.Fatalf("GetChat(5) = %+v, %v", chat, err)
+	}
+	chats, err := s.ListChats()
+	if err != nil || len(chats) != 2 {
+		t.Fatalf("ListChats = %+v, %v", chats, err)
+	}
+}
+
+func testSettings(t *testing.T, s Store) {
+	if _, err := s.GetSetting(1, "lang"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetSetting(missing) = %v, want ErrNotFound", err)
+	}
+	if err := s.SetSetting(1, "lang", "ru"); err != nil {
+		t.Fatalf("SetSetting: %v", err)
+	}
+	if err := s.SetSetting(2, "lang", "en"); err != nil {
+		t.Fatalf("SetSetting: %v", err)
+	}
+	if got, err := s.GetSetting(1, "lang"); err != nil || got != "ru" {
+		t.Fatalf("GetSetting(1) = %q, %v; want ru", got, err)
+	}
+	if err := s.SetSetting(1, "lang", ""); err != nil {
+		t.Fatalf("SetSetting empty: %v", err)
+	}
+	if got, err := s.GetSetting(1, "lang"); err != nil || got != "" {
+		t.Fatalf("GetSetting after clear = %q, %v; want empty", got, err)
+	}
+}
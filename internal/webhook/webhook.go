@@ -3,10 +3,15 @@
 package webhook
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"tg-bot/pkg/logger"
 	"time"
 
@@ -19,17 +24,62 @@ type Config interface {
 	WebhookURL() string
 	// WebhookPort portini qaytaradi
 	WebhookPort() string
+	// WebhookPath endpoint yo'lini qaytaradi, bo'sh bo'lsa URL dagi yo'l yoki bot tokeni ishlatiladi
+	WebhookPath() string
+	// WebhookSecretToken so'rovlarni tekshirish uchun maxfiy kalitni qaytaradi
+	WebhookSecretToken() string
 	// GetTelegramToken Telegram bot tokenini qaytaradi
 	GetTelegramToken() string
 }
 
+// secretTokenHeader Telegram har bir webhook so'rovida maxfiy kalitni yuboradigan sarlavha
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
 // Server webhook serverini yaratish va boshqarish uchun tuzilma
 type Server struct {
-	bot        *tgbotapi.BotAPI
-	config     Config
-	logger     *logger.Logger
-	httpServer *http.Server
-	updateChan chan tgbotapi.Update
+	bot         *tgbotapi.BotAPI
+	config      Config
+	logger      *logger.Logger
+	httpServer  *http.Server
+	updateChan  chan tgbotapi.Update
+	secretToken string
+	done        chan struct{}      // To'xtatish boshlanganda yopiladi
+	stats       func() interface{} // /health javobiga qo'shiladigan qo'shimcha statistika
+}
+
+// SetStatsProvider /health endpointi javobiga qo'shiladigan statistika manbasini o'rnatadi
+func (s *Server) SetStatsProvider(fn func() interface{}) {
+	s.stats = fn
+}
+
+// Handle webhook serveriga qo'shimcha HTTP endpoint qo'shadi, u Start dan oldin chaqirilishi kerak
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	http.Handle(pattern, handler)
+	s.logger.Infof("Qo'shimcha endpoint: %s", pattern)
+}
+
+// generateSecretToken Telegram talablariga mos tasodifiy maxfiy kalit yaratadi
+// Kalit faqat 0-9 va a-f belgilaridan iborat bo'lib, 64 belgidan oshmaydi
+func generateSecretToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// redact matndagi bot tokenini yashiradi, shunda u loglarga tushib qolmaydi
+func (s *Server) redact(text string) string {
+	if s.bot.Token == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, s.bot.Token, "<token>")
+}
+
+// validSecret so'rov sarlavhasidagi maxfiy kalitni doimiy vaqtda solishtiradi
+func (s *Server) validSecret(r *http.Request) bool {
+	got := r.Header.Get(secretTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(s.secretToken)) == 1
 }
 
 // NewServer yangi webhook server yaratadi
@@ -39,6 +89,7 @@ func NewServer(bot *tgbotapi.BotAPI, config Config, log *logger.Logger) *Server
 		config:     config,
 		logger:     log,
 		updateChan: make(chan tgbotapi.Update, 100), // Update kanalini bufer bilan yaratamiz
+		done:       make(chan struct{}),
 	}
 }
 
@@ -65,31 +116,43 @@ func (s *Server) Setup() error {
 		return fmt.Errorf("invalid webhook URL: %w", err)
 	}
 
-	// Append bot token as path if not already present
-	if len(webhookURL.Path) <= 1 {
+	// Endpoint yo'lini aniqlash: sozlamalardagi yo'l, URL dagi yo'l yoki eski usulda bot tokeni
+	if path := s.config.WebhookPath(); path != "" {
+		webhookURL.Path = "/" + strings.TrimPrefix(path, "/")
+	} else if len(webhookURL.Path) <= 1 {
 		// Make sure the path includes the token
 		webhookURL.Path = "/" + s.bot.Token
 	}
 
-	// Log the complete webhook URL
-	s.logger.Infof("Setting webhook URL to: %s", webhookURL.String())
+	// Maxfiy kalitni sozlamalardan olish yoki yangisini yaratish
+	s.secretToken = s.config.WebhookSecretToken()
+	if s.secretToken == "" {
+		s.secretToken, err = generateSecretToken()
+		if err != nil {
+			return fmt.Errorf("maxfiy kalit yaratishda xatolik: %w", err)
+		}
+	}
+
+	// Log the webhook URL without exposing the bot token
+	s.logger.Infof("Setting webhook URL to: %s", s.redact(webhookURL.String()))
 
-	// Create webhook config without certificate for domains with valid SSL
-	webhookConfig := tgbotapi.WebhookConfig{
-		URL:            webhookURL,
-		MaxConnections: 40,
+	// tgbotapi.WebhookConfig secret_token maydonini qo'llab-quvvatlamaydi, shuning uchun so'rovni o'zimiz yig'amiz
+	params := tgbotapi.Params{
+		"url":          webhookURL.String(),
+		"secret_token": s.secretToken,
 	}
+	params.AddNonZero("max_connections", 40)
 
 	// Webhook ni o'rnatish
 	s.logger.Info("Registering webhook with Telegram...")
-	resp, err := s.bot.Request(webhookConfig)
+	resp, err := s.bot.MakeRequest("setWebhook", params)
 	if err != nil {
-		s.logger.Errorf("Webhook registration error: %v", err)
-		return fmt.Errorf("webhook o'rnatishda xatolik: %w", err)
+		s.logger.Errorf("Webhook registration error: %v", s.redact(err.Error()))
+		return fmt.Errorf("webhook o'rnatishda xatolik: %s", s.redact(err.Error()))
 	}
 
-	// Log the full response
-	s.logger.Infof("Webhook registration response: %v", resp)
+	// Log the response
+	s.logger.Infof("Webhook registration response: %s", resp.Description)
 
 	// Webhook info ni tekshirish
 	info, err := s.bot.GetWebhookInfo()
@@ -98,7 +161,7 @@ func (s *Server) Setup() error {
 	}
 
 	// Log webhook info for debugging
-	s.logger.Infof("Webhook info: %+v", info)
+	s.logger.Infof("Webhook info: %s", s.redact(fmt.Sprintf("%+v", info)))
 
 	// Webhook holatini tekshirish
 	if info.LastErrorDate != 0 {
@@ -107,35 +170,11 @@ func (s *Server) Setup() error {
 		s.logger.Info("Webhook successfully registered with no errors!")
 	}
 
-	// Webhook manzili va endpointini yaratish
-	webhookEndpoint := "/" + s.bot.Token
+	// Webhook endpointi Telegram'ga berilgan URL yo'li bilan bir xil bo'lishi kerak
+	webhookEndpoint := webhookURL.Path
 
 	// HTTP handler sozlash
-	http.HandleFunc(webhookEndpoint, func(w http.ResponseWriter, r *http.Request) {
-		// Log all incoming requests
-		s.logger.Infof("Received webhook request from: %s %s", r.RemoteAddr, r.URL.Path)
-
-		if r.Method != http.MethodPost {
-			s.logger.Warnf("Rejected non-POST request: %s", r.Method)
-			http.Error(w, "Faqat POST so'rovlari qabul qilinadi", http.StatusMethodNotAllowed)
-			return
-		}
-
-		// Telegram update ni qabul qilish
-		update, err := s.bot.HandleUpdate(r)
-		if err != nil {
-			s.logger.Errorf("Update ni qayta ishlashda xatolik: %v", err)
-			http.Error(w, "Update ni qayta ishlashda xatolik", http.StatusBadRequest)
-			return
-		}
-
-		// Log successful update
-		s.logger.Infof("Received valid update ID: %d", update.UpdateID)
-
-		// Update ni kanalga yuborish
-		s.updateChan <- *update
-		w.WriteHeader(http.StatusOK)
-	})
+	http.HandleFunc(webhookEndpoint, s.handleUpdate)
 
 	// Add a health check endpoint to verify server is working
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -151,7 +190,7 @@ func (s *Server) Setup() error {
 				"id":       s.bot.Self.ID,
 			},
 			"webhook": map[string]interface{}{
-				"url":         webhookInfo.URL,
+				"url":         s.redact(webhookInfo.URL),
 				"is_set":      webhookInfo.URL != "",
 				"last_error":  webhookInfo.LastErrorMessage,
 				"error_date":  webhookInfo.LastErrorDate,
@@ -161,6 +200,10 @@ func (s *Server) Setup() error {
 			},
 		}
 
+		if s.stats != nil {
+			responseData["dispatcher"] = s.stats()
+		}
+
 		// Set JSON content type
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -177,13 +220,58 @@ func (s *Server) Setup() error {
 	})
 
 	// Info chiqarish
-	s.logger.Infof("Webhook registered with URL: %s", webhookURL.String())
-	s.logger.Infof("Webhook endpoint listening on: %s", webhookEndpoint)
+	s.logger.Infof("Webhook registered with URL: %s", s.redact(webhookURL.String()))
+	s.logger.Infof("Webhook endpoint listening on: %s", s.redact(webhookEndpoint))
 	s.logger.Info("Health check endpoint available at: /health")
 
 	return nil
 }
 
+// handleUpdate Telegram yuborgan yangilanishni tekshiradi va Updates kanaliga uzatadi
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	// Log all incoming requests
+	s.logger.Infof("Received webhook request from: %s %s", r.RemoteAddr, s.redact(r.URL.Path))
+
+	if r.Method != http.MethodPost {
+		s.logger.Warnf("Rejected non-POST request: %s", r.Method)
+		http.Error(w, "Faqat POST so'rovlari qabul qilinadi", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// So'rov haqiqatan Telegram'dan kelganini maxfiy kalit orqali tekshirish
+	if !s.validSecret(r) {
+		s.logger.Warnf("Rejected webhook request with invalid secret token from: %s", r.RemoteAddr)
+		http.Error(w, "Ruxsat berilmagan", http.StatusUnauthorized)
+		return
+	}
+
+	// Telegram update ni qabul qilish
+	update, err := s.bot.HandleUpdate(r)
+	if err != nil {
+		s.logger.Errorf("Update ni qayta ishlashda xatolik: %v", err)
+		http.Error(w, "Update ni qayta ishlashda xatolik", http.StatusBadRequest)
+		return
+	}
+
+	// Log successful update
+	s.logger.Infof("Received valid update ID: %d", update.UpdateID)
+
+	// Server to'xtatilayotgan bo'lsa, Telegram update ni keyinroq qayta yuborishi uchun 503 qaytaramiz.
+	// Kanalda joy bo'lsa ham select tasodifiy tanlamasligi uchun to'xtatish avval tekshiriladi
+	select {
+	case <-s.done:
+		http.Error(w, "Server to'xtatilmoqda", http.StatusServiceUnavailable)
+		return
+	default:
+	}
+	select {
+	case s.updateChan <- *update:
+		w.WriteHeader(http.StatusOK)
+	case <-s.done:
+		http.Error(w, "Server to'xtatilmoqda", http.StatusServiceUnavailable)
+	}
+}
+
 // Start webhook serverni ishga tushiradi
 func (s *Server) Start() error {
 	// Webhook portini olish
@@ -220,10 +308,19 @@ func (s *Server) Updates() <-chan tgbotapi.Update {
 }
 
 // Stop webhook serverni to'xtatadi
-func (s *Server) Stop() error {
-	if s.httpServer != nil {
-		s.logger.Info("Webhook server to'xtatilmoqda...")
-		return s.httpServer.Close()
+// Yangi so'rovlar qabul qilinmaydi, ishlayotgan so'rovlar esa ctx muddati tugagunicha kutiladi
+// Server to'liq to'xtagach Updates kanali yopiladi
+func (s *Server) Stop(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+
+	s.logger.Info("Webhook server to'xtatilmoqda...")
+	close(s.done)
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		// Ba'zi so'rovlar hali ishlayotgan bo'lishi mumkin, shuning uchun kanalni yopmaymiz
+		return err
 	}
+	close(s.updateChan)
 	return nil
 }
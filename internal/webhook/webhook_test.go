@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tg-bot/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const testSecret = "0123456789abcdef"
+
+// newTestServer tarmoqqa ulanmaydigan, maxfiy kaliti o'rnatilgan server yaratadi
+func newTestServer() *Server {
+	s := NewServer(&tgbotapi.BotAPI{Token: "123:abc"}, nil, logger.New("error"))
+	s.secretToken = testSecret
+	return s
+}
+
+// post webhook handleriga yangilanish yuboradi
+func post(s *Server, method, secret, body string) int {
+	r := httptest.NewRequest(method, "/telegram", strings.NewReader(body))
+	if secret != "" {
+		r.Header.Set(secretTokenHeader, secret)
+	}
+	w := httptest.NewRecorder()
+	s.handleUpdate(w, r)
+	return w.Code
+}
+
+func TestHandleUpdateSecret(t *testing.T) {
+	const update = `{"update_id": 7, "message": {"message_id": 1, "text": "salom"}}`
+	tests := []struct {
+		name   string
+		method string
+		secret string
+		body   string
+		want   int
+	}{
+		{name: "missing secret", method: http.MethodPost, body: update, want: http.StatusUnauthorized},
+		{name: "wrong secret", method: http.MethodPost, secret: "wrong", body: update, want: http.StatusUnauthorized},
+		{name: "secret prefix", method: http.MethodPost, secret: testSecret[:8], body: update, want: http.StatusUnauthorized},
+		{name: "not POST", method: http.MethodGet, secret: testSecret, want: http.StatusMethodNotAllowed},
+		{name: "bad body", method: http.MethodPost, secret: testSecret, body: "{", want: http.StatusBadRequest},
+		{name: "valid", method: http.MethodPost, secret: testSecret, body: update, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer()
+			if got := post(s, tt.method, tt.secret, tt.body); got != tt.want {
+				t.Fatalf("status = %d, want %d", got, tt.want)
+			}
+			// Faqat tekshiruvdan o'tgan yangilanish kanalga tushadi
+			select {
+			case u := <-s.Updates():
+				if tt.want != http.StatusOK || u.UpdateID != 7 {
+					t.Fatalf("unexpected update %d", u.UpdateID)
+				}
+			default:
+				if tt.want == http.StatusOK {
+					t.Fatal("update was not queued")
+				}
+			}
+		})
+	}
+}
+
+func TestHandleUpdateShutdown(t *testing.T) {
+	s := newTestServer()
+	close(s.done)
+	// Kanalda joy bo'lsa ham to'xtatilayotgan server yangilanishni qabul qilmaydi
+	for i := 0; i < 20; i++ {
+		if got := post(s, http.MethodPost, testSecret, `{"update_id": 1}`); got != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", got, http.StatusServiceUnavailable)
+		}
+	}
+	if n := len(s.Updates()); n != 0 {
+		t.Fatalf("%d updates queued during shutdown", n)
+	}
+}
@@ -6,12 +6,21 @@ Bu yerda loyiha ishga tushiriladi.
 package main
 
 import (
+	"context"
+
 	"tg-bot/cmd/bot"
 	"tg-bot/internal/config"
+	"tg-bot/internal/sandbox"
 	"tg-bot/pkg/logger"
 )
 
 func main() {
+	// /run sandbox'i foydalanuvchi dasturini ishga tushirish uchun botning o'zini qayta chaqiradi
+	if sandbox.IsChild() {
+		sandbox.ChildMain()
+		return
+	}
+
 	// Konfiguratsiyani yuklash
 	cfg := config.LoadConfig()
 
@@ -20,5 +29,5 @@ func main() {
 	log.Info("Bot ishga tushmoqda...")
 
 	// Bot funksiyasini chaqirish
-	bot.RunBot(cfg, log)
+	bot.RunBot(context.Background(), cfg, log)
 }